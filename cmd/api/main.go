@@ -1,15 +1,29 @@
 package main
 
 import (
-	"net/http"
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/vynazevedo/go-modular-monolith/internal/modules/user"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
 	"github.com/vynazevedo/go-modular-monolith/internal/shared/config"
 	"github.com/vynazevedo/go-modular-monolith/internal/shared/database"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/events"
 	httpHandler "github.com/vynazevedo/go-modular-monolith/internal/shared/http"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/idempotency"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/jwtauth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/mail"
 	"github.com/vynazevedo/go-modular-monolith/internal/shared/middleware"
 	"github.com/vynazevedo/go-modular-monolith/internal/shared/module"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/server"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/validation"
 	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
 	"gorm.io/gorm"
 )
@@ -25,6 +39,7 @@ func main() {
 
 	gin.SetMode(cfg.Server.Mode)
 	logger.Infof("Gin mode set to: %s", cfg.Server.Mode)
+	validation.Setup()
 
 	db, err := database.Connect(cfg)
 	if err != nil {
@@ -39,12 +54,83 @@ func main() {
 		logger.Info("Database migrations completed successfully")
 	}
 
-	userModuleSetup := func(db *gorm.DB) module.Module {
-		return user.NewModule(db)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	jwtConfig := jwtauth.Config{
+		Issuer:              cfg.JWT.Issuer,
+		Audience:            cfg.JWT.Audience,
+		Algorithms:          cfg.JWT.Algorithms,
+		ClockSkew:           cfg.JWT.ClockSkew,
+		HMACSecret:          cfg.JWT.HMACSecret,
+		JWKSFile:            cfg.JWT.JWKSFile,
+		JWKSURL:             cfg.JWT.JWKSURL,
+		JWKSRefreshInterval: cfg.JWT.JWKSRefreshInterval,
+		SigningKeyFile:      cfg.JWT.SigningKeyFile,
+		SigningKeyID:        cfg.JWT.SigningKeyID,
+	}
+	tokens, err := jwtauth.NewVerifier(jwtConfig)
+	if err != nil {
+		logger.Fatalf("Failed to configure JWT authentication: %v", err)
+	}
+	if !tokens.Enabled() {
+		logger.Info("JWT authentication disabled: no signing key configured")
+	}
+	signer, err := jwtauth.NewSigner(jwtConfig)
+	if err != nil {
+		logger.Fatalf("Failed to configure JWT signing: %v", err)
+	}
+	if !signer.Enabled() {
+		logger.Info("Password login disabled: set JWT_SIGNING_KEY_FILE or JWT_HMAC_SECRET to issue tokens")
+	}
+
+	registry := module.NewRegistry()
+	registry.Register(modules.Definitions()...)
+	registry.Supply(auth.TokenVerifierContract, tokens)
+	registry.Supply(auth.TokenIssuerContract, signer)
+
+	bus := events.NewBus(events.Config{
+		Workers:   cfg.Events.Workers,
+		QueueSize: cfg.Events.QueueSize,
+	})
+
+	rateLimits := ratelimit.Limits{
+		Store:  ratelimit.NewMemoryStore(),
+		PerIP:  ratelimit.Policy{Name: "ip", Requests: cfg.RateLimit.PerIP.Requests, Window: cfg.RateLimit.PerIP.Window},
+		PerKey: ratelimit.Policy{Name: "key", Requests: cfg.RateLimit.PerKey.Requests, Window: cfg.RateLimit.PerKey.Window},
+		Auth:   ratelimit.Policy{Name: "auth", Requests: cfg.RateLimit.Auth.Requests, Window: cfg.RateLimit.Auth.Window},
+	}
+
+	mailer, err := mail.NewSender(mail.Config{Driver: cfg.Mail.Driver, From: cfg.Mail.From, Dir: cfg.Mail.Dir, Debug: cfg.Server.Mode == gin.DebugMode})
+	if err != nil {
+		logger.Fatalf("Failed to configure mail: %v", err)
+	}
+
+	deps := module.Dependencies{
+		DB:           db,
+		Config:       cfg,
+		Events:       bus,
+		Transactions: transaction.NewManager(db),
+		RateLimits:   rateLimits,
+		Idempotency:  idempotency.NewGormStore(db),
+		Mail:         mailer,
+	}
+
+	appModules, err := registry.Build(ctx, deps)
+	if err != nil {
+		logger.Fatalf("Failed to setup modules: %v", err)
+	}
+
+	if err := module.StartModules(ctx, appModules...); err != nil {
+		logger.Fatalf("Failed to start modules: %v", err)
 	}
-	modules := module.SetupAllModules(db, userModuleSetup)
+	logger.Info("Modules started successfully")
 
 	router := gin.New()
+	// Sem proxies confiáveis, X-Forwarded-For é ignorado e c.ClientIP() é o IP da conexão
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	router.Use(gin.Recovery())
 	router.Use(gin.Logger())
@@ -53,12 +139,47 @@ func main() {
 	healthHandler := httpHandler.NewHandler()
 	healthHandler.RegisterRoutes(router)
 
-	api := router.Group("/api/v1")
+	api := router.Group("/api/v1", middleware.RateLimit(rateLimits.Store, rateLimits.PerIP, middleware.ByClientIP))
+
+	module.RegisterModules(api, appModules...)
+
+	srv := server.New(cfg.Server, router)
+
+	logger.Infof("Server starting on port %s", cfg.Server.Port)
+	serverErr := server.Run(ctx, srv, cfg.Server.ShutdownTimeout)
+	if serverErr != nil {
+		logger.Errorf("Server error: %v", serverErr)
+	}
+
+	if err := shutdown(appModules, bus, db, cfg.Server.ShutdownTimeout); err != nil {
+		logger.Errorf("Shutdown finished with errors: %v", err)
+		os.Exit(1)
+	}
 
-	module.RegisterModules(api, modules...)
+	if serverErr != nil {
+		os.Exit(1)
+	}
+	logger.Info("Shutdown completed")
+}
 
-	port := cfg.Server.Port
+// shutdown para os módulos em ordem reversa (o dispatcher do outbox primeiro), drena o
+// barramento de eventos e fecha o pool do banco por último
+func shutdown(modules []module.Module, bus *events.Bus, db *gorm.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	if err := module.StopModules(ctx, modules...); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := bus.Close(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := database.Close(db); err != nil {
+		errs = append(errs, err)
+	}
 
-	logger.Infof("Server starting on port %s", port)
-	logger.Fatal(http.ListenAndServe(":"+port, router))
+	return errors.Join(errs...)
 }
@@ -0,0 +1,13 @@
+package app
+
+import "time"
+
+type CreateAPIKeyCommand struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+type RevokeAPIKeyCommand struct {
+	ID string
+}
@@ -0,0 +1,33 @@
+package app
+
+import "time"
+
+type GetAPIKeyQuery struct {
+	ID string
+}
+
+// Status derivados da chave
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+	StatusRevoked = "revoked"
+)
+
+// APIKeyInfo é a visão pública de uma chave; nunca inclui o segredo nem o hash
+type APIKeyInfo struct {
+	ID         string
+	Name       string
+	Prefix     string
+	Scopes     []string
+	Status     string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+// CreatedAPIKey carrega o token em texto puro, devolvido apenas na criação
+type CreatedAPIKey struct {
+	APIKeyInfo
+	Token string
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/apikey/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+// BootstrapKeyName é o nome da chave importada da configuração
+const BootstrapKeyName = "bootstrap"
+
+// ScopeAdmin permite gerenciar API keys. É o único escopo da chave de bootstrap,
+// que serve para emitir as chaves com os escopos de cada integração.
+const ScopeAdmin = "api_keys:admin"
+
+// lastUsedResolution limita a frequência de escrita de last_used_at por chave
+const lastUsedResolution = time.Minute
+
+type APIKeyService struct {
+	repo domain.APIKeyRepository
+	tx   transaction.Manager
+	now  func() time.Time
+}
+
+func NewAPIKeyService(repo domain.APIKeyRepository, tx transaction.Manager) *APIKeyService {
+	return &APIKeyService{repo: repo, tx: tx, now: time.Now}
+}
+
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, cmd CreateAPIKeyCommand) (*CreatedAPIKey, error) {
+	key, token, err := domain.NewAPIKey(cmd.Name, cmd.Scopes, cmd.ExpiresAt, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &CreatedAPIKey{APIKeyInfo: *s.toInfo(key), Token: token}, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*APIKeyInfo, error) {
+	keys, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*APIKeyInfo, len(keys))
+	for i, key := range keys {
+		result[i] = s.toInfo(key)
+	}
+	return result, nil
+}
+
+func (s *APIKeyService) GetAPIKey(ctx context.Context, query GetAPIKeyQuery) (*APIKeyInfo, error) {
+	key, err := s.repo.FindByID(ctx, query.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toInfo(key), nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, cmd RevokeAPIKeyCommand) (*APIKeyInfo, error) {
+	var key *domain.APIKey
+
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		key, err = s.repo.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		key.Revoke(s.now())
+		return s.repo.Save(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toInfo(key), nil
+}
+
+// EnsureBootstrapKey importa a chave configurada em APIKEY_BOOTSTRAP_KEY caso ainda
+// não exista, permitindo criar as demais chaves em um ambiente vazio
+func (s *APIKeyService) EnsureBootstrapKey(ctx context.Context, token string) error {
+	key, err := domain.ImportAPIKey(BootstrapKeyName, token, []string{ScopeAdmin}, nil, s.now())
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.repo.FindByPrefix(ctx, key.Prefix())
+	switch {
+	case err == nil:
+		if !existing.Matches(secretOf(token)) {
+			return domain.ErrPrefixTaken
+		}
+		return nil
+	case !errors.Is(err, domain.ErrAPIKeyNotFound):
+		return err
+	}
+
+	if err := s.repo.Save(ctx, key); err != nil {
+		return err
+	}
+
+	logger.WithField("api_key_prefix", key.Prefix()).Info("Bootstrap API key registered")
+	return nil
+}
+
+// ResolveAPIKey implementa auth.APIKeyResolver. O motivo da recusa só vai para o log
+// (identificado pelo prefixo); o cliente sempre recebe auth.ErrInvalidAPIKey.
+func (s *APIKeyService) ResolveAPIKey(ctx context.Context, token string) (*auth.Principal, error) {
+	prefix, secret, ok := domain.ParseToken(token)
+	if !ok {
+		logger.Warn("Malformed API key provided")
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	log := logger.WithField("api_key_prefix", prefix)
+
+	key, err := s.repo.FindByPrefix(ctx, prefix)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		log.Warn("Unknown API key provided")
+		return nil, auth.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	switch {
+	case !key.Matches(secret):
+		log.Warn("API key secret mismatch")
+		return nil, auth.ErrInvalidAPIKey
+	case key.IsRevoked():
+		log.Warn("Revoked API key provided")
+		return nil, auth.ErrInvalidAPIKey
+	case key.IsExpired(now):
+		log.Warn("Expired API key provided")
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	if last := key.LastUsedAt(); last == nil || now.Sub(*last) >= lastUsedResolution {
+		if err := s.repo.TouchLastUsed(ctx, key.ID(), now); err != nil {
+			log.WithError(err).Warn("Failed to record API key usage")
+		}
+	}
+
+	return &auth.Principal{
+		Subject: key.ID(),
+		Kind:    auth.KindAPIKey,
+		Name:    key.Name(),
+		Scopes:  key.Scopes(),
+	}, nil
+}
+
+func (s *APIKeyService) toInfo(key *domain.APIKey) *APIKeyInfo {
+	status := StatusActive
+	switch {
+	case key.IsRevoked():
+		status = StatusRevoked
+	case key.IsExpired(s.now()):
+		status = StatusExpired
+	}
+
+	return &APIKeyInfo{
+		ID:         key.ID(),
+		Name:       key.Name(),
+		Prefix:     key.Prefix(),
+		Scopes:     key.Scopes(),
+		Status:     status,
+		CreatedAt:  key.CreatedAt(),
+		ExpiresAt:  key.ExpiresAt(),
+		RevokedAt:  key.RevokedAt(),
+		LastUsedAt: key.LastUsedAt(),
+	}
+}
+
+func secretOf(token string) string {
+	_, secret, _ := domain.ParseToken(token)
+	return secret
+}
+
+var _ auth.APIKeyResolver = (*APIKeyService)(nil)
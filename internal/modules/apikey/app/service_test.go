@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/apikey/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+type MockAPIKeyRepository struct {
+	keys    map[string]*domain.APIKey
+	Touched []string
+
+	FindByPrefixFunc func(ctx context.Context, prefix string) (*domain.APIKey, error)
+}
+
+func NewMockAPIKeyRepository() *MockAPIKeyRepository {
+	return &MockAPIKeyRepository{keys: make(map[string]*domain.APIKey)}
+}
+
+func (m *MockAPIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	m.keys[key.ID()] = key
+	return nil
+}
+
+func (m *MockAPIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (m *MockAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	if m.FindByPrefixFunc != nil {
+		return m.FindByPrefixFunc(ctx, prefix)
+	}
+	for _, key := range m.keys {
+		if key.Prefix() == prefix {
+			return key, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (m *MockAPIKeyRepository) FindAll(ctx context.Context) ([]*domain.APIKey, error) {
+	keys := make([]*domain.APIKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	m.Touched = append(m.Touched, id)
+	return nil
+}
+
+var _ domain.APIKeyRepository = (*MockAPIKeyRepository)(nil)
+
+type MockTransactionManager struct{}
+
+func (MockTransactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestResolveAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid key resolves to a principal", func(t *testing.T) {
+		repo := NewMockAPIKeyRepository()
+		service := NewAPIKeyService(repo, MockTransactionManager{})
+
+		created, err := service.CreateAPIKey(ctx, CreateAPIKeyCommand{Name: "partner", Scopes: []string{"users:read"}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		principal, err := service.ResolveAPIKey(ctx, created.Token)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if principal.Subject != created.ID || principal.Kind != auth.KindAPIKey || principal.Name != "partner" {
+			t.Errorf("Unexpected principal: %+v", principal)
+		}
+		if !principal.HasScope("users:read") || principal.HasScope("users:write") {
+			t.Errorf("Expected principal to carry only the key scopes, got %v", principal.Scopes)
+		}
+		if len(repo.Touched) != 1 {
+			t.Errorf("Expected usage to be recorded once, got %d", len(repo.Touched))
+		}
+	})
+
+	t.Run("Invalid keys are rejected without details", func(t *testing.T) {
+		repo := NewMockAPIKeyRepository()
+		service := NewAPIKeyService(repo, MockTransactionManager{})
+
+		revoked, _ := service.CreateAPIKey(ctx, CreateAPIKeyCommand{Name: "revoked", Scopes: []string{"users:read"}})
+		if _, err := service.RevokeAPIKey(ctx, RevokeAPIKeyCommand{ID: revoked.ID}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expiresAt := time.Now().Add(time.Minute)
+		expired, _ := service.CreateAPIKey(ctx, CreateAPIKeyCommand{Name: "expired", Scopes: []string{"users:read"}, ExpiresAt: &expiresAt})
+		service.now = func() time.Time { return expiresAt.Add(time.Second) }
+
+		valid, _ := service.CreateAPIKey(ctx, CreateAPIKeyCommand{Name: "valid", Scopes: []string{"users:read"}})
+		prefix, _, _ := domain.ParseToken(valid.Token)
+
+		tokens := map[string]string{
+			"malformed":    "api-key-exemplo",
+			"unknown":      domain.FormatToken("zzzzzzzz", strings.Repeat("a", domain.MinSecretLength)),
+			"wrong secret": domain.FormatToken(prefix, strings.Repeat("a", domain.MinSecretLength)),
+			"revoked":      revoked.Token,
+			"expired":      expired.Token,
+		}
+		for name, token := range tokens {
+			if _, err := service.ResolveAPIKey(ctx, token); !errors.Is(err, auth.ErrInvalidAPIKey) {
+				t.Errorf("Expected ErrInvalidAPIKey for %s key, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("Storage failures are not reported as invalid keys", func(t *testing.T) {
+		repo := NewMockAPIKeyRepository()
+		service := NewAPIKeyService(repo, MockTransactionManager{})
+		created, _ := service.CreateAPIKey(ctx, CreateAPIKeyCommand{Name: "partner", Scopes: []string{"users:read"}})
+
+		outage := errors.New("connection refused")
+		repo.FindByPrefixFunc = func(ctx context.Context, prefix string) (*domain.APIKey, error) {
+			return nil, outage
+		}
+
+		if _, err := service.ResolveAPIKey(ctx, created.Token); !errors.Is(err, outage) {
+			t.Errorf("Expected storage error, got %v", err)
+		}
+	})
+}
+
+func TestEnsureBootstrapKey(t *testing.T) {
+	ctx := context.Background()
+	token := domain.FormatToken("boot0001", strings.Repeat("b", domain.MinSecretLength))
+
+	t.Run("Registers once and resolves", func(t *testing.T) {
+		repo := NewMockAPIKeyRepository()
+		service := NewAPIKeyService(repo, MockTransactionManager{})
+
+		for range 2 {
+			if err := service.EnsureBootstrapKey(ctx, token); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		if len(repo.keys) != 1 {
+			t.Errorf("Expected a single bootstrap key, got %d", len(repo.keys))
+		}
+		principal, err := service.ResolveAPIKey(ctx, token)
+		if err != nil {
+			t.Fatalf("Expected bootstrap key to resolve, got %v", err)
+		}
+		if !principal.HasScope(ScopeAdmin) || principal.HasScope("users:write") {
+			t.Errorf("Expected bootstrap key to only manage api keys, got %v", principal.Scopes)
+		}
+	})
+
+	t.Run("Rejects malformed and conflicting keys", func(t *testing.T) {
+		repo := NewMockAPIKeyRepository()
+		service := NewAPIKeyService(repo, MockTransactionManager{})
+
+		if err := service.EnsureBootstrapKey(ctx, "api-key-exemplo"); !errors.Is(err, domain.ErrMalformedToken) {
+			t.Errorf("Expected ErrMalformedToken, got %v", err)
+		}
+
+		_ = service.EnsureBootstrapKey(ctx, token)
+		other := domain.FormatToken("boot0001", strings.Repeat("c", domain.MinSecretLength))
+		if err := service.EnsureBootstrapKey(ctx, other); !errors.Is(err, domain.ErrPrefixTaken) {
+			t.Errorf("Expected ErrPrefixTaken, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,243 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+const (
+	// TokenPrefix identifica as chaves emitidas por este serviço (útil para secret scanning)
+	TokenPrefix = "mm"
+
+	// PrefixLength é o tamanho do trecho público usado para localizar a chave
+	PrefixLength = 8
+	// MinSecretLength é o tamanho mínimo do segredo aceito, inclusive em chaves de bootstrap
+	MinSecretLength = 32
+
+	MaxNameLength = 255
+	// MaxScopes limita a quantidade de escopos por chave
+	MaxScopes = 32
+
+	prefixAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// scopePattern aceita escopos no formato "<recurso>:<ação>", ex.: "users:write"
+var scopePattern = regexp.MustCompile(`^[a-z][a-z_]*:[a-z][a-z_]*$`)
+
+type APIKey struct {
+	id         string
+	name       string
+	prefix     string
+	secretHash string
+	scopes     []string
+	createdAt  time.Time
+	expiresAt  *time.Time
+	revokedAt  *time.Time
+	lastUsedAt *time.Time
+}
+
+// NewAPIKey gera uma chave aleatória e retorna o agregado junto com o token em texto puro,
+// que só existe neste momento: apenas o hash do segredo é persistido
+func NewAPIKey(name string, scopes []string, expiresAt *time.Time, now time.Time) (*APIKey, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+
+	token := FormatToken(randomPrefix(), base64.RawURLEncoding.EncodeToString(secret))
+
+	key, err := ImportAPIKey(name, token, scopes, expiresAt, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, token, nil
+}
+
+// ImportAPIKey registra uma chave cujo token já é conhecido (ex.: chave de bootstrap vinda da configuração)
+func ImportAPIKey(name, token string, scopes []string, expiresAt *time.Time, now time.Time) (*APIKey, error) {
+	name = strings.TrimSpace(name)
+	scopes = normalizeScopes(scopes)
+
+	var violations []apperror.FieldError
+	switch {
+	case name == "":
+		violations = append(violations, apperror.FieldError{Field: "name", Code: "required", Message: "name is required"})
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		violations = append(violations, apperror.FieldError{Field: "name", Code: "max", Message: fmt.Sprintf("name must be at most %d characters", MaxNameLength)})
+	}
+	violations = append(violations, validateScopes(scopes)...)
+	if expiresAt != nil && !expiresAt.After(now) {
+		violations = append(violations, apperror.FieldError{Field: "expires_at", Code: "future", Message: "expires_at must be in the future"})
+	}
+	if len(violations) > 0 {
+		return nil, ErrInvalidAPIKeyData.WithFields(violations...)
+	}
+
+	prefix, secret, ok := ParseToken(token)
+	if !ok {
+		return nil, ErrMalformedToken
+	}
+
+	return &APIKey{
+		id:         uuid.New().String(),
+		name:       name,
+		prefix:     prefix,
+		secretHash: auth.HashSecret(secret),
+		scopes:     scopes,
+		createdAt:  now,
+		expiresAt:  expiresAt,
+	}, nil
+}
+
+func ReconstructAPIKey(id, name, prefix, secretHash string, scopes []string, createdAt time.Time, expiresAt, revokedAt, lastUsedAt *time.Time) *APIKey {
+	return &APIKey{
+		id:         id,
+		name:       name,
+		prefix:     prefix,
+		secretHash: secretHash,
+		scopes:     scopes,
+		createdAt:  createdAt,
+		expiresAt:  expiresAt,
+		revokedAt:  revokedAt,
+		lastUsedAt: lastUsedAt,
+	}
+}
+
+// FormatToken monta o token no formato mm_<prefixo>_<segredo>
+func FormatToken(prefix, secret string) string {
+	return TokenPrefix + "_" + prefix + "_" + secret
+}
+
+// ParseToken separa prefixo e segredo de um token mm_<prefixo>_<segredo>
+func ParseToken(token string) (prefix, secret string, ok bool) {
+	rest, found := strings.CutPrefix(token, TokenPrefix+"_")
+	if !found {
+		return "", "", false
+	}
+
+	prefix, secret, found = strings.Cut(rest, "_")
+	if !found || len(prefix) != PrefixLength || len(secret) < MinSecretLength {
+		return "", "", false
+	}
+	for _, r := range prefix {
+		if !strings.ContainsRune(prefixAlphabet, r) {
+			return "", "", false
+		}
+	}
+
+	return prefix, secret, true
+}
+
+func (k *APIKey) ID() string {
+	return k.id
+}
+
+func (k *APIKey) Name() string {
+	return k.name
+}
+
+func (k *APIKey) Prefix() string {
+	return k.prefix
+}
+
+func (k *APIKey) SecretHash() string {
+	return k.secretHash
+}
+
+// Scopes retorna os escopos concedidos, ordenados e sem repetição
+func (k *APIKey) Scopes() []string {
+	return slices.Clone(k.scopes)
+}
+
+func (k *APIKey) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+func (k *APIKey) ExpiresAt() *time.Time {
+	return k.expiresAt
+}
+
+func (k *APIKey) RevokedAt() *time.Time {
+	return k.revokedAt
+}
+
+func (k *APIKey) LastUsedAt() *time.Time {
+	return k.lastUsedAt
+}
+
+// Matches compara o segredo com o hash armazenado em tempo constante
+func (k *APIKey) Matches(secret string) bool {
+	return auth.SecretMatches(secret, k.secretHash)
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.expiresAt != nil && !now.Before(*k.expiresAt)
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.revokedAt != nil
+}
+
+// IsActive informa se a chave pode autenticar requisições
+func (k *APIKey) IsActive(now time.Time) bool {
+	return !k.IsRevoked() && !k.IsExpired(now)
+}
+
+// Revoke invalida a chave definitivamente; revogar de novo não altera a data original
+func (k *APIKey) Revoke(now time.Time) {
+	if k.revokedAt != nil {
+		return
+	}
+	k.revokedAt = &now
+}
+
+// normalizeScopes remove espaços e repetições e ordena os escopos
+func normalizeScopes(scopes []string) []string {
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		normalized = append(normalized, strings.TrimSpace(scope))
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+func validateScopes(scopes []string) []apperror.FieldError {
+	switch {
+	case len(scopes) == 0:
+		return []apperror.FieldError{{Field: "scopes", Code: "required", Message: "at least one scope is required"}}
+	case len(scopes) > MaxScopes:
+		return []apperror.FieldError{{Field: "scopes", Code: "max", Message: fmt.Sprintf("at most %d scopes are allowed", MaxScopes)}}
+	}
+
+	var violations []apperror.FieldError
+	for _, scope := range scopes {
+		if !scopePattern.MatchString(scope) {
+			violations = append(violations, apperror.FieldError{
+				Field:   "scopes",
+				Code:    "format",
+				Message: fmt.Sprintf("scope %q must have the format <resource>:<action>", scope),
+			})
+		}
+	}
+	return violations
+}
+
+func randomPrefix() string {
+	buf := make([]byte, PrefixLength)
+	_, _ = rand.Read(buf)
+	for i, b := range buf {
+		buf[i] = prefixAlphabet[int(b)%len(prefixAlphabet)]
+	}
+	return string(buf)
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var readScopes = []string{"users:read"}
+
+func TestNewAPIKey(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Generates a parseable token and stores only the hash", func(t *testing.T) {
+		key, token, err := NewAPIKey("partner", readScopes, nil, now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		prefix, secret, ok := ParseToken(token)
+		if !ok {
+			t.Fatalf("Expected token %q to be parseable", token)
+		}
+		if prefix != key.Prefix() {
+			t.Errorf("Expected prefix %s, got %s", key.Prefix(), prefix)
+		}
+		if strings.Contains(key.SecretHash(), secret) {
+			t.Error("Expected secret not to be stored in plain text")
+		}
+		if !key.Matches(secret) || key.Matches(secret+"x") {
+			t.Error("Expected only the original secret to match")
+		}
+	})
+
+	t.Run("Rejects invalid data", func(t *testing.T) {
+		past := now.Add(-time.Hour)
+
+		if _, _, err := NewAPIKey(" ", readScopes, nil, now); !errors.Is(err, ErrInvalidAPIKeyData) {
+			t.Errorf("Expected ErrInvalidAPIKeyData for empty name, got %v", err)
+		}
+		if _, _, err := NewAPIKey("partner", readScopes, &past, now); !errors.Is(err, ErrInvalidAPIKeyData) {
+			t.Errorf("Expected ErrInvalidAPIKeyData for past expiry, got %v", err)
+		}
+		if _, _, err := NewAPIKey("partner", nil, nil, now); !errors.Is(err, ErrInvalidAPIKeyData) {
+			t.Errorf("Expected ErrInvalidAPIKeyData without scopes, got %v", err)
+		}
+		if _, _, err := NewAPIKey("partner", []string{"users"}, nil, now); !errors.Is(err, ErrInvalidAPIKeyData) {
+			t.Errorf("Expected ErrInvalidAPIKeyData for malformed scope, got %v", err)
+		}
+	})
+
+	t.Run("Normalizes scopes", func(t *testing.T) {
+		key, _, err := NewAPIKey("partner", []string{"users:write", " users:read ", "users:write"}, nil, now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := strings.Join(key.Scopes(), " "); got != "users:read users:write" {
+			t.Errorf("Expected sorted unique scopes, got %q", got)
+		}
+	})
+}
+
+func TestParseToken(t *testing.T) {
+	secret := strings.Repeat("s", MinSecretLength)
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{name: "Valid", token: "mm_abcd1234_" + secret, ok: true},
+		{name: "Secret with underscore", token: "mm_abcd1234_" + secret + "_x", ok: true},
+		{name: "Missing product prefix", token: "abcd1234_" + secret, ok: false},
+		{name: "Short prefix", token: "mm_abc_" + secret, ok: false},
+		{name: "Uppercase prefix", token: "mm_ABCD1234_" + secret, ok: false},
+		{name: "Short secret", token: "mm_abcd1234_short", ok: false},
+		{name: "Legacy key", token: "api-key-exemplo", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, ok := ParseToken(tt.token); ok != tt.ok {
+				t.Errorf("Expected ok=%v, got %v", tt.ok, ok)
+			}
+		})
+	}
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	key, _, _ := NewAPIKey("partner", readScopes, &expiresAt, now)
+
+	if !key.IsActive(now) {
+		t.Error("Expected new key to be active")
+	}
+	if key.IsActive(expiresAt) {
+		t.Error("Expected key to be expired at its expiry time")
+	}
+
+	key.Revoke(now)
+	revokedAt := *key.RevokedAt()
+	key.Revoke(now.Add(time.Minute))
+
+	if key.IsActive(now) {
+		t.Error("Expected revoked key to be inactive")
+	}
+	if !key.RevokedAt().Equal(revokedAt) {
+		t.Error("Expected second revoke to keep the original date")
+	}
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+
+// Erros do módulo apikey. Compare com errors.Is; os códigos são expostos na API e devem ser estáveis.
+var (
+	ErrAPIKeyNotFound    = apperror.NotFound("API_KEY_NOT_FOUND", "api key not found")
+	ErrInvalidAPIKeyData = apperror.Validation("INVALID_API_KEY_DATA", "invalid api key data")
+	ErrMalformedToken    = apperror.Validation("MALFORMED_API_KEY", "api key must have the format mm_<prefix>_<secret>")
+	ErrPrefixTaken       = apperror.Conflict("API_KEY_PREFIX_TAKEN", "an api key with this prefix already exists")
+)
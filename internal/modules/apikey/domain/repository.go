@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, key *APIKey) error
+	FindByID(ctx context.Context, id string) (*APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	FindAll(ctx context.Context) ([]*APIKey, error)
+	// TouchLastUsed registra o uso da chave sem reescrever o agregado
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
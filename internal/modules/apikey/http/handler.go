@@ -0,0 +1,96 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/apikey/app"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/problem"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/validation"
+)
+
+type APIKeyHandlers struct {
+	service *app.APIKeyService
+	tokens  auth.TokenVerifier
+	authz   auth.Authorizer
+	limits  ratelimit.Limits
+}
+
+func NewAPIKeyHandlers(service *app.APIKeyService, tokens auth.TokenVerifier, authz auth.Authorizer, limits ratelimit.Limits) *APIKeyHandlers {
+	return &APIKeyHandlers{service: service, tokens: tokens, authz: authz, limits: limits}
+}
+
+func (h *APIKeyHandlers) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	created, err := h.service.CreateAPIKey(c.Request.Context(), app.CreateAPIKeyCommand{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	// O segredo não pode ser recuperado depois; evita que proxies guardem a resposta
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, CreatedAPIKeyResponse{
+		APIKeyResponse: toResponse(&created.APIKeyInfo),
+		Key:            created.Token,
+	})
+}
+
+func (h *APIKeyHandlers) ListAPIKeys(c *gin.Context) {
+	keys, err := h.service.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	responses := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = toResponse(key)
+	}
+
+	c.JSON(http.StatusOK, APIKeysResponse{APIKeys: responses})
+}
+
+func (h *APIKeyHandlers) GetAPIKey(c *gin.Context) {
+	key, err := h.service.GetAPIKey(c.Request.Context(), app.GetAPIKeyQuery{ID: c.Param("id")})
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toResponse(key))
+}
+
+func (h *APIKeyHandlers) RevokeAPIKey(c *gin.Context) {
+	if _, err := h.service.RevokeAPIKey(c.Request.Context(), app.RevokeAPIKeyCommand{ID: c.Param("id")}); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func toResponse(key *app.APIKeyInfo) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		Status:     key.Status,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		LastUsedAt: key.LastUsedAt,
+	}
+}
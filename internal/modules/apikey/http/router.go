@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/apikey/app"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/middleware"
+)
+
+func (h *APIKeyHandlers) RegisterRoutes(router *gin.RouterGroup) {
+	// Gerenciar chaves exige api_keys:admin, como escopo da credencial ou por papel (rbac)
+	protected := router.Group("/",
+		middleware.Authenticate(h.service, h.tokens),
+		middleware.RateLimit(h.limits.Store, h.limits.PerKey, middleware.ByPrincipal),
+		middleware.RequirePermission(h.authz, app.ScopeAdmin),
+	)
+	{
+		protected.POST("/", h.CreateAPIKey)
+		protected.GET("/", h.ListAPIKeys)
+		protected.GET("/:id", h.GetAPIKey)
+		protected.DELETE("/:id", h.RevokeAPIKey)
+	}
+}
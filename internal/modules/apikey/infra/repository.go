@@ -0,0 +1,128 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/apikey/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"gorm.io/gorm"
+)
+
+type APIKeyModel struct {
+	ID         string `gorm:"primaryKey"`
+	Name       string
+	Prefix     string `gorm:"size:16;uniqueIndex"`
+	SecretHash string
+	// Scopes guarda os escopos separados por espaço, como no parâmetro scope do OAuth 2.0
+	Scopes     string
+	CreatedAt  int64
+	ExpiresAt  *int64
+	RevokedAt  *int64
+	LastUsedAt *int64
+}
+
+func (APIKeyModel) TableName() string {
+	return "api_keys"
+}
+
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAPIKeyRepository(db *gorm.DB) *GormAPIKeyRepository {
+	return &GormAPIKeyRepository{db: db}
+}
+
+func (r *GormAPIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	model := APIKeyModel{
+		ID:         key.ID(),
+		Name:       key.Name(),
+		Prefix:     key.Prefix(),
+		SecretHash: key.SecretHash(),
+		Scopes:     strings.Join(key.Scopes(), " "),
+		CreatedAt:  key.CreatedAt().Unix(),
+		ExpiresAt:  toUnix(key.ExpiresAt()),
+		RevokedAt:  toUnix(key.RevokedAt()),
+		LastUsedAt: toUnix(key.LastUsedAt()),
+	}
+
+	err := transaction.DB(ctx, r.db).Save(&model).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrPrefixTaken.Wrap(err)
+	}
+	return err
+}
+
+func (r *GormAPIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *GormAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	return r.findOne(ctx, "prefix = ?", prefix)
+}
+
+func (r *GormAPIKeyRepository) FindAll(ctx context.Context) ([]*domain.APIKey, error) {
+	var models []APIKeyModel
+	if err := transaction.DB(ctx, r.db).Order("created_at, id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]*domain.APIKey, len(models))
+	for i, model := range models {
+		keys[i] = toDomain(model)
+	}
+	return keys, nil
+}
+
+func (r *GormAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	return transaction.DB(ctx, r.db).
+		Model(&APIKeyModel{}).
+		Where("id = ?", id).
+		Update("last_used_at", at.Unix()).Error
+}
+
+func (r *GormAPIKeyRepository) findOne(ctx context.Context, query string, arg any) (*domain.APIKey, error) {
+	var model APIKeyModel
+	err := transaction.DB(ctx, r.db).First(&model, query, arg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomain(model), nil
+}
+
+func toDomain(model APIKeyModel) *domain.APIKey {
+	return domain.ReconstructAPIKey(
+		model.ID,
+		model.Name,
+		model.Prefix,
+		model.SecretHash,
+		strings.Fields(model.Scopes),
+		time.Unix(model.CreatedAt, 0),
+		fromUnix(model.ExpiresAt),
+		fromUnix(model.RevokedAt),
+		fromUnix(model.LastUsedAt),
+	)
+}
+
+func toUnix(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	unix := t.Unix()
+	return &unix
+}
+
+func fromUnix(unix *int64) *time.Time {
+	if unix == nil {
+		return nil
+	}
+	t := time.Unix(*unix, 0)
+	return &t
+}
@@ -0,0 +1,114 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/apikey/app"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/apikey/http"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/apikey/infra"
+	rbacDomain "github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/module"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"gorm.io/gorm"
+)
+
+// Module resolve as chaves para os demais módulos; as rotas de gerenciamento ficam no AdminModule
+type Module struct {
+	service      *app.APIKeyService
+	bootstrapKey string
+}
+
+func NewModule(db *gorm.DB, tx transaction.Manager, bootstrapKey string) *Module {
+	repo := infra.NewGormAPIKeyRepository(db)
+
+	return &Module{
+		service:      app.NewAPIKeyService(repo, tx),
+		bootstrapKey: bootstrapKey,
+	}
+}
+
+// Definition descreve o módulo para o registro de módulos
+func Definition() module.Definition {
+	return module.Definition{
+		Name:     "apikey",
+		Provides: []string{auth.APIKeyResolverContract},
+		Setup: func(deps module.Dependencies) (module.Module, error) {
+			return NewModule(deps.DB, deps.Transactions, deps.Config.APIKeys.BootstrapKey), nil
+		},
+	}
+}
+
+// Init registra a chave de bootstrap, se configurada
+func (m *Module) Init(ctx context.Context, deps module.Dependencies) error {
+	if m.bootstrapKey == "" {
+		return nil
+	}
+
+	if err := m.service.EnsureBootstrapKey(ctx, m.bootstrapKey); err != nil {
+		return fmt.Errorf("bootstrap api key: %w", err)
+	}
+	return nil
+}
+
+// RegisterRoutes não registra nada: as rotas /api-keys são do AdminModule
+func (m *Module) RegisterRoutes(router *gin.RouterGroup) {}
+
+func (m *Module) Resolver() auth.APIKeyResolver {
+	return m.service
+}
+
+func (m *Module) Exports() map[string]any {
+	return map[string]any{
+		auth.APIKeyResolverContract: m.Resolver(),
+	}
+}
+
+// AdminModule expõe as rotas /api-keys. Fica fora do Module porque consome o Authorizer
+// do rbac, que por sua vez depende do resolver de chaves.
+type AdminModule struct {
+	handlers *http.APIKeyHandlers
+}
+
+func NewAdminModule(service *app.APIKeyService, tokens auth.TokenVerifier, authz auth.Authorizer, limits ratelimit.Limits) *AdminModule {
+	return &AdminModule{handlers: http.NewAPIKeyHandlers(service, tokens, authz, limits)}
+}
+
+// AdminDefinition descreve as rotas de gerenciamento para o registro de módulos
+func AdminDefinition() module.Definition {
+	return module.Definition{
+		Name:     "apikey_admin",
+		Requires: []string{auth.APIKeyResolverContract, auth.TokenVerifierContract, rbacDomain.AuthorizerContract},
+		Setup: func(deps module.Dependencies) (module.Module, error) {
+			// O resolver exportado pelo módulo apikey é o próprio serviço de chaves
+			service, err := module.Resolve[*app.APIKeyService](deps.Contracts, auth.APIKeyResolverContract)
+			if err != nil {
+				return nil, err
+			}
+			tokens, err := module.Resolve[auth.TokenVerifier](deps.Contracts, auth.TokenVerifierContract)
+			if err != nil {
+				return nil, err
+			}
+			authz, err := module.Resolve[auth.Authorizer](deps.Contracts, rbacDomain.AuthorizerContract)
+			if err != nil {
+				return nil, err
+			}
+
+			return NewAdminModule(service, tokens, authz, deps.RateLimits), nil
+		},
+	}
+}
+
+func (m *AdminModule) RegisterRoutes(router *gin.RouterGroup) {
+	m.handlers.RegisterRoutes(router.Group("/api-keys"))
+}
+
+var (
+	_ module.Module      = (*Module)(nil)
+	_ module.Initializer = (*Module)(nil)
+	_ module.Exporter    = (*Module)(nil)
+	_ module.Module      = (*AdminModule)(nil)
+)
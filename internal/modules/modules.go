@@ -0,0 +1,24 @@
+// Package modules lista os módulos que compõem a aplicação.
+// Para adicionar um módulo basta incluir sua Definition aqui; a ordem de
+// inicialização é resolvida pelos contratos declarados em Provides/Requires.
+package modules
+
+import (
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/apikey"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/module"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/outbox"
+)
+
+// Definitions retorna as definições de todos os módulos da aplicação
+func Definitions() []module.Definition {
+	return []module.Definition{
+		apikey.Definition(),
+		rbac.Definition(),
+		apikey.AdminDefinition(),
+		user.Definition(),
+		// Por último: o dispatcher só entrega eventos depois que todos os módulos os assinaram
+		outbox.Definition(),
+	}
+}
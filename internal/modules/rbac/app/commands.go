@@ -0,0 +1,27 @@
+package app
+
+type CreateRoleCommand struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+type UpdateRoleCommand struct {
+	ID          string
+	Description string
+	Permissions []string
+}
+
+type DeleteRoleCommand struct {
+	ID string
+}
+
+type AssignRoleCommand struct {
+	RoleID string
+	UserID string
+}
+
+type UnassignRoleCommand struct {
+	RoleID string
+	UserID string
+}
@@ -0,0 +1,19 @@
+package app
+
+import "time"
+
+type GetRoleQuery struct {
+	ID string
+}
+
+type ListMembersQuery struct {
+	RoleID string
+}
+
+type RoleDetails struct {
+	ID          string
+	Name        string
+	Description string
+	Permissions []string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+)
+
+// ScopeAdmin permite gerenciar papéis e atribuições
+const ScopeAdmin = "roles:admin"
+
+type RoleService struct {
+	repo domain.RoleRepository
+	tx   transaction.Manager
+	now  func() time.Time
+}
+
+func NewRoleService(repo domain.RoleRepository, tx transaction.Manager) *RoleService {
+	return &RoleService{repo: repo, tx: tx, now: time.Now}
+}
+
+// Can implementa auth.Authorizer. Escopos da credencial (API keys, tokens) valem como
+// antes; usuários recebem também as permissões dos papéis atribuídos a eles.
+func (s *RoleService) Can(ctx context.Context, principal *auth.Principal, permission string) (bool, error) {
+	if principal == nil {
+		return false, nil
+	}
+	if principal.HasScope(permission) {
+		return true, nil
+	}
+	if principal.Kind != auth.KindUser {
+		return false, nil
+	}
+
+	roles, err := s.repo.FindByUser(ctx, principal.Subject)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if role.Grants(permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UserRoles implementa domain.Authorizer
+func (s *RoleService) UserRoles(ctx context.Context, userID string) ([]*domain.RoleInfo, error) {
+	roles, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.RoleInfo, len(roles))
+	for i, role := range roles {
+		result[i] = &domain.RoleInfo{ID: role.ID(), Name: role.Name(), Permissions: role.Permissions()}
+	}
+	return result, nil
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, cmd CreateRoleCommand) (*RoleDetails, error) {
+	role, err := domain.NewRole(cmd.Name, cmd.Description, cmd.Permissions, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		_, err := s.repo.FindByName(ctx, role.Name())
+		if err == nil {
+			return domain.ErrRoleAlreadyExists
+		}
+		if !errors.Is(err, domain.ErrRoleNotFound) {
+			return err
+		}
+		return s.repo.Save(ctx, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toDetails(role), nil
+}
+
+func (s *RoleService) ListRoles(ctx context.Context) ([]*RoleDetails, error) {
+	roles, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*RoleDetails, len(roles))
+	for i, role := range roles {
+		result[i] = toDetails(role)
+	}
+	return result, nil
+}
+
+func (s *RoleService) GetRole(ctx context.Context, query GetRoleQuery) (*RoleDetails, error) {
+	role, err := s.repo.FindByID(ctx, query.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toDetails(role), nil
+}
+
+func (s *RoleService) UpdateRole(ctx context.Context, cmd UpdateRoleCommand) (*RoleDetails, error) {
+	var role *domain.Role
+
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		role, err = s.repo.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := role.Update(cmd.Description, cmd.Permissions); err != nil {
+			return err
+		}
+		return s.repo.Save(ctx, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toDetails(role), nil
+}
+
+// DeleteRole remove o papel e, com ele, todas as suas atribuições
+func (s *RoleService) DeleteRole(ctx context.Context, cmd DeleteRoleCommand) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		role, err := s.repo.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, role)
+	})
+}
+
+// AssignRole atribui o papel ao usuário; repetir a atribuição não é um erro
+func (s *RoleService) AssignRole(ctx context.Context, cmd AssignRoleCommand) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.FindByID(ctx, cmd.RoleID); err != nil {
+			return err
+		}
+		return s.repo.Assign(ctx, cmd.RoleID, cmd.UserID)
+	})
+}
+
+// UnassignRole remove o papel do usuário; remover uma atribuição inexistente não é um erro
+func (s *RoleService) UnassignRole(ctx context.Context, cmd UnassignRoleCommand) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.FindByID(ctx, cmd.RoleID); err != nil {
+			return err
+		}
+		return s.repo.Unassign(ctx, cmd.RoleID, cmd.UserID)
+	})
+}
+
+// ListMembers lista os ids dos usuários com o papel
+func (s *RoleService) ListMembers(ctx context.Context, query ListMembersQuery) ([]string, error) {
+	if _, err := s.repo.FindByID(ctx, query.RoleID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindMembers(ctx, query.RoleID)
+}
+
+func toDetails(role *domain.Role) *RoleDetails {
+	return &RoleDetails{
+		ID:          role.ID(),
+		Name:        role.Name(),
+		Description: role.Description(),
+		Permissions: role.Permissions(),
+		CreatedAt:   role.CreatedAt(),
+	}
+}
+
+var _ domain.Authorizer = (*RoleService)(nil)
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+type MockRoleRepository struct {
+	roles       map[string]*domain.Role
+	assignments map[string][]string
+
+	FindByUserFunc func(ctx context.Context, userID string) ([]*domain.Role, error)
+}
+
+func NewMockRoleRepository() *MockRoleRepository {
+	return &MockRoleRepository{
+		roles:       make(map[string]*domain.Role),
+		assignments: make(map[string][]string),
+	}
+}
+
+func (m *MockRoleRepository) Save(ctx context.Context, role *domain.Role) error {
+	m.roles[role.ID()] = role
+	return nil
+}
+
+func (m *MockRoleRepository) FindByID(ctx context.Context, id string) (*domain.Role, error) {
+	role, ok := m.roles[id]
+	if !ok {
+		return nil, domain.ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func (m *MockRoleRepository) FindByName(ctx context.Context, name string) (*domain.Role, error) {
+	for _, role := range m.roles {
+		if role.Name() == name {
+			return role, nil
+		}
+	}
+	return nil, domain.ErrRoleNotFound
+}
+
+func (m *MockRoleRepository) FindAll(ctx context.Context) ([]*domain.Role, error) {
+	roles := make([]*domain.Role, 0, len(m.roles))
+	for _, role := range m.roles {
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func (m *MockRoleRepository) Delete(ctx context.Context, role *domain.Role) error {
+	delete(m.roles, role.ID())
+	delete(m.assignments, role.ID())
+	return nil
+}
+
+func (m *MockRoleRepository) Assign(ctx context.Context, roleID, userID string) error {
+	if !slices.Contains(m.assignments[roleID], userID) {
+		m.assignments[roleID] = append(m.assignments[roleID], userID)
+	}
+	return nil
+}
+
+func (m *MockRoleRepository) Unassign(ctx context.Context, roleID, userID string) error {
+	m.assignments[roleID] = slices.DeleteFunc(m.assignments[roleID], func(id string) bool { return id == userID })
+	return nil
+}
+
+func (m *MockRoleRepository) FindByUser(ctx context.Context, userID string) ([]*domain.Role, error) {
+	if m.FindByUserFunc != nil {
+		return m.FindByUserFunc(ctx, userID)
+	}
+	var roles []*domain.Role
+	for roleID, members := range m.assignments {
+		if slices.Contains(members, userID) {
+			roles = append(roles, m.roles[roleID])
+		}
+	}
+	return roles, nil
+}
+
+func (m *MockRoleRepository) FindMembers(ctx context.Context, roleID string) ([]string, error) {
+	return m.assignments[roleID], nil
+}
+
+var _ domain.RoleRepository = (*MockRoleRepository)(nil)
+
+type MockTransactionManager struct{}
+
+func (MockTransactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestCan(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*RoleService, *MockRoleRepository) {
+		repo := NewMockRoleRepository()
+		service := NewRoleService(repo, MockTransactionManager{})
+
+		role, err := service.CreateRole(ctx, CreateRoleCommand{Name: "support", Permissions: []string{"users:write"}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := service.AssignRole(ctx, AssignRoleCommand{RoleID: role.ID, UserID: "user-1"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return service, repo
+	}
+
+	tests := []struct {
+		name       string
+		principal  *auth.Principal
+		permission string
+		want       bool
+	}{
+		{name: "Role grants permission", principal: &auth.Principal{Subject: "user-1", Kind: auth.KindUser}, permission: "users:write", want: true},
+		{name: "Role does not grant other permissions", principal: &auth.Principal{Subject: "user-1", Kind: auth.KindUser}, permission: "users:admin", want: false},
+		{name: "User without roles", principal: &auth.Principal{Subject: "user-2", Kind: auth.KindUser}, permission: "users:write", want: false},
+		{name: "Credential scope still grants", principal: &auth.Principal{Subject: "key-1", Kind: auth.KindAPIKey, Scopes: []string{"users:admin"}}, permission: "users:write", want: true},
+		{name: "Roles are not looked up for API keys", principal: &auth.Principal{Subject: "user-1", Kind: auth.KindAPIKey}, permission: "users:write", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := setup(t)
+
+			got, err := service.Can(ctx, tt.principal, tt.permission)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("Storage failures are returned", func(t *testing.T) {
+		service, repo := setup(t)
+		outage := errors.New("connection refused")
+		repo.FindByUserFunc = func(ctx context.Context, userID string) ([]*domain.Role, error) {
+			return nil, outage
+		}
+
+		if _, err := service.Can(ctx, &auth.Principal{Subject: "user-1", Kind: auth.KindUser}, "users:write"); !errors.Is(err, outage) {
+			t.Errorf("Expected storage error, got %v", err)
+		}
+	})
+}
+
+func TestManageRoles(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Duplicate name", func(t *testing.T) {
+		service := NewRoleService(NewMockRoleRepository(), MockTransactionManager{})
+
+		if _, err := service.CreateRole(ctx, CreateRoleCommand{Name: "support", Permissions: []string{"users:read"}}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := service.CreateRole(ctx, CreateRoleCommand{Name: "support", Permissions: []string{"users:write"}}); !errors.Is(err, domain.ErrRoleAlreadyExists) {
+			t.Errorf("Expected ErrRoleAlreadyExists, got %v", err)
+		}
+	})
+
+	t.Run("Updated permissions apply immediately", func(t *testing.T) {
+		service := NewRoleService(NewMockRoleRepository(), MockTransactionManager{})
+		role, _ := service.CreateRole(ctx, CreateRoleCommand{Name: "support", Permissions: []string{"users:read"}})
+		_ = service.AssignRole(ctx, AssignRoleCommand{RoleID: role.ID, UserID: "user-1"})
+
+		if _, err := service.UpdateRole(ctx, UpdateRoleCommand{ID: role.ID, Permissions: []string{"users:admin"}}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		principal := &auth.Principal{Subject: "user-1", Kind: auth.KindUser}
+		if ok, _ := service.Can(ctx, principal, "users:write"); !ok {
+			t.Error("Expected updated role to grant users:write")
+		}
+	})
+
+	t.Run("Assigning is idempotent and unassigning revokes", func(t *testing.T) {
+		service := NewRoleService(NewMockRoleRepository(), MockTransactionManager{})
+		role, _ := service.CreateRole(ctx, CreateRoleCommand{Name: "support", Permissions: []string{"users:write"}})
+
+		for range 2 {
+			if err := service.AssignRole(ctx, AssignRoleCommand{RoleID: role.ID, UserID: "user-1"}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if members, _ := service.ListMembers(ctx, ListMembersQuery{RoleID: role.ID}); len(members) != 1 {
+			t.Errorf("Expected a single member, got %v", members)
+		}
+
+		if err := service.UnassignRole(ctx, UnassignRoleCommand{RoleID: role.ID, UserID: "user-1"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if roles, _ := service.UserRoles(ctx, "user-1"); len(roles) != 0 {
+			t.Errorf("Expected no roles left, got %d", len(roles))
+		}
+	})
+
+	t.Run("Unknown role", func(t *testing.T) {
+		service := NewRoleService(NewMockRoleRepository(), MockTransactionManager{})
+
+		if err := service.AssignRole(ctx, AssignRoleCommand{RoleID: "missing", UserID: "user-1"}); !errors.Is(err, domain.ErrRoleNotFound) {
+			t.Errorf("Expected ErrRoleNotFound, got %v", err)
+		}
+		if err := service.DeleteRole(ctx, DeleteRoleCommand{ID: "missing"}); !errors.Is(err, domain.ErrRoleNotFound) {
+			t.Errorf("Expected ErrRoleNotFound, got %v", err)
+		}
+	})
+}
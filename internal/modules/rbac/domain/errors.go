@@ -0,0 +1,11 @@
+package domain
+
+import "github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+
+// Erros do módulo rbac. Compare com errors.Is; os códigos são expostos na API e devem ser estáveis.
+var (
+	ErrRoleNotFound      = apperror.NotFound("ROLE_NOT_FOUND", "role not found")
+	ErrRoleAlreadyExists = apperror.Conflict("ROLE_ALREADY_EXISTS", "a role with this name already exists")
+	ErrInvalidRole       = apperror.Validation("INVALID_ROLE", "invalid role data")
+	ErrUserNotFound      = apperror.NotFound("USER_NOT_FOUND", "user not found")
+)
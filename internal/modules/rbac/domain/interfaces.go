@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+// AuthorizerContract é o nome do contrato Authorizer no registro de módulos
+const AuthorizerContract = "rbac.Authorizer"
+
+type RoleInfo struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// Authorizer responde se um principal pode executar uma ação sobre um recurso
+// (auth.Authorizer, usado por middleware.RequirePermission) e expõe os papéis dos usuários
+type Authorizer interface {
+	auth.Authorizer
+	UserRoles(ctx context.Context, userID string) ([]*RoleInfo, error)
+}
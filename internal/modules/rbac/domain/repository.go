@@ -0,0 +1,19 @@
+package domain
+
+import "context"
+
+type RoleRepository interface {
+	Save(ctx context.Context, role *Role) error
+	FindByID(ctx context.Context, id string) (*Role, error)
+	FindByName(ctx context.Context, name string) (*Role, error)
+	FindAll(ctx context.Context) ([]*Role, error)
+	Delete(ctx context.Context, role *Role) error
+
+	// Assign e Unassign são idempotentes
+	Assign(ctx context.Context, roleID, userID string) error
+	Unassign(ctx context.Context, roleID, userID string) error
+	// FindByUser lista os papéis atribuídos ao usuário
+	FindByUser(ctx context.Context, userID string) ([]*Role, error)
+	// FindMembers lista os ids dos usuários com o papel
+	FindMembers(ctx context.Context, roleID string) ([]string, error)
+}
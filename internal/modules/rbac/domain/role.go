@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+const (
+	MaxNameLength        = 64
+	MaxDescriptionLength = 255
+	// MaxPermissions limita a quantidade de permissões por papel
+	MaxPermissions = 64
+)
+
+var (
+	// namePattern aceita nomes como "admin" ou "support-agent"
+	namePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+	// permissionPattern aceita permissões no formato "<recurso>:<ação>", o mesmo dos escopos
+	permissionPattern = regexp.MustCompile(`^[a-z][a-z_]*:[a-z][a-z_]*$`)
+)
+
+// Role agrupa permissões "<recurso>:<ação>"; "<recurso>:admin" concede todas as ações do recurso
+type Role struct {
+	id          string
+	name        string
+	description string
+	permissions []string
+	createdAt   time.Time
+}
+
+func NewRole(name, description string, permissions []string, now time.Time) (*Role, error) {
+	name = strings.TrimSpace(name)
+	description = strings.TrimSpace(description)
+	permissions = normalizePermissions(permissions)
+
+	violations := append(validateName(name), validateDescription(description)...)
+	violations = append(violations, validatePermissions(permissions)...)
+	if len(violations) > 0 {
+		return nil, ErrInvalidRole.WithFields(violations...)
+	}
+
+	return &Role{
+		id:          uuid.New().String(),
+		name:        name,
+		description: description,
+		permissions: permissions,
+		createdAt:   now,
+	}, nil
+}
+
+func ReconstructRole(id, name, description string, permissions []string, createdAt time.Time) *Role {
+	return &Role{
+		id:          id,
+		name:        name,
+		description: description,
+		permissions: permissions,
+		createdAt:   createdAt,
+	}
+}
+
+func (r *Role) ID() string {
+	return r.id
+}
+
+func (r *Role) Name() string {
+	return r.name
+}
+
+func (r *Role) Description() string {
+	return r.description
+}
+
+// Permissions retorna uma cópia das permissões, ordenadas
+func (r *Role) Permissions() []string {
+	return slices.Clone(r.permissions)
+}
+
+func (r *Role) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+// Update substitui descrição e permissões; o nome é imutável
+func (r *Role) Update(description string, permissions []string) error {
+	description = strings.TrimSpace(description)
+	permissions = normalizePermissions(permissions)
+
+	violations := append(validateDescription(description), validatePermissions(permissions)...)
+	if len(violations) > 0 {
+		return ErrInvalidRole.WithFields(violations...)
+	}
+
+	r.description = description
+	r.permissions = permissions
+	return nil
+}
+
+// Grants informa se o papel concede permission
+func (r *Role) Grants(permission string) bool {
+	return auth.Grants(r.permissions, permission)
+}
+
+// normalizePermissions remove espaços e repetições e ordena as permissões
+func normalizePermissions(permissions []string) []string {
+	normalized := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		normalized = append(normalized, strings.TrimSpace(permission))
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+func validateName(name string) []apperror.FieldError {
+	switch {
+	case name == "":
+		return []apperror.FieldError{{Field: "name", Code: "required", Message: "name is required"}}
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		return []apperror.FieldError{{Field: "name", Code: "max", Message: fmt.Sprintf("name must be at most %d characters", MaxNameLength)}}
+	case !namePattern.MatchString(name):
+		return []apperror.FieldError{{Field: "name", Code: "format", Message: "name must contain only lowercase letters, digits, '-' and '_'"}}
+	}
+	return nil
+}
+
+func validateDescription(description string) []apperror.FieldError {
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return []apperror.FieldError{{Field: "description", Code: "max", Message: fmt.Sprintf("description must be at most %d characters", MaxDescriptionLength)}}
+	}
+	return nil
+}
+
+func validatePermissions(permissions []string) []apperror.FieldError {
+	if len(permissions) > MaxPermissions {
+		return []apperror.FieldError{{Field: "permissions", Code: "max", Message: fmt.Sprintf("at most %d permissions are allowed", MaxPermissions)}}
+	}
+
+	var violations []apperror.FieldError
+	for _, permission := range permissions {
+		if !permissionPattern.MatchString(permission) {
+			violations = append(violations, apperror.FieldError{
+				Field:   "permissions",
+				Code:    "format",
+				Message: fmt.Sprintf("permission %q must have the format <resource>:<action>", permission),
+			})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewRole(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Normalizes permissions", func(t *testing.T) {
+		role, err := NewRole(" support ", "Atendimento", []string{"users:write", " users:read", "users:write"}, now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if role.Name() != "support" {
+			t.Errorf("Expected trimmed name, got %q", role.Name())
+		}
+		if got := role.Permissions(); len(got) != 2 || got[0] != "users:read" || got[1] != "users:write" {
+			t.Errorf("Expected sorted unique permissions, got %v", got)
+		}
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			roleName    string
+			permissions []string
+		}{
+			{name: "Empty name", roleName: "", permissions: []string{"users:read"}},
+			{name: "Name with spaces", roleName: "Super Admin", permissions: []string{"users:read"}},
+			{name: "Malformed permission", roleName: "support", permissions: []string{"users"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := NewRole(tt.roleName, "", tt.permissions, now); !errors.Is(err, ErrInvalidRole) {
+					t.Errorf("Expected ErrInvalidRole, got %v", err)
+				}
+			})
+		}
+	})
+}
+
+func TestRoleGrants(t *testing.T) {
+	role, _ := NewRole("admin", "", []string{"users:admin", "roles:read"}, time.Now())
+
+	tests := []struct {
+		permission string
+		expected   bool
+	}{
+		{"users:write", true},
+		{"users:deactivate", true},
+		{"roles:read", true},
+		{"roles:write", false},
+		{"api_keys:read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.permission, func(t *testing.T) {
+			if got := role.Grants(tt.permission); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/app"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/problem"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/validation"
+)
+
+type RoleHandlers struct {
+	service *app.RoleService
+	keys    auth.APIKeyResolver
+	tokens  auth.TokenVerifier
+	limits  ratelimit.Limits
+}
+
+func NewRoleHandlers(service *app.RoleService, keys auth.APIKeyResolver, tokens auth.TokenVerifier, limits ratelimit.Limits) *RoleHandlers {
+	return &RoleHandlers{service: service, keys: keys, tokens: tokens, limits: limits}
+}
+
+func (h *RoleHandlers) CreateRole(c *gin.Context) {
+	var req CreateRoleRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	role, err := h.service.CreateRole(c.Request.Context(), app.CreateRoleCommand{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toResponse(role))
+}
+
+func (h *RoleHandlers) ListRoles(c *gin.Context) {
+	roles, err := h.service.ListRoles(c.Request.Context())
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	responses := make([]RoleResponse, len(roles))
+	for i, role := range roles {
+		responses[i] = toResponse(role)
+	}
+
+	c.JSON(http.StatusOK, RolesResponse{Roles: responses})
+}
+
+func (h *RoleHandlers) GetRole(c *gin.Context) {
+	role, err := h.service.GetRole(c.Request.Context(), app.GetRoleQuery{ID: c.Param("id")})
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toResponse(role))
+}
+
+func (h *RoleHandlers) UpdateRole(c *gin.Context) {
+	var req UpdateRoleRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	role, err := h.service.UpdateRole(c.Request.Context(), app.UpdateRoleCommand{
+		ID:          c.Param("id"),
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toResponse(role))
+}
+
+func (h *RoleHandlers) DeleteRole(c *gin.Context) {
+	if err := h.service.DeleteRole(c.Request.Context(), app.DeleteRoleCommand{ID: c.Param("id")}); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandlers) ListMembers(c *gin.Context) {
+	userIDs, err := h.service.ListMembers(c.Request.Context(), app.ListMembersQuery{RoleID: c.Param("id")})
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+	c.JSON(http.StatusOK, MembersResponse{UserIDs: userIDs})
+}
+
+func (h *RoleHandlers) AssignRole(c *gin.Context) {
+	cmd := app.AssignRoleCommand{RoleID: c.Param("id"), UserID: c.Param("user_id")}
+	if err := h.service.AssignRole(c.Request.Context(), cmd); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandlers) UnassignRole(c *gin.Context) {
+	cmd := app.UnassignRoleCommand{RoleID: c.Param("id"), UserID: c.Param("user_id")}
+	if err := h.service.UnassignRole(c.Request.Context(), cmd); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func toResponse(role *app.RoleDetails) RoleResponse {
+	return RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+		CreatedAt:   role.CreatedAt,
+	}
+}
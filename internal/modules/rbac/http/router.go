@@ -0,0 +1,26 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/app"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/middleware"
+)
+
+func (h *RoleHandlers) RegisterRoutes(router *gin.RouterGroup) {
+	// Gerenciar papéis exige roles:admin, como escopo da credencial ou por um papel do usuário
+	protected := router.Group("/",
+		middleware.Authenticate(h.keys, h.tokens),
+		middleware.RateLimit(h.limits.Store, h.limits.PerKey, middleware.ByPrincipal),
+		middleware.RequirePermission(h.service, app.ScopeAdmin),
+	)
+	{
+		protected.POST("/", h.CreateRole)
+		protected.GET("/", h.ListRoles)
+		protected.GET("/:id", h.GetRole)
+		protected.PUT("/:id", h.UpdateRole)
+		protected.DELETE("/:id", h.DeleteRole)
+		protected.GET("/:id/users", h.ListMembers)
+		protected.PUT("/:id/users/:user_id", h.AssignRole)
+		protected.DELETE("/:id/users/:user_id", h.UnassignRole)
+	}
+}
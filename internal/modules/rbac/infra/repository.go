@@ -0,0 +1,156 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleModel struct {
+	ID          string `gorm:"primaryKey"`
+	Name        string `gorm:"size:64;uniqueIndex"`
+	Description string
+	// Permissions guarda as permissões separadas por espaço, como os escopos das API keys
+	Permissions string
+	CreatedAt   int64
+}
+
+func (RoleModel) TableName() string {
+	return "roles"
+}
+
+type UserRoleModel struct {
+	RoleID    string `gorm:"primaryKey;size:36"`
+	UserID    string `gorm:"primaryKey;size:36;index"`
+	CreatedAt int64
+}
+
+func (UserRoleModel) TableName() string {
+	return "user_roles"
+}
+
+type GormRoleRepository struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+func NewGormRoleRepository(db *gorm.DB) *GormRoleRepository {
+	return &GormRoleRepository{db: db, now: time.Now}
+}
+
+func (r *GormRoleRepository) Save(ctx context.Context, role *domain.Role) error {
+	model := RoleModel{
+		ID:          role.ID(),
+		Name:        role.Name(),
+		Description: role.Description(),
+		Permissions: strings.Join(role.Permissions(), " "),
+		CreatedAt:   role.CreatedAt().Unix(),
+	}
+
+	err := transaction.DB(ctx, r.db).Save(&model).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrRoleAlreadyExists.Wrap(err)
+	}
+	return err
+}
+
+func (r *GormRoleRepository) FindByID(ctx context.Context, id string) (*domain.Role, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *GormRoleRepository) FindByName(ctx context.Context, name string) (*domain.Role, error) {
+	return r.findOne(ctx, "name = ?", name)
+}
+
+func (r *GormRoleRepository) FindAll(ctx context.Context) ([]*domain.Role, error) {
+	var models []RoleModel
+	if err := transaction.DB(ctx, r.db).Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return toDomainRoles(models), nil
+}
+
+// Delete remove o papel e suas atribuições
+func (r *GormRoleRepository) Delete(ctx context.Context, role *domain.Role) error {
+	conn := transaction.DB(ctx, r.db)
+	if err := conn.Where("role_id = ?", role.ID()).Delete(&UserRoleModel{}).Error; err != nil {
+		return err
+	}
+	return conn.Delete(&RoleModel{}, "id = ?", role.ID()).Error
+}
+
+func (r *GormRoleRepository) Assign(ctx context.Context, roleID, userID string) error {
+	model := UserRoleModel{RoleID: roleID, UserID: userID, CreatedAt: r.now().Unix()}
+
+	err := transaction.DB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&model).Error
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return domain.ErrUserNotFound.Wrap(err)
+	}
+	return err
+}
+
+func (r *GormRoleRepository) Unassign(ctx context.Context, roleID, userID string) error {
+	return transaction.DB(ctx, r.db).
+		Where("role_id = ? AND user_id = ?", roleID, userID).
+		Delete(&UserRoleModel{}).Error
+}
+
+func (r *GormRoleRepository) FindByUser(ctx context.Context, userID string) ([]*domain.Role, error) {
+	var models []RoleModel
+	err := transaction.DB(ctx, r.db).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDomainRoles(models), nil
+}
+
+func (r *GormRoleRepository) FindMembers(ctx context.Context, roleID string) ([]string, error) {
+	var userIDs []string
+	err := transaction.DB(ctx, r.db).
+		Model(&UserRoleModel{}).
+		Where("role_id = ?", roleID).
+		Order("created_at, user_id").
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (r *GormRoleRepository) findOne(ctx context.Context, query string, arg any) (*domain.Role, error) {
+	var model RoleModel
+	err := transaction.DB(ctx, r.db).First(&model, query, arg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomain(model), nil
+}
+
+func toDomainRoles(models []RoleModel) []*domain.Role {
+	roles := make([]*domain.Role, len(models))
+	for i, model := range models {
+		roles[i] = toDomain(model)
+	}
+	return roles
+}
+
+func toDomain(model RoleModel) *domain.Role {
+	return domain.ReconstructRole(
+		model.ID,
+		model.Name,
+		model.Description,
+		strings.Fields(model.Permissions),
+		time.Unix(model.CreatedAt, 0),
+	)
+}
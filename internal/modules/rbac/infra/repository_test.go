@@ -0,0 +1,85 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/domain"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func newTestRepository(t *testing.T) *GormRoleRepository {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "rbac.db")), &gorm.Config{
+		Logger:         gormlogger.Default.LogMode(gormlogger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&RoleModel{}, &UserRoleModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	return NewGormRoleRepository(db)
+}
+
+func TestRoleRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Assignments", func(t *testing.T) {
+		repo := newTestRepository(t)
+		admin, _ := domain.NewRole("admin", "", []string{"users:admin"}, time.Now())
+		support, _ := domain.NewRole("support", "", []string{"users:write"}, time.Now())
+		for _, role := range []*domain.Role{admin, support} {
+			if err := repo.Save(ctx, role); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		for range 2 {
+			if err := repo.Assign(ctx, support.ID(), "user-1"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		_ = repo.Assign(ctx, admin.ID(), "user-2")
+
+		roles, err := repo.FindByUser(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(roles) != 1 || roles[0].Name() != "support" || !roles[0].Grants("users:write") {
+			t.Errorf("Expected only the support role, got %v", roles)
+		}
+
+		if err := repo.Delete(ctx, support); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if members, _ := repo.FindMembers(ctx, support.ID()); len(members) != 0 {
+			t.Errorf("Expected assignments to be removed with the role, got %v", members)
+		}
+		if members, _ := repo.FindMembers(ctx, admin.ID()); len(members) != 1 || members[0] != "user-2" {
+			t.Errorf("Expected other assignments to be kept, got %v", members)
+		}
+	})
+
+	t.Run("Duplicate name", func(t *testing.T) {
+		repo := newTestRepository(t)
+		first, _ := domain.NewRole("support", "", []string{"users:read"}, time.Now())
+		second, _ := domain.NewRole("support", "", []string{"users:read"}, time.Now())
+
+		if err := repo.Save(ctx, first); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := repo.Save(ctx, second); !errors.Is(err, domain.ErrRoleAlreadyExists) {
+			t.Errorf("Expected ErrRoleAlreadyExists, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package rbac
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/app"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/http"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/infra"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/module"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"gorm.io/gorm"
+)
+
+type Module struct {
+	service  *app.RoleService
+	handlers *http.RoleHandlers
+}
+
+func NewModule(db *gorm.DB, tx transaction.Manager, keys auth.APIKeyResolver, tokens auth.TokenVerifier, limits ratelimit.Limits) *Module {
+	repo := infra.NewGormRoleRepository(db)
+	service := app.NewRoleService(repo, tx)
+	handlers := http.NewRoleHandlers(service, keys, tokens, limits)
+
+	return &Module{
+		service:  service,
+		handlers: handlers,
+	}
+}
+
+// Definition descreve o módulo para o registro de módulos. O rbac não depende do
+// módulo user (que consome o Authorizer); a remoção das atribuições de usuários
+// apagados fica a cargo da chave estrangeira de user_roles.
+func Definition() module.Definition {
+	return module.Definition{
+		Name:     "rbac",
+		Provides: []string{domain.AuthorizerContract},
+		Requires: []string{auth.APIKeyResolverContract, auth.TokenVerifierContract},
+		Setup: func(deps module.Dependencies) (module.Module, error) {
+			keys, err := module.Resolve[auth.APIKeyResolver](deps.Contracts, auth.APIKeyResolverContract)
+			if err != nil {
+				return nil, err
+			}
+			tokens, err := module.Resolve[auth.TokenVerifier](deps.Contracts, auth.TokenVerifierContract)
+			if err != nil {
+				return nil, err
+			}
+
+			return NewModule(deps.DB, deps.Transactions, keys, tokens, deps.RateLimits), nil
+		},
+	}
+}
+
+func (m *Module) RegisterRoutes(router *gin.RouterGroup) {
+	m.handlers.RegisterRoutes(router.Group("/roles"))
+}
+
+func (m *Module) Authorizer() domain.Authorizer {
+	return m.service
+}
+
+func (m *Module) Exports() map[string]any {
+	return map[string]any{
+		domain.AuthorizerContract: m.Authorizer(),
+	}
+}
+
+var (
+	_ module.Module   = (*Module)(nil)
+	_ module.Exporter = (*Module)(nil)
+)
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+)
+
+// AuthConfig reúne as políticas de login
+type AuthConfig struct {
+	Lockout        domain.LockoutPolicy
+	Sessions       domain.SessionPolicy
+	AccessTokenTTL time.Duration
+	// DefaultScopes são concedidos a todo token emitido no login
+	DefaultScopes []string
+}
+
+// AuthService autentica usuários por e-mail e senha, mantém as sessões de login e emite access tokens
+type AuthService struct {
+	repo     domain.UserRepository
+	sessions domain.SessionRepository
+	tx       transaction.Manager
+	hasher   domain.PasswordHasher
+	tokens   auth.TokenIssuer
+	authz    auth.Authorizer
+	throttle *LoginThrottle
+	cfg      AuthConfig
+	// dummyHash é verificado quando o e-mail não existe, para que a resposta leve
+	// o mesmo tempo e não revele quais e-mails estão cadastrados
+	dummyHash string
+	now       func() time.Time
+}
+
+func NewAuthService(repo domain.UserRepository, sessions domain.SessionRepository, tx transaction.Manager, hasher domain.PasswordHasher, tokens auth.TokenIssuer, authz auth.Authorizer, throttle *LoginThrottle, cfg AuthConfig) (*AuthService, error) {
+	dummyHash, err := hasher.Hash("dummy password for timing equalization")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthService{
+		repo:      repo,
+		sessions:  sessions,
+		tx:        tx,
+		hasher:    hasher,
+		tokens:    tokens,
+		authz:     authz,
+		throttle:  throttle,
+		cfg:       cfg,
+		dummyHash: dummyHash,
+		now:       time.Now,
+	}, nil
+}
+
+// Login confere as credenciais, abre uma sessão e emite access e refresh tokens. E-mail
+// desconhecido e senha errada retornam o mesmo erro. O contador de falhas é gravado
+// mesmo quando o login falha.
+func (s *AuthService) Login(ctx context.Context, cmd LoginCommand) (*Tokens, error) {
+	now := s.now()
+	if !s.throttle.Allow(cmd.ClientIP, now) {
+		return nil, ErrTooManyLoginAttempts
+	}
+
+	user, err := s.repo.FindByEmail(ctx, strings.TrimSpace(cmd.Email))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		_, _ = s.hasher.Verify(cmd.Password, s.dummyHash)
+		s.throttle.Fail(cmd.ClientIP, now)
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	authErr := user.Authenticate(cmd.Password, s.hasher, s.cfg.Lockout, now)
+	if err := s.tx.Do(ctx, func(ctx context.Context) error {
+		return s.repo.SaveCredentials(ctx, user)
+	}); err != nil {
+		return nil, err
+	}
+	if authErr != nil {
+		s.throttle.Fail(cmd.ClientIP, now)
+		return nil, authErr
+	}
+
+	// A senha só é conferida antes para não revelar o status a quem não a conhece
+	if err := checkActive(user); err != nil {
+		return nil, err
+	}
+
+	session, refreshToken, err := domain.NewSession(user.ID(), domain.SessionMetadata{UserAgent: cmd.UserAgent, IP: cmd.ClientIP}, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessions.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, session, refreshToken)
+}
+
+// checkActive só deixa passar usuários ativos; o pendente recebe um erro próprio para
+// que o cliente ofereça o reenvio da confirmação
+func checkActive(user *domain.User) error {
+	switch user.Status() {
+	case domain.StatusActive:
+		return nil
+	case domain.StatusPendingVerification:
+		return domain.ErrEmailNotVerified
+	}
+	return domain.ErrUserInactive
+}
+
+// issueTokens emite o access token da sessão e o devolve junto com o refresh token
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, session *domain.Session, refreshToken string) (*Tokens, error) {
+	principal := &auth.Principal{
+		Subject:   user.ID(),
+		Kind:      auth.KindUser,
+		Name:      user.Name(),
+		Scopes:    s.cfg.DefaultScopes,
+		SessionID: session.ID(),
+	}
+	accessToken, expiresAt, err := s.tokens.IssueToken(ctx, principal, s.cfg.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+		SessionID:    session.ID(),
+	}, nil
+}
+
+// ChangePassword troca a senha de um usuário e encerra as demais sessões dele. O próprio
+// usuário precisa informar a senha atual (quando já possui uma); administradores
+// (users:admin) podem redefini-la sem ela.
+func (s *AuthService) ChangePassword(ctx context.Context, cmd ChangePasswordCommand) error {
+	principal, self, err := authorizeUser(ctx, s.authz, cmd.UserID)
+	if err != nil {
+		return err
+	}
+
+	var verifyErr error
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		user, err := s.repo.FindByID(ctx, cmd.UserID)
+		if err != nil {
+			return err
+		}
+
+		if self && user.HasPassword() {
+			// Senha atual errada conta como tentativa de login; o contador é gravado
+			// e a transação segue sem erro
+			verifyErr = user.VerifyPassword(cmd.CurrentPassword, s.hasher, s.cfg.Lockout, s.now())
+			if verifyErr != nil {
+				return s.repo.SaveCredentials(ctx, user)
+			}
+		}
+
+		if err := user.SetPassword(cmd.NewPassword, s.hasher); err != nil {
+			return err
+		}
+		if err := s.repo.SaveCredentials(ctx, user); err != nil {
+			return err
+		}
+
+		// Quem tinha a senha antiga não deve continuar logado; a sessão atual é mantida
+		return s.revokeSessions(ctx, cmd.UserID, principal.SessionID, domain.RevokeReasonPassword)
+	})
+	if err != nil {
+		return err
+	}
+
+	if errors.Is(verifyErr, domain.ErrInvalidCredentials) {
+		return domain.ErrIncorrectPassword
+	}
+	return verifyErr
+}
+
+// authorizeUser permite operar sobre a conta userID ao próprio usuário ou a quem tem a
+// permissão users:admin (por escopo ou papel). self indica que o principal é o dono da conta.
+func authorizeUser(ctx context.Context, authz auth.Authorizer, userID string) (principal *auth.Principal, self bool, err error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, false, auth.ErrUnauthenticated
+	}
+
+	self = principal.Kind == auth.KindUser && principal.Subject == userID
+	if self {
+		return principal, true, nil
+	}
+
+	allowed, err := authz.Can(ctx, principal, ScopeAdmin)
+	if err != nil {
+		return nil, false, err
+	}
+	if !allowed {
+		return nil, false, auth.ErrPermissionDenied.WithMessage("missing required permission: " + ScopeAdmin)
+	}
+	return principal, false, nil
+}
@@ -0,0 +1,240 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+// MockTokenIssuer devolve o subject como token
+type MockTokenIssuer struct {
+	Issued []*auth.Principal
+}
+
+func (m *MockTokenIssuer) IssueToken(ctx context.Context, principal *auth.Principal, ttl time.Duration) (string, time.Time, error) {
+	m.Issued = append(m.Issued, principal)
+	return "token-" + principal.Subject, time.Now().Add(ttl), nil
+}
+
+// scopeAuthorizer concede apenas o que os escopos do principal concedem, como o rbac sem papéis
+type scopeAuthorizer struct{}
+
+func (scopeAuthorizer) Can(ctx context.Context, principal *auth.Principal, permission string) (bool, error) {
+	return principal.HasScope(permission), nil
+}
+
+const testPassword = "correct horse battery"
+
+var testAuthConfig = AuthConfig{
+	Lockout:        domain.LockoutPolicy{MaxFailedAttempts: 3, Duration: 15 * time.Minute},
+	Sessions:       domain.SessionPolicy{IdleTimeout: time.Hour, AbsoluteTimeout: 24 * time.Hour},
+	AccessTokenTTL: 15 * time.Minute,
+	DefaultScopes:  []string{ScopeWrite},
+}
+
+func newAuthService(t *testing.T, repo *MockUserRepository, throttle *LoginThrottle) (*AuthService, *MockTokenIssuer, *MockSessionRepository) {
+	t.Helper()
+
+	issuer := &MockTokenIssuer{}
+	sessions := NewMockSessionRepository()
+	service, err := NewAuthService(repo, sessions, MockTransactionManager{}, testHasher, issuer, scopeAuthorizer{}, throttle, testAuthConfig)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return service, issuer, sessions
+}
+
+func addUserWithPassword(t *testing.T, repo *MockUserRepository, email string) *domain.User {
+	t.Helper()
+
+	user, _ := domain.NewUser(email, "Ana")
+	if err := user.SetPassword(testPassword, testHasher); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := user.VerifyEmail("user:" + user.ID()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repo.AddUser(user)
+	return user
+}
+
+func TestLogin(t *testing.T) {
+	t.Run("Valid credentials issue a token", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		service, issuer, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+
+		token, err := service.Login(context.Background(), LoginCommand{Email: " ana@example.com ", Password: testPassword})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if token.AccessToken != "token-"+user.ID() || token.TokenType != "Bearer" || token.RefreshToken == "" {
+			t.Errorf("Unexpected token: %+v", token)
+		}
+		if principal := issuer.Issued[0]; principal.Kind != auth.KindUser || !principal.HasScope(ScopeWrite) {
+			t.Errorf("Unexpected principal: %+v", principal)
+		}
+		if user.Credentials().LastLoginAt == nil {
+			t.Error("Expected last login to be recorded")
+		}
+	})
+
+	t.Run("Wrong password and unknown email return the same error", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		addUserWithPassword(t, repo, "ana@example.com")
+		service, issuer, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+
+		_, wrongPassword := service.Login(context.Background(), LoginCommand{Email: "ana@example.com", Password: "wrong password!"})
+		_, unknownEmail := service.Login(context.Background(), LoginCommand{Email: "bia@example.com", Password: testPassword})
+
+		if !errors.Is(wrongPassword, domain.ErrInvalidCredentials) || !errors.Is(unknownEmail, domain.ErrInvalidCredentials) {
+			t.Errorf("Expected ErrInvalidCredentials for both, got %v and %v", wrongPassword, unknownEmail)
+		}
+		if len(issuer.Issued) != 0 {
+			t.Errorf("Expected no token issued, got %d", len(issuer.Issued))
+		}
+	})
+
+	t.Run("Repeated failures lock the account", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+
+		var err error
+		for range testAuthConfig.Lockout.MaxFailedAttempts {
+			_, err = service.Login(context.Background(), LoginCommand{Email: "ana@example.com", Password: "wrong password!"})
+		}
+		if !errors.Is(err, domain.ErrAccountLocked) {
+			t.Fatalf("Expected ErrAccountLocked, got %v", err)
+		}
+
+		if _, err := service.Login(context.Background(), LoginCommand{Email: "ana@example.com", Password: testPassword}); !errors.Is(err, domain.ErrAccountLocked) {
+			t.Errorf("Expected correct password to be refused while locked, got %v", err)
+		}
+		if !user.IsLockedOut(time.Now()) {
+			t.Error("Expected lockout to be persisted")
+		}
+	})
+
+	t.Run("Unverified user cannot log in", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user, _ := domain.NewUser("ana@example.com", "Ana")
+		_ = user.SetPassword(testPassword, testHasher)
+		repo.AddUser(user)
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+
+		_, err := service.Login(context.Background(), LoginCommand{Email: "ana@example.com", Password: testPassword})
+		if !errors.Is(err, domain.ErrEmailNotVerified) {
+			t.Errorf("Expected ErrEmailNotVerified, got %v", err)
+		}
+	})
+
+	t.Run("Suspended user cannot log in", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		user.Suspend("policy violation", "admin")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+
+		_, err := service.Login(context.Background(), LoginCommand{Email: "ana@example.com", Password: testPassword})
+		if !errors.Is(err, domain.ErrUserInactive) {
+			t.Errorf("Expected ErrUserInactive, got %v", err)
+		}
+	})
+
+	t.Run("Too many failures from the same address are throttled", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(2, time.Minute))
+
+		for _, email := range []string{"bia@example.com", "caio@example.com"} {
+			_, _ = service.Login(context.Background(), LoginCommand{Email: email, Password: testPassword, ClientIP: "10.0.0.1"})
+		}
+
+		_, err := service.Login(context.Background(), LoginCommand{Email: "ana@example.com", Password: testPassword, ClientIP: "10.0.0.1"})
+		if !errors.Is(err, ErrTooManyLoginAttempts) {
+			t.Errorf("Expected ErrTooManyLoginAttempts, got %v", err)
+		}
+
+		if _, err := service.Login(context.Background(), LoginCommand{Email: "ana@example.com", Password: testPassword, ClientIP: "10.0.0.2"}); err != nil {
+			t.Errorf("Expected other addresses not to be throttled, got %v", err)
+		}
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	const newPassword = "another long passphrase"
+
+	t.Run("User changes own password", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser})
+
+		err := service.ChangePassword(ctx, ChangePasswordCommand{UserID: user.ID(), CurrentPassword: testPassword, NewPassword: newPassword})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if ok, _ := testHasher.Verify(newPassword, user.Credentials().PasswordHash); !ok {
+			t.Error("Expected new password to be stored")
+		}
+		if names := repo.EventNames(); names[len(names)-1] != domain.UserPasswordChangedEvent {
+			t.Errorf("Expected %s event, got %v", domain.UserPasswordChangedEvent, names)
+		}
+	})
+
+	t.Run("Wrong current password", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser})
+
+		err := service.ChangePassword(ctx, ChangePasswordCommand{UserID: user.ID(), CurrentPassword: "wrong password!", NewPassword: newPassword})
+		if !errors.Is(err, domain.ErrIncorrectPassword) {
+			t.Errorf("Expected ErrIncorrectPassword, got %v", err)
+		}
+		if user.Credentials().FailedLogins != 1 {
+			t.Errorf("Expected failure to be counted, got %d", user.Credentials().FailedLogins)
+		}
+	})
+
+	t.Run("Admin resets another user's password", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "key-1", Kind: auth.KindAPIKey, Scopes: []string{ScopeAdmin}})
+
+		if err := service.ChangePassword(ctx, ChangePasswordCommand{UserID: user.ID(), NewPassword: newPassword}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Other users cannot change the password", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "someone-else", Kind: auth.KindUser, Scopes: []string{ScopeWrite}})
+
+		err := service.ChangePassword(ctx, ChangePasswordCommand{UserID: user.ID(), CurrentPassword: testPassword, NewPassword: newPassword})
+		if !errors.Is(err, auth.ErrPermissionDenied) {
+			t.Errorf("Expected ErrPermissionDenied, got %v", err)
+		}
+	})
+
+	t.Run("External token with the same subject is not the user", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindExternal, Scopes: []string{ScopeWrite}})
+
+		err := service.ChangePassword(ctx, ChangePasswordCommand{UserID: user.ID(), CurrentPassword: testPassword, NewPassword: newPassword})
+		if !errors.Is(err, auth.ErrPermissionDenied) {
+			t.Errorf("Expected ErrPermissionDenied, got %v", err)
+		}
+	})
+}
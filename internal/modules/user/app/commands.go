@@ -1,23 +1,114 @@
 package app
 
+import "github.com/vynazevedo/go-modular-monolith/internal/shared/patch"
+
 type CreateUserCommand struct {
 	Email string
 	Name  string
+	// Password é opcional; sem ele o usuário não consegue fazer login até definir uma senha
+	Password *string
 }
 
+// ExpectedVersion, quando presente, é a versão do usuário que o cliente leu (If-Match);
+// a alteração falha com ErrVersionMismatch se o usuário mudou desde então
 type UpdateUserCommand struct {
-	ID   string
-	Name string
+	ID              string
+	Name            string
+	ExpectedVersion *int64
+}
+
+// PatchUserCommand altera apenas os campos presentes; Null pede a remoção do valor
+type PatchUserCommand struct {
+	ID              string
+	Name            patch.Optional[string]
+	ExpectedVersion *int64
 }
 
 type DeleteUserCommand struct {
-	ID string
+	ID              string
+	ExpectedVersion *int64
+}
+
+type RestoreUserCommand struct {
+	ID              string
+	ExpectedVersion *int64
 }
 
 type ActivateUserCommand struct {
-	ID string
+	ID              string
+	ExpectedVersion *int64
 }
 
+// DeactivateUserCommand suspende o usuário; Reason é obrigatório
 type DeactivateUserCommand struct {
-	ID string
+	ID              string
+	Reason          string
+	ExpectedVersion *int64
+}
+
+// LockUserCommand bloqueia o usuário por segurança; Reason é obrigatório
+type LockUserCommand struct {
+	ID              string
+	Reason          string
+	ExpectedVersion *int64
+}
+
+type LoginCommand struct {
+	Email    string
+	Password string
+	// ClientIP identifica a origem para o limite de tentativas e, com UserAgent, descreve a sessão
+	ClientIP  string
+	UserAgent string
+}
+
+type RefreshCommand struct {
+	RefreshToken string
+	ClientIP     string
+	UserAgent    string
+}
+
+type LogoutCommand struct {
+	RefreshToken string
+}
+
+type RevokeSessionCommand struct {
+	UserID    string
+	SessionID string
+}
+
+type RevokeAllSessionsCommand struct {
+	UserID string
+}
+
+type ChangePasswordCommand struct {
+	UserID string
+	// CurrentPassword é exigido quando o próprio usuário troca a senha
+	CurrentPassword string
+	NewPassword     string
+}
+
+// VerifyEmailCommand consome o token recebido no link de confirmação
+type VerifyEmailCommand struct {
+	Token string
+}
+
+type ResendVerificationCommand struct {
+	Email string
+}
+
+// RequestEmailChangeCommand registra Email como novo e-mail pendente de confirmação
+type RequestEmailChangeCommand struct {
+	UserID          string
+	Email           string
+	ExpectedVersion *int64
+}
+
+// ConfirmEmailChangeCommand consome o token recebido no novo endereço
+type ConfirmEmailChangeCommand struct {
+	Token string
+}
+
+type CancelEmailChangeCommand struct {
+	UserID          string
+	ExpectedVersion *int64
 }
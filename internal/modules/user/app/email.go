@@ -0,0 +1,201 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/mail"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/signedtoken"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+var (
+	ErrInvalidVerificationToken = apperror.BadRequest("INVALID_VERIFICATION_TOKEN", "verification token is invalid")
+	ErrVerificationTokenExpired = apperror.BadRequest("VERIFICATION_TOKEN_EXPIRED", "verification token has expired, request a new one")
+	ErrVerificationThrottled    = apperror.RateLimited("VERIFICATION_RESEND_THROTTLED", "a verification email was sent recently, try again later")
+)
+
+// verifyEmailPurpose impede que tokens de outros fluxos confirmem o e-mail
+const verifyEmailPurpose = "verify_email"
+
+// VerificationConfig controla a confirmação de e-mail no cadastro e na troca de e-mail.
+// Com Required false os usuários são criados já ativos, sem envio de e-mail.
+type VerificationConfig struct {
+	Required bool
+	TokenTTL time.Duration
+	// ResendInterval é o intervalo mínimo entre dois envios para o mesmo e-mail
+	ResendInterval time.Duration
+	// URL é a página que recebe o token no parâmetro "token"
+	URL string
+	// ChangeURL é a página equivalente para a confirmação da troca de e-mail
+	ChangeURL string
+}
+
+// verificationClaims vinculam o token ao e-mail: trocar o e-mail invalida os tokens emitidos
+type verificationClaims struct {
+	UserID string `json:"uid"`
+	Email  string `json:"email"`
+}
+
+// EmailService envia e consome os links de confirmação de e-mail (cadastro e troca de e-mail)
+type EmailService struct {
+	repo    domain.UserRepository
+	tx      transaction.Manager
+	tokens  *signedtoken.Codec
+	mailer  mail.Sender
+	limiter ratelimit.Store
+	authz   auth.Authorizer
+	cfg     VerificationConfig
+	now     func() time.Time
+}
+
+func NewEmailService(repo domain.UserRepository, tx transaction.Manager, tokens *signedtoken.Codec, mailer mail.Sender, limiter ratelimit.Store, authz auth.Authorizer, cfg VerificationConfig) *EmailService {
+	return &EmailService{repo: repo, tx: tx, tokens: tokens, mailer: mailer, limiter: limiter, authz: authz, cfg: cfg, now: time.Now}
+}
+
+// SendVerification envia o link ao usuário recém-criado; chamado pelo assinante de
+// user.created. Usuários já confirmados ou excluídos são ignorados.
+func (s *EmailService) SendVerification(ctx context.Context, userID string) error {
+	user, err := s.repo.FindByID(ctx, userID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Status() != domain.StatusPendingVerification {
+		return nil
+	}
+
+	// Conta como um envio, para que o reenvio logo após o cadastro seja limitado
+	if _, err := s.limiter.Take(ctx, resendKey(user.Email()), s.resendPolicy()); err != nil {
+		logger.Warnf("Failed to record verification email for throttling: %v", err)
+	}
+
+	return s.sendVerification(ctx, user)
+}
+
+// ResendVerification reenvia o link para um usuário pendente. A resposta não revela se
+// o e-mail existe: e-mails desconhecidos ou já confirmados são ignorados em silêncio.
+func (s *EmailService) ResendVerification(ctx context.Context, cmd ResendVerificationCommand) error {
+	email := strings.TrimSpace(cmd.Email)
+
+	// O limite vale por e-mail, exista ele ou não, para não servir de oráculo
+	result, err := s.limiter.Take(ctx, resendKey(email), s.resendPolicy())
+	if err != nil {
+		return err
+	}
+	if !result.Allowed {
+		return ErrVerificationThrottled
+	}
+
+	user, err := s.repo.FindByEmail(ctx, email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Status() != domain.StatusPendingVerification {
+		return nil
+	}
+
+	return s.sendVerification(ctx, user)
+}
+
+// VerifyEmail consome o token do link e confirma o e-mail. O token é de uso único na
+// prática: depois de confirmado, o e-mail não pode ser confirmado de novo.
+func (s *EmailService) VerifyEmail(ctx context.Context, cmd VerifyEmailCommand) (*domain.UserInfo, error) {
+	var claims verificationClaims
+	err := s.tokens.Parse(verifyEmailPurpose, cmd.Token, &claims, s.now())
+	switch {
+	case errors.Is(err, signedtoken.ErrExpired):
+		return nil, ErrVerificationTokenExpired
+	case err != nil:
+		return nil, ErrInvalidVerificationToken
+	}
+
+	var user *domain.User
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.repo.FindByID(ctx, claims.UserID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return ErrInvalidVerificationToken
+		}
+		if err != nil {
+			return err
+		}
+		if user.Email() != claims.Email {
+			return ErrInvalidVerificationToken
+		}
+
+		if err := user.VerifyEmail(auth.KindUser + ":" + user.ID()); err != nil {
+			return err
+		}
+
+		return s.repo.Save(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserInfo(user), nil
+}
+
+func (s *EmailService) sendVerification(ctx context.Context, user *domain.User) error {
+	expiresAt := s.now().Add(s.cfg.TokenTTL)
+	token, err := s.tokens.Issue(verifyEmailPurpose, verificationClaims{UserID: user.ID(), Email: user.Email()}, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	link, err := withToken(s.cfg.URL, token)
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      user.Email(),
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm your email address by opening the link below:\n\n%s\n\nThe link expires at %s. If you did not sign up, ignore this message.\n",
+			user.Name(), link, expiresAt.UTC().Format(time.RFC1123)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{"user_id": user.ID()}).Info("Verification email sent")
+	return nil
+}
+
+func (s *EmailService) resendPolicy() ratelimit.Policy {
+	return ratelimit.Policy{Name: "verify_email", Requests: 1, Window: s.cfg.ResendInterval}
+}
+
+// resendKey normaliza o e-mail para que variações de caixa compartilhem o limite
+func resendKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// withToken acrescenta o token à query da URL configurada
+func withToken(base, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid confirmation URL: %w", err)
+	}
+
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,215 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/mail"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/signedtoken"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+var ErrEmailChangeThrottled = apperror.RateLimited("EMAIL_CHANGE_THROTTLED", "an email change was requested recently, try again later")
+
+// changeEmailPurpose separa os tokens da troca de e-mail dos de confirmação do cadastro
+const changeEmailPurpose = "change_email"
+
+// emailChangeClaims vinculam o token ao pedido: um novo pedido, o cancelamento ou a
+// própria troca invalidam os links já enviados
+type emailChangeClaims struct {
+	UserID      string `json:"uid"`
+	Email       string `json:"email"`
+	NewEmail    string `json:"new_email"`
+	RequestedAt int64  `json:"req"`
+}
+
+// RequestEmailChange registra o novo e-mail como pendente, para o próprio usuário ou
+// users:admin. A confirmação (novo endereço) e o aviso (endereço atual) saem depois do
+// commit, pelos assinantes de user.email_change_requested e user.email_change_notice.
+func (s *EmailService) RequestEmailChange(ctx context.Context, cmd RequestEmailChangeCommand) (*domain.UserInfo, error) {
+	if _, _, err := authorizeUser(ctx, s.authz, cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	var info *domain.UserInfo
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		info, err = mutateUser(ctx, s.repo, s.tx, cmd.UserID, cmd.ExpectedVersion, func(user *domain.User) error {
+			if err := user.RequestEmailChange(cmd.Email, actorOf(ctx)); err != nil {
+				return err
+			}
+			return s.ensureAvailable(ctx, user)
+		})
+		if err != nil {
+			return err
+		}
+
+		// Cada pedido envia dois e-mails; o limite só é consumido depois que o pedido foi
+		// gravado, e a recusa desfaz a gravação
+		result, err := s.limiter.Take(ctx, cmd.UserID, s.changePolicy())
+		if err != nil {
+			return err
+		}
+		if !result.Allowed {
+			return ErrEmailChangeThrottled
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// SendEmailChangeConfirmation envia o link ao novo endereço. Pedidos substituídos,
+// cancelados ou já confirmados são ignorados.
+func (s *EmailService) SendEmailChangeConfirmation(ctx context.Context, event domain.UserEmailChangeRequested) error {
+	user, err := s.pendingChange(ctx, event.UserID, event.NewEmail)
+	if err != nil || user == nil {
+		return err
+	}
+	requestedAt := user.EmailChangeRequestedAt()
+
+	expiresAt := s.now().Add(s.cfg.TokenTTL)
+	claims := emailChangeClaims{UserID: user.ID(), Email: user.Email(), NewEmail: user.PendingEmail(), RequestedAt: requestedAt.Unix()}
+	token, err := s.tokens.Issue(changeEmailPurpose, claims, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	link, err := withToken(s.cfg.ChangeURL, token)
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      claims.NewEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm %s as the new email address of your account by opening the link below:\n\n%s\n\nThe link expires at %s. If you did not request this change, ignore this message.\n",
+			user.Name(), claims.NewEmail, link, expiresAt.UTC().Format(time.RFC1123)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send email change confirmation: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{"user_id": user.ID()}).Info("Email change confirmation sent")
+	return nil
+}
+
+// SendEmailChangeNotice avisa o endereço atual do pedido; o aviso não tem link. Pedidos
+// substituídos, cancelados ou já confirmados são ignorados.
+func (s *EmailService) SendEmailChangeNotice(ctx context.Context, event domain.UserEmailChangeNotice) error {
+	user, err := s.pendingChange(ctx, event.UserID, event.NewEmail)
+	if err != nil || user == nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      user.Email(),
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf("Hi %s,\n\nA request was made to change the email address of your account from %s to %s. The change only takes effect once it is confirmed from the new address.\n\nIf you did not request it, sign in, cancel the change and change your password.\n",
+			user.Name(), user.Email(), user.PendingEmail()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send email change notice: %w", err)
+	}
+	return nil
+}
+
+// pendingChange carrega o usuário se newEmail ainda é a troca em andamento; nil caso contrário
+func (s *EmailService) pendingChange(ctx context.Context, userID, newEmail string) (*domain.User, error) {
+	user, err := s.repo.FindByID(ctx, userID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.EmailChangeRequestedAt() == nil || user.PendingEmail() != newEmail {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// ConfirmEmailChange consome o token do link e troca o e-mail. A unicidade é conferida de
+// novo: outro usuário pode ter ficado com o endereço desde o pedido.
+func (s *EmailService) ConfirmEmailChange(ctx context.Context, cmd ConfirmEmailChangeCommand) (*domain.UserInfo, error) {
+	var claims emailChangeClaims
+	err := s.tokens.Parse(changeEmailPurpose, cmd.Token, &claims, s.now())
+	switch {
+	case errors.Is(err, signedtoken.ErrExpired):
+		return nil, ErrVerificationTokenExpired
+	case err != nil:
+		return nil, ErrInvalidVerificationToken
+	}
+
+	var user *domain.User
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.repo.FindByID(ctx, claims.UserID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return ErrInvalidVerificationToken
+		}
+		if err != nil {
+			return err
+		}
+
+		requestedAt := user.EmailChangeRequestedAt()
+		if requestedAt == nil || requestedAt.Unix() != claims.RequestedAt ||
+			user.Email() != claims.Email || user.PendingEmail() != claims.NewEmail {
+			return ErrInvalidVerificationToken
+		}
+		if err := s.ensureAvailable(ctx, user); err != nil {
+			return err
+		}
+
+		if err := user.ConfirmEmailChange(auth.KindUser + ":" + user.ID()); err != nil {
+			return err
+		}
+
+		// O índice único do e-mail ainda barra a corrida entre a busca acima e a gravação
+		return s.repo.Save(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserInfo(user), nil
+}
+
+// CancelEmailChange descarta a troca pendente, para o próprio usuário ou users:admin
+func (s *EmailService) CancelEmailChange(ctx context.Context, cmd CancelEmailChangeCommand) (*domain.UserInfo, error) {
+	if _, _, err := authorizeUser(ctx, s.authz, cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	return mutateUser(ctx, s.repo, s.tx, cmd.UserID, cmd.ExpectedVersion, func(user *domain.User) error {
+		return user.CancelEmailChange(actorOf(ctx))
+	})
+}
+
+// ensureAvailable confere se o e-mail pendente não pertence a outro usuário
+func (s *EmailService) ensureAvailable(ctx context.Context, user *domain.User) error {
+	owner, err := s.repo.FindByEmail(ctx, user.PendingEmail())
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return nil
+	case err != nil:
+		return err
+	case owner.ID() != user.ID():
+		return domain.ErrEmailAlreadyExists
+	}
+	return nil
+}
+
+func (s *EmailService) changePolicy() ratelimit.Policy {
+	return ratelimit.Policy{Name: "change_email", Requests: 1, Window: s.cfg.ResendInterval}
+}
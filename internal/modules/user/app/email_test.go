@@ -0,0 +1,373 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/mail"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/signedtoken"
+)
+
+// MockMailSender guarda as mensagens enviadas
+type MockMailSender struct {
+	mu       sync.Mutex
+	Messages []mail.Message
+	SendFunc func(ctx context.Context, msg mail.Message) error
+}
+
+func (m *MockMailSender) Send(ctx context.Context, msg mail.Message) error {
+	if m.SendFunc != nil {
+		return m.SendFunc(ctx, msg)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Messages = append(m.Messages, msg)
+	return nil
+}
+
+var linkToken = regexp.MustCompile(`[?&]token=([^&\s]+)`)
+
+// tokenFrom extrai o token do link da última mensagem enviada
+func tokenFrom(t *testing.T, mailer *MockMailSender) string {
+	t.Helper()
+
+	if len(mailer.Messages) == 0 {
+		t.Fatal("Expected a message to be sent")
+	}
+	match := linkToken.FindStringSubmatch(mailer.Messages[len(mailer.Messages)-1].Body)
+	if match == nil {
+		t.Fatal("Expected a verification link in the message")
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return token
+}
+
+// tokenSentTo extrai o token do link da mensagem enviada para to
+func tokenSentTo(t *testing.T, mailer *MockMailSender, to string) string {
+	t.Helper()
+
+	for _, msg := range mailer.Messages {
+		if msg.To != to {
+			continue
+		}
+		if match := linkToken.FindStringSubmatch(msg.Body); match != nil {
+			token, err := url.QueryUnescape(match[1])
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			return token
+		}
+	}
+	t.Fatalf("Expected a message with a link to %s", to)
+	return ""
+}
+
+func newEmailService(repo *MockUserRepository) (*EmailService, *MockMailSender) {
+	mailer := &MockMailSender{}
+	links, _ := signedtoken.NewCodec(testSecret)
+	service := NewEmailService(repo, MockTransactionManager{}, links, mailer, ratelimit.NewMemoryStore(), scopeAuthorizer{}, testVerification)
+	return service, mailer
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Token from the email activates the user once", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, mailer := newEmailService(repo)
+		user, _ := domain.NewUser("ana@example.com", "Ana")
+		repo.AddUser(user)
+
+		if err := service.SendVerification(ctx, user.ID()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if mailer.Messages[0].To != "ana@example.com" {
+			t.Errorf("Expected the message to go to the user, got %s", mailer.Messages[0].To)
+		}
+
+		token := tokenFrom(t, mailer)
+		info, err := service.VerifyEmail(ctx, VerifyEmailCommand{Token: token})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if info.Status != domain.StatusActive.String() || info.EmailVerifiedAt == nil {
+			t.Errorf("Expected active user with verified email, got %s", info.Status)
+		}
+
+		if _, err := service.VerifyEmail(ctx, VerifyEmailCommand{Token: token}); !errors.Is(err, domain.ErrEmailAlreadyVerified) {
+			t.Errorf("Expected ErrEmailAlreadyVerified on reuse, got %v", err)
+		}
+	})
+
+	t.Run("Expired and tampered tokens are rejected", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, mailer := newEmailService(repo)
+		user, _ := domain.NewUser("ana@example.com", "Ana")
+		repo.AddUser(user)
+		_ = service.SendVerification(ctx, user.ID())
+		token := tokenFrom(t, mailer)
+
+		if _, err := service.VerifyEmail(ctx, VerifyEmailCommand{Token: token + "x"}); !errors.Is(err, ErrInvalidVerificationToken) {
+			t.Errorf("Expected ErrInvalidVerificationToken, got %v", err)
+		}
+
+		service.now = func() time.Time { return time.Now().Add(2 * testVerification.TokenTTL) }
+		if _, err := service.VerifyEmail(ctx, VerifyEmailCommand{Token: token}); !errors.Is(err, ErrVerificationTokenExpired) {
+			t.Errorf("Expected ErrVerificationTokenExpired, got %v", err)
+		}
+	})
+
+	t.Run("Active users are not emailed", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, mailer := newEmailService(repo)
+		user, _ := domain.NewUser("ana@example.com", "Ana")
+		_ = user.Activate(ActorSystem)
+		repo.AddUser(user)
+
+		if err := service.SendVerification(ctx, user.ID()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(mailer.Messages) != 0 {
+			t.Errorf("Expected no messages, got %d", len(mailer.Messages))
+		}
+	})
+}
+
+func TestResendVerification(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Resend is throttled per email", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, mailer := newEmailService(repo)
+		user, _ := domain.NewUser("ana@example.com", "Ana")
+		repo.AddUser(user)
+
+		if err := service.SendVerification(ctx, user.ID()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		err := service.ResendVerification(ctx, ResendVerificationCommand{Email: "ANA@example.com"})
+		if !errors.Is(err, ErrVerificationThrottled) {
+			t.Errorf("Expected ErrVerificationThrottled right after sign-up, got %v", err)
+		}
+		if len(mailer.Messages) != 1 {
+			t.Errorf("Expected a single message, got %d", len(mailer.Messages))
+		}
+	})
+
+	t.Run("Unknown email is ignored silently", func(t *testing.T) {
+		service, mailer := newEmailService(NewMockUserRepository())
+
+		if err := service.ResendVerification(ctx, ResendVerificationCommand{Email: "nobody@example.com"}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if len(mailer.Messages) != 0 {
+			t.Errorf("Expected no messages, got %d", len(mailer.Messages))
+		}
+	})
+
+	t.Run("Pending user receives a new link", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, mailer := newEmailService(repo)
+		user, _ := domain.NewUser("ana@example.com", "Ana")
+		repo.AddUser(user)
+
+		if err := service.ResendVerification(ctx, ResendVerificationCommand{Email: "ana@example.com"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := service.VerifyEmail(ctx, VerifyEmailCommand{Token: tokenFrom(t, mailer)}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+// changeEvents devolve os eventos do último pedido de troca de e-mail
+func changeEvents(t *testing.T, repo *MockUserRepository) (domain.UserEmailChangeRequested, domain.UserEmailChangeNotice) {
+	t.Helper()
+
+	if len(repo.Events) < 2 {
+		t.Fatalf("Expected the email change events, got %v", repo.Events)
+	}
+	requested, ok := repo.Events[len(repo.Events)-2].(domain.UserEmailChangeRequested)
+	notice, noticeOK := repo.Events[len(repo.Events)-1].(domain.UserEmailChangeNotice)
+	if !ok || !noticeOK {
+		t.Fatalf("Expected UserEmailChangeRequested and UserEmailChangeNotice, got %v", repo.Events)
+	}
+	return requested, notice
+}
+
+func TestEmailChange(t *testing.T) {
+	// requestChange pede a troca como o próprio usuário e envia os e-mails como o assinante faria
+	requestChange := func(t *testing.T, service *EmailService, repo *MockUserRepository, user *domain.User, email string) {
+		t.Helper()
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser})
+		if _, err := service.RequestEmailChange(ctx, RequestEmailChangeCommand{UserID: user.ID(), Email: email}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		requested, notice := changeEvents(t, repo)
+		if err := service.SendEmailChangeConfirmation(context.Background(), requested); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := service.SendEmailChangeNotice(context.Background(), notice); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	newUser := func(repo *MockUserRepository, email string) *domain.User {
+		user, _ := domain.NewUser(email, "Ana")
+		_ = user.VerifyEmail("user:" + user.ID())
+		repo.AddUser(user)
+		return user
+	}
+
+	t.Run("Confirmation from the new address swaps the email", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, mailer := newEmailService(repo)
+		user := newUser(repo, "ana@example.com")
+
+		requestChange(t, service, repo, user, "ana@new.example.com")
+		if len(mailer.Messages) != 2 || mailer.Messages[1].To != "ana@example.com" {
+			t.Fatalf("Expected a confirmation and a notice to the current address, got %v", mailer.Messages)
+		}
+		if linkToken.MatchString(mailer.Messages[1].Body) {
+			t.Error("Expected the notice to the current address to carry no link")
+		}
+
+		token := tokenSentTo(t, mailer, "ana@new.example.com")
+		info, err := service.ConfirmEmailChange(context.Background(), ConfirmEmailChangeCommand{Token: token})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if info.Email != "ana@new.example.com" || info.PendingEmail != "" {
+			t.Errorf("Expected email ana@new.example.com without pending change, got %s (%q)", info.Email, info.PendingEmail)
+		}
+
+		if _, err := service.ConfirmEmailChange(context.Background(), ConfirmEmailChangeCommand{Token: token}); !errors.Is(err, ErrInvalidVerificationToken) {
+			t.Errorf("Expected ErrInvalidVerificationToken on reuse, got %v", err)
+		}
+	})
+
+	t.Run("Address taken before the confirmation is rejected", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, mailer := newEmailService(repo)
+		user := newUser(repo, "ana@example.com")
+
+		requestChange(t, service, repo, user, "ana@new.example.com")
+		newUser(repo, "ana@new.example.com")
+
+		_, err := service.ConfirmEmailChange(context.Background(), ConfirmEmailChangeCommand{Token: tokenSentTo(t, mailer, "ana@new.example.com")})
+		if !errors.Is(err, domain.ErrEmailAlreadyExists) {
+			t.Errorf("Expected ErrEmailAlreadyExists, got %v", err)
+		}
+		if user.Email() != "ana@example.com" {
+			t.Errorf("Expected the email to be kept, got %s", user.Email())
+		}
+	})
+
+	t.Run("Cancelled and superseded requests invalidate the link", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, mailer := newEmailService(repo)
+		service.cfg.ResendInterval = time.Nanosecond
+		user := newUser(repo, "ana@example.com")
+
+		requestChange(t, service, repo, user, "first@example.com")
+		first := tokenSentTo(t, mailer, "first@example.com")
+		requestChange(t, service, repo, user, "second@example.com")
+
+		if _, err := service.ConfirmEmailChange(context.Background(), ConfirmEmailChangeCommand{Token: first}); !errors.Is(err, ErrInvalidVerificationToken) {
+			t.Errorf("Expected ErrInvalidVerificationToken for a superseded request, got %v", err)
+		}
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser})
+		if _, err := service.CancelEmailChange(ctx, CancelEmailChangeCommand{UserID: user.ID()}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		second := tokenSentTo(t, mailer, "second@example.com")
+		if _, err := service.ConfirmEmailChange(context.Background(), ConfirmEmailChangeCommand{Token: second}); !errors.Is(err, ErrInvalidVerificationToken) {
+			t.Errorf("Expected ErrInvalidVerificationToken after cancel, got %v", err)
+		}
+	})
+
+	t.Run("Failed notice is redelivered without a second confirmation", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, mailer := newEmailService(repo)
+		user := newUser(repo, "ana@example.com")
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser})
+		if _, err := service.RequestEmailChange(ctx, RequestEmailChangeCommand{UserID: user.ID(), Email: "ana@new.example.com"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		requested, notice := changeEvents(t, repo)
+
+		if err := service.SendEmailChangeConfirmation(context.Background(), requested); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		mailer.SendFunc = func(ctx context.Context, msg mail.Message) error {
+			return errors.New("smtp unavailable")
+		}
+		if err := service.SendEmailChangeNotice(context.Background(), notice); err == nil {
+			t.Fatal("Expected the failed notice to be reported for redelivery")
+		}
+
+		mailer.SendFunc = nil
+		if err := service.SendEmailChangeNotice(context.Background(), notice); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(mailer.Messages) != 2 || mailer.Messages[0].To != "ana@new.example.com" || mailer.Messages[1].To != "ana@example.com" {
+			t.Errorf("Expected one confirmation and one notice, got %v", mailer.Messages)
+		}
+	})
+
+	t.Run("Request checks ownership, availability and throttling", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, _ := newEmailService(repo)
+		user := newUser(repo, "ana@example.com")
+		newUser(repo, "taken@example.com")
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser})
+
+		other := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "someone-else", Kind: auth.KindUser})
+		if _, err := service.RequestEmailChange(other, RequestEmailChangeCommand{UserID: user.ID(), Email: "new@example.com"}); !errors.Is(err, auth.ErrPermissionDenied) {
+			t.Errorf("Expected ErrPermissionDenied, got %v", err)
+		}
+		if _, err := service.RequestEmailChange(ctx, RequestEmailChangeCommand{UserID: user.ID(), Email: "taken@example.com"}); !errors.Is(err, domain.ErrEmailAlreadyExists) {
+			t.Errorf("Expected ErrEmailAlreadyExists, got %v", err)
+		}
+
+		if _, err := service.RequestEmailChange(ctx, RequestEmailChangeCommand{UserID: user.ID(), Email: "new@example.com"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := service.RequestEmailChange(ctx, RequestEmailChangeCommand{UserID: user.ID(), Email: "other@example.com"}); !errors.Is(err, ErrEmailChangeThrottled) {
+			t.Errorf("Expected ErrEmailChangeThrottled, got %v", err)
+		}
+	})
+
+	t.Run("Failed save does not consume the throttle", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service, _ := newEmailService(repo)
+		user := newUser(repo, "ana@example.com")
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser})
+
+		repo.SaveFunc = func(ctx context.Context, user *domain.User) error {
+			return domain.ErrEmailAlreadyExists
+		}
+		if _, err := service.RequestEmailChange(ctx, RequestEmailChangeCommand{UserID: user.ID(), Email: "new@example.com"}); !errors.Is(err, domain.ErrEmailAlreadyExists) {
+			t.Fatalf("Expected ErrEmailAlreadyExists, got %v", err)
+		}
+
+		repo.SaveFunc = nil
+		if _, err := service.RequestEmailChange(ctx, RequestEmailChangeCommand{UserID: user.ID(), Email: "new@example.com"}); err != nil {
+			t.Errorf("Expected the retry to be allowed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+// purgeBatchSize limita quantos usuários cada DELETE apaga, para não segurar locks por muito tempo
+const purgeBatchSize = 500
+
+// PurgeConfig define a retenção dos usuários excluídos e a frequência do job.
+// Retention ou Interval zero desligam o job.
+type PurgeConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+// Purger apaga de vez, periodicamente, os usuários excluídos há mais de Retention
+type Purger struct {
+	repo domain.UserRepository
+	cfg  PurgeConfig
+	now  func() time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+}
+
+func NewPurger(repo domain.UserRepository, cfg PurgeConfig) *Purger {
+	return &Purger{repo: repo, cfg: cfg, now: time.Now}
+}
+
+func (p *Purger) enabled() bool {
+	return p.cfg.Retention > 0 && p.cfg.Interval > 0
+}
+
+// Start inicia o job em background
+func (p *Purger) Start(ctx context.Context) error {
+	if !p.enabled() {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		return errors.New("user purger already started")
+	}
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	p.cancel = cancel
+	p.done = make(chan struct{})
+
+	go p.run(runCtx)
+
+	logger.Infof("User purger started (retention %s, interval %s)", p.cfg.Retention, p.cfg.Interval)
+	return nil
+}
+
+// Stop interrompe o job e aguarda o lote em andamento até o prazo de ctx
+func (p *Purger) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel == nil {
+		return nil
+	}
+
+	p.cancel()
+	p.cancel = nil
+
+	select {
+	case <-p.done:
+		logger.Info("User purger stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("user purger stop: %w", ctx.Err())
+	}
+}
+
+func (p *Purger) run(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.PurgeDeleted(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Errorf("User purge failed: %v", err)
+		}
+		if purged > 0 {
+			logger.Infof("Purged %d deleted users", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeDeleted apaga, em lotes, todos os usuários excluídos antes do fim da retenção
+func (p *Purger) PurgeDeleted(ctx context.Context) (int64, error) {
+	before := p.now().Add(-p.cfg.Retention)
+
+	var total int64
+	for {
+		purged, err := p.repo.PurgeDeleted(ctx, before, purgeBatchSize)
+		total += purged
+		if err != nil || purged < purgeBatchSize {
+			return total, err
+		}
+	}
+}
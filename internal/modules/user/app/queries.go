@@ -1,5 +1,11 @@
 package app
 
+import (
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+)
+
 type GetUserQuery struct {
 	ID string
 }
@@ -11,4 +17,52 @@ type GetUserByEmailQuery struct {
 type ListUsersQuery struct {
 	Page  int
 	Limit int
+
+	// Filtros opcionais; valores vazios não filtram
+	Status        string
+	EmailPrefix   string
+	NameContains  string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Sort aceita campos separados por vírgula, "-" para decrescente (ex.: "-created_at,name")
+	Sort string
+	// IncludeDeleted inclui os usuários excluídos; o handler só permite a users:admin
+	IncludeDeleted bool
+
+	// UseCursor troca a paginação por página pela paginação por cursor (keyset).
+	// Cursor vazio começa do primeiro registro.
+	UseCursor bool
+	Cursor    string
+}
+
+// UserList é uma página de usuários. Total só é calculado na paginação por página;
+// NextCursor fica vazio quando não há próxima página.
+type UserList struct {
+	Users      []*domain.UserInfo
+	Total      int64
+	NextCursor string
+}
+
+// Tokens é o resultado do login e do refresh. O refresh token só aparece nesta resposta.
+type Tokens struct {
+	AccessToken  string
+	TokenType    string
+	ExpiresAt    time.Time
+	RefreshToken string
+	SessionID    string
+}
+
+type ListSessionsQuery struct {
+	UserID string
+}
+
+// SessionInfo é uma sessão ativa; Current marca a sessão do token usado na requisição
+type SessionInfo struct {
+	ID         string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  *time.Time
+	Current    bool
 }
@@ -0,0 +1,8 @@
+package app
+
+// Escopos das rotas de usuários. "users:admin" concede os demais. As leituras
+// (GET /users e /users/:id) são públicas e não têm escopo próprio.
+const (
+	ScopeWrite = "users:write"
+	ScopeAdmin = "users:admin"
+)
@@ -3,39 +3,80 @@ package app
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/pagination"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
 )
 
+// ActorSystem identifica mudanças de status feitas sem principal (jobs, chamadas internas)
+const ActorSystem = "system"
+
 type UserService struct {
-	repo domain.UserRepository
+	repo    domain.UserRepository
+	tx      transaction.Manager
+	cursors *pagination.CursorCodec
+	hasher  domain.PasswordHasher
+	// requireVerification deixa os usuários novos pendentes até confirmarem o e-mail
+	requireVerification bool
+}
+
+func NewUserService(repo domain.UserRepository, tx transaction.Manager, cursors *pagination.CursorCodec, hasher domain.PasswordHasher, verification VerificationConfig) *UserService {
+	return &UserService{repo: repo, tx: tx, cursors: cursors, hasher: hasher, requireVerification: verification.Required}
 }
 
-func NewUserService(repo domain.UserRepository) *UserService {
-	return &UserService{repo: repo}
+var errCursorSort = domain.ErrInvalidCriteria.WithFields(apperror.FieldError{
+	Field:   "sort",
+	Code:    "cursor",
+	Message: "cursor pagination only supports the default sort (created_at)",
+})
+
+// cursorPosition é o conteúdo assinado do cursor de listagem
+type cursorPosition struct {
+	CreatedAt int64  `json:"t"`
+	ID        string `json:"id"`
 }
 
 func (s *UserService) CreateUser(ctx context.Context, cmd CreateUserCommand) (*domain.UserInfo, error) {
-	existingUser, err := s.repo.FindByEmail(ctx, cmd.Email)
-	if err == nil && existingUser != nil {
-		return nil, errors.New("user with this email already exists")
-	}
+	var user *domain.User
 
-	user, err := domain.NewUser(cmd.Email, cmd.Name)
-	if err != nil {
-		return nil, err
-	}
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		// Sem verificação o cadastro já nasce ativo, como antes da confirmação de e-mail
+		if s.requireVerification {
+			user, err = domain.NewUser(cmd.Email, cmd.Name)
+		} else {
+			user, err = domain.NewActiveUser(cmd.Email, cmd.Name, ActorSystem)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Busca pelo e-mail já normalizado pelo domínio
+		_, err = s.repo.FindByEmail(ctx, user.Email())
+		switch {
+		case err == nil:
+			return domain.ErrEmailAlreadyExists
+		case !errors.Is(err, domain.ErrUserNotFound):
+			return err
+		}
 
-	if err := s.repo.Save(ctx, user); err != nil {
+		if cmd.Password != nil {
+			if err := user.SetPassword(*cmd.Password, s.hasher); err != nil {
+				return err
+			}
+		}
+
+		return s.repo.Save(ctx, user)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &domain.UserInfo{
-		ID:     user.ID(),
-		Email:  user.Email(),
-		Name:   user.Name(),
-		Status: user.Status().String(),
-	}, nil
+	return toUserInfo(user), nil
 }
 
 func (s *UserService) GetUser(ctx context.Context, query GetUserQuery) (*domain.UserInfo, error) {
@@ -44,12 +85,7 @@ func (s *UserService) GetUser(ctx context.Context, query GetUserQuery) (*domain.
 		return nil, err
 	}
 
-	return &domain.UserInfo{
-		ID:     user.ID(),
-		Email:  user.Email(),
-		Name:   user.Name(),
-		Status: user.Status().String(),
-	}, nil
+	return toUserInfo(user), nil
 }
 
 func (s *UserService) QueryUserByEmail(ctx context.Context, query GetUserByEmailQuery) (*domain.UserInfo, error) {
@@ -58,97 +94,189 @@ func (s *UserService) QueryUserByEmail(ctx context.Context, query GetUserByEmail
 		return nil, err
 	}
 
-	return &domain.UserInfo{
-		ID:     user.ID(),
-		Email:  user.Email(),
-		Name:   user.Name(),
-		Status: user.Status().String(),
-	}, nil
+	return toUserInfo(user), nil
 }
 
 func (s *UserService) UpdateUser(ctx context.Context, cmd UpdateUserCommand) (*domain.UserInfo, error) {
-	user, err := s.repo.FindByID(ctx, cmd.ID)
-	if err != nil {
-		return nil, err
-	}
+	return s.mutate(ctx, cmd.ID, cmd.ExpectedVersion, func(user *domain.User) error {
+		return user.UpdateName(cmd.Name)
+	})
+}
 
-	if err := user.UpdateName(cmd.Name); err != nil {
-		return nil, err
-	}
+// PatchUser aplica os campos presentes pelos métodos do domínio, na mesma transação;
+// campos obrigatórios não podem ser removidos com null
+func (s *UserService) PatchUser(ctx context.Context, cmd PatchUserCommand) (*domain.UserInfo, error) {
+	return s.mutate(ctx, cmd.ID, cmd.ExpectedVersion, func(user *domain.User) error {
+		if cmd.Name.Null {
+			return domain.ErrInvalidUser.WithFields(apperror.FieldError{
+				Field:   "name",
+				Code:    domain.FieldCodeRequired,
+				Message: "name cannot be removed",
+			})
+		}
+		if cmd.Name.Present {
+			return user.UpdateName(cmd.Name.Value)
+		}
+		return nil
+	})
+}
 
-	if err := s.repo.Save(ctx, user); err != nil {
+// DeleteUser exclui o usuário logicamente; PurgeDeleted o apaga de vez após a retenção
+func (s *UserService) DeleteUser(ctx context.Context, cmd DeleteUserCommand) error {
+	_, err := s.mutate(ctx, cmd.ID, cmd.ExpectedVersion, func(user *domain.User) error {
+		return user.Delete(actorOf(ctx))
+	})
+	return err
+}
+
+// RestoreUser desfaz a exclusão lógica de um usuário ainda não apagado de vez
+func (s *UserService) RestoreUser(ctx context.Context, cmd RestoreUserCommand) (*domain.UserInfo, error) {
+	var user *domain.User
+
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.repo.FindDeletedByID(ctx, cmd.ID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			// Distingue o usuário que existe, mas não está excluído, do inexistente
+			if _, findErr := s.repo.FindByID(ctx, cmd.ID); findErr == nil {
+				return domain.ErrUserNotDeleted
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if err := user.CheckVersion(cmd.ExpectedVersion); err != nil {
+			return err
+		}
+
+		if err := user.Restore(actorOf(ctx)); err != nil {
+			return err
+		}
+
+		return s.repo.Save(ctx, user)
+	})
+	if err := versionError(err, cmd.ExpectedVersion); err != nil {
 		return nil, err
 	}
 
-	return &domain.UserInfo{
-		ID:     user.ID(),
-		Email:  user.Email(),
-		Name:   user.Name(),
-		Status: user.Status().String(),
-	}, nil
+	return toUserInfo(user), nil
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, cmd DeleteUserCommand) error {
-	return s.repo.Delete(ctx, cmd.ID)
+func (s *UserService) ActivateUser(ctx context.Context, cmd ActivateUserCommand) (*domain.UserInfo, error) {
+	return s.mutate(ctx, cmd.ID, cmd.ExpectedVersion, func(user *domain.User) error {
+		return user.Activate(actorOf(ctx))
+	})
 }
 
-func (s *UserService) ActivateUser(ctx context.Context, cmd ActivateUserCommand) (*domain.UserInfo, error) {
-	user, err := s.repo.FindByID(ctx, cmd.ID)
+// DeactivateUser suspende o usuário; o motivo fica registrado na transição
+func (s *UserService) DeactivateUser(ctx context.Context, cmd DeactivateUserCommand) (*domain.UserInfo, error) {
+	return s.mutate(ctx, cmd.ID, cmd.ExpectedVersion, func(user *domain.User) error {
+		return user.Suspend(cmd.Reason, actorOf(ctx))
+	})
+}
+
+// LockUser bloqueia o usuário por segurança; só ActivateUser o libera
+func (s *UserService) LockUser(ctx context.Context, cmd LockUserCommand) (*domain.UserInfo, error) {
+	return s.mutate(ctx, cmd.ID, cmd.ExpectedVersion, func(user *domain.User) error {
+		return user.Lock(cmd.Reason, actorOf(ctx))
+	})
+}
+
+// actorOf identifica quem executa a operação; chamadas internas sem principal ficam como "system"
+func actorOf(ctx context.Context) string {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return ActorSystem
+	}
+	return principal.Kind + ":" + principal.Subject
+}
+
+func (s *UserService) ListUsers(ctx context.Context, query ListUsersQuery) (*UserList, error) {
+	criteria, err := toCriteria(query)
 	if err != nil {
 		return nil, err
 	}
 
-	user.Activate()
+	if query.UseCursor {
+		if !criteria.HasDefaultSort() {
+			return nil, errCursorSort
+		}
+		return s.listAfter(ctx, criteria, query)
+	}
 
-	if err := s.repo.Save(ctx, user); err != nil {
+	users, err := s.repo.FindAll(ctx, criteria, query.Page, query.Limit)
+	if err != nil {
 		return nil, err
 	}
 
-	return &domain.UserInfo{
-		ID:     user.ID(),
-		Email:  user.Email(),
-		Name:   user.Name(),
-		Status: user.Status().String(),
-	}, nil
-}
-
-func (s *UserService) DeactivateUser(ctx context.Context, cmd DeactivateUserCommand) (*domain.UserInfo, error) {
-	user, err := s.repo.FindByID(ctx, cmd.ID)
+	total, err := s.repo.Count(ctx, criteria)
 	if err != nil {
 		return nil, err
 	}
 
-	user.Deactivate()
+	list := &UserList{Users: toUserInfos(users), Total: total}
 
-	if err := s.repo.Save(ctx, user); err != nil {
-		return nil, err
+	// Na ordenação padrão as duas paginações coincidem, e o cliente pode seguir por cursor a partir daqui
+	if criteria.HasDefaultSort() && int64(query.Page)*int64(query.Limit) < total && len(users) > 0 {
+		if list.NextCursor, err = s.encodeCursor(users[len(users)-1]); err != nil {
+			return nil, err
+		}
 	}
 
-	return &domain.UserInfo{
-		ID:     user.ID(),
-		Email:  user.Email(),
-		Name:   user.Name(),
-		Status: user.Status().String(),
-	}, nil
+	return list, nil
 }
 
-func (s *UserService) ListUsers(ctx context.Context, query ListUsersQuery) ([]*domain.UserInfo, error) {
-	users, err := s.repo.FindAll(ctx, query.Page, query.Limit)
+// listAfter busca um registro além do limite para saber se existe próxima página sem COUNT
+func (s *UserService) listAfter(ctx context.Context, criteria domain.UserCriteria, query ListUsersQuery) (*UserList, error) {
+	var after *domain.UserCursor
+	if query.Cursor != "" {
+		var position cursorPosition
+		if err := s.cursors.Decode(query.Cursor, &position); err != nil {
+			return nil, err
+		}
+		after = &domain.UserCursor{CreatedAt: time.Unix(position.CreatedAt, 0), ID: position.ID}
+	}
+
+	users, err := s.repo.FindAfter(ctx, criteria, after, query.Limit+1)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*domain.UserInfo, len(users))
-	for i, user := range users {
-		result[i] = &domain.UserInfo{
-			ID:     user.ID(),
-			Email:  user.Email(),
-			Name:   user.Name(),
-			Status: user.Status().String(),
+	list := &UserList{}
+	if len(users) > query.Limit {
+		users = users[:query.Limit]
+		if list.NextCursor, err = s.encodeCursor(users[len(users)-1]); err != nil {
+			return nil, err
 		}
 	}
+	list.Users = toUserInfos(users)
+
+	return list, nil
+}
+
+func toCriteria(query ListUsersQuery) (domain.UserCriteria, error) {
+	sort, err := domain.ParseSort(query.Sort)
+	if err != nil {
+		return domain.UserCriteria{}, err
+	}
+
+	criteria := domain.UserCriteria{
+		Status:        domain.Status(query.Status),
+		EmailPrefix:   query.EmailPrefix,
+		NameContains:  query.NameContains,
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+		Sort:          sort,
+
+		IncludeDeleted: query.IncludeDeleted,
+	}
 
-	return result, nil
+	return criteria, criteria.Validate()
+}
+
+func (s *UserService) encodeCursor(user *domain.User) (string, error) {
+	cursor := domain.CursorOf(user)
+	return s.cursors.Encode(cursorPosition{CreatedAt: cursor.CreatedAt.Unix(), ID: cursor.ID})
 }
 
 func (s *UserService) GetUserInfo(ctx context.Context, id string) (*domain.UserInfo, error) {
@@ -158,3 +286,74 @@ func (s *UserService) GetUserInfo(ctx context.Context, id string) (*domain.UserI
 func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*domain.UserInfo, error) {
 	return s.QueryUserByEmail(ctx, GetUserByEmailQuery{Email: email})
 }
+
+// mutate carrega o usuário, confere a versão esperada, aplica a alteração e persiste,
+// tudo na mesma transação
+func (s *UserService) mutate(ctx context.Context, id string, expected *int64, change func(user *domain.User) error) (*domain.UserInfo, error) {
+	return mutateUser(ctx, s.repo, s.tx, id, expected, change)
+}
+
+// mutateUser é o mutate compartilhado pelos serviços do módulo
+func mutateUser(ctx context.Context, repo domain.UserRepository, tx transaction.Manager, id string, expected *int64, change func(user *domain.User) error) (*domain.UserInfo, error) {
+	var user *domain.User
+
+	err := tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := user.CheckVersion(expected); err != nil {
+			return err
+		}
+
+		if err := change(user); err != nil {
+			return err
+		}
+
+		return repo.Save(ctx, user)
+	})
+	if err := versionError(err, expected); err != nil {
+		return nil, err
+	}
+
+	return toUserInfo(user), nil
+}
+
+// versionError trata a gravação concorrente como falha da pré-condição quando o cliente
+// informou a versão: para ele, o usuário mudou depois da leitura
+func versionError(err error, expected *int64) error {
+	if expected != nil && errors.Is(err, domain.ErrVersionConflict) {
+		return domain.ErrVersionMismatch.Wrap(err)
+	}
+	return err
+}
+
+func toUserInfos(users []*domain.User) []*domain.UserInfo {
+	result := make([]*domain.UserInfo, len(users))
+	for i, user := range users {
+		result[i] = toUserInfo(user)
+	}
+	return result
+}
+
+func toUserInfo(user *domain.User) *domain.UserInfo {
+	info := &domain.UserInfo{
+		ID:              user.ID(),
+		Email:           user.Email(),
+		Name:            user.Name(),
+		Status:          user.Status().String(),
+		EmailVerifiedAt: user.EmailVerifiedAt(),
+		PendingEmail:    user.PendingEmail(),
+		Version:         user.Version(),
+		DeletedAt:       user.DeletedAt(),
+	}
+
+	if change := user.LastStatusChange(); !change.At.IsZero() {
+		info.StatusReason = change.Reason
+		info.StatusChangedBy = change.Actor
+		info.StatusChangedAt = &change.At
+	}
+
+	return info
+}
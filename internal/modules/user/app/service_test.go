@@ -4,19 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/events"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/pagination"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/password"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/patch"
 )
 
 type MockUserRepository struct {
-	users map[string]*domain.User
+	users  map[string]*domain.User
+	Events []events.Event
 
 	SaveFunc        func(ctx context.Context, user *domain.User) error
 	FindByIDFunc    func(ctx context.Context, id string) (*domain.User, error)
 	FindByEmailFunc func(ctx context.Context, email string) (*domain.User, error)
-	FindAllFunc     func(ctx context.Context, page, limit int) ([]*domain.User, error)
-	DeleteFunc      func(ctx context.Context, id string) error
+	FindAllFunc     func(ctx context.Context, criteria domain.UserCriteria, page, limit int) ([]*domain.User, error)
+	FindAfterFunc   func(ctx context.Context, criteria domain.UserCriteria, after *domain.UserCursor, limit int) ([]*domain.User, error)
+	CountFunc       func(ctx context.Context, criteria domain.UserCriteria) (int64, error)
+	PurgeFunc       func(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 func NewMockUserRepository() *MockUserRepository {
@@ -30,16 +41,29 @@ func (m *MockUserRepository) Save(ctx context.Context, user *domain.User) error
 		return m.SaveFunc(ctx, user)
 	}
 	m.users[user.ID()] = user
+	m.Events = append(m.Events, user.PullEvents()...)
 	return nil
 }
 
+func (m *MockUserRepository) SaveCredentials(ctx context.Context, user *domain.User) error {
+	return m.Save(ctx, user)
+}
+
 func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
 	if m.FindByIDFunc != nil {
 		return m.FindByIDFunc(ctx, id)
 	}
 	user, exists := m.users[id]
-	if !exists {
-		return nil, errors.New("user not found")
+	if !exists || user.DeletedAt() != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (m *MockUserRepository) FindDeletedByID(ctx context.Context, id string) (*domain.User, error) {
+	user, exists := m.users[id]
+	if !exists || user.DeletedAt() == nil {
+		return nil, domain.ErrUserNotFound
 	}
 	return user, nil
 }
@@ -49,22 +73,19 @@ func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*do
 		return m.FindByEmailFunc(ctx, email)
 	}
 	for _, user := range m.users {
-		if user.Email() == email {
+		if user.Email() == email && user.DeletedAt() == nil {
 			return user, nil
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, domain.ErrUserNotFound
 }
 
-func (m *MockUserRepository) FindAll(ctx context.Context, page, limit int) ([]*domain.User, error) {
+func (m *MockUserRepository) FindAll(ctx context.Context, criteria domain.UserCriteria, page, limit int) ([]*domain.User, error) {
 	if m.FindAllFunc != nil {
-		return m.FindAllFunc(ctx, page, limit)
+		return m.FindAllFunc(ctx, criteria, page, limit)
 	}
 
-	users := make([]*domain.User, 0, len(m.users))
-	for _, user := range m.users {
-		users = append(users, user)
-	}
+	users := m.matching(criteria)
 
 	start := (page - 1) * limit
 	end := start + limit
@@ -80,17 +101,78 @@ func (m *MockUserRepository) FindAll(ctx context.Context, page, limit int) ([]*d
 	return users[start:end], nil
 }
 
-func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
-	if m.DeleteFunc != nil {
-		return m.DeleteFunc(ctx, id)
+func (m *MockUserRepository) FindAfter(ctx context.Context, criteria domain.UserCriteria, after *domain.UserCursor, limit int) ([]*domain.User, error) {
+	if m.FindAfterFunc != nil {
+		return m.FindAfterFunc(ctx, criteria, after, limit)
 	}
 
-	if _, exists := m.users[id]; !exists {
-		return errors.New("user not found")
+	users := make([]*domain.User, 0, len(m.users))
+	for _, user := range m.matching(criteria) {
+		if after == nil || isAfter(domain.CursorOf(user), *after) {
+			users = append(users, user)
+		}
 	}
 
-	delete(m.users, id)
-	return nil
+	sort.Slice(users, func(i, j int) bool {
+		return isAfter(domain.CursorOf(users[j]), domain.CursorOf(users[i]))
+	})
+
+	return users[:min(limit, len(users))], nil
+}
+
+// isAfter reproduz a ordenação (created_at, id) do repositório real
+func isAfter(a, b domain.UserCursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID > b.ID
+}
+
+func (m *MockUserRepository) Count(ctx context.Context, criteria domain.UserCriteria) (int64, error) {
+	if m.CountFunc != nil {
+		return m.CountFunc(ctx, criteria)
+	}
+	return int64(len(m.matching(criteria))), nil
+}
+
+// matching aplica apenas os filtros de status e de excluídos, suficientes para os testes do serviço
+func (m *MockUserRepository) matching(criteria domain.UserCriteria) []*domain.User {
+	users := make([]*domain.User, 0, len(m.users))
+	for _, user := range m.users {
+		if user.DeletedAt() != nil && !criteria.IncludeDeleted {
+			continue
+		}
+		if criteria.Status == "" || user.Status() == criteria.Status {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+func (m *MockUserRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if m.PurgeFunc != nil {
+		return m.PurgeFunc(ctx, before, limit)
+	}
+
+	var purged int64
+	for id, user := range m.users {
+		if purged == int64(limit) {
+			break
+		}
+		if deletedAt := user.DeletedAt(); deletedAt != nil && deletedAt.Before(before) {
+			delete(m.users, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (m *MockUserRepository) EventNames() []string {
+	names := make([]string, len(m.Events))
+	for i, event := range m.Events {
+		names[i] = event.EventName()
+	}
+	return names
 }
 
 func (m *MockUserRepository) AddUser(user *domain.User) {
@@ -99,10 +181,27 @@ func (m *MockUserRepository) AddUser(user *domain.User) {
 
 var _ domain.UserRepository = (*MockUserRepository)(nil)
 
+// testSecret tem o tamanho mínimo exigido pelos codecs assinados
+const testSecret = "test-secret-with-at-least-32-bytes"
+
+var testCursors, _ = pagination.NewCursorCodec(testSecret)
+
+var testVerification = VerificationConfig{Required: true, TokenTTL: time.Hour, ResendInterval: time.Minute, URL: "http://localhost:3000/verify-email", ChangeURL: "http://localhost:3000/confirm-email-change"}
+
+// testHasher usa bcrypt com custo mínimo para os testes não ficarem lentos
+var testHasher, _ = password.NewHasher(password.Config{Algorithm: password.AlgorithmBcrypt, BcryptCost: 4})
+
+// MockTransactionManager executa o caso de uso sem transação real
+type MockTransactionManager struct{}
+
+func (MockTransactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 func TestCreateUser(t *testing.T) {
 	t.Run("Create valid user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		cmd := CreateUserCommand{
 			Email: "test@example.com",
@@ -127,21 +226,76 @@ func TestCreateUser(t *testing.T) {
 			t.Errorf("Expected name %s, got %s", cmd.Name, user.Name)
 		}
 
-		if user.Status != domain.StatusActive.String() {
-			t.Errorf("Expected status %s, got %s", domain.StatusActive, user.Status)
+		if user.Status != domain.StatusPendingVerification.String() {
+			t.Errorf("Expected status %s, got %s", domain.StatusPendingVerification, user.Status)
+		}
+	})
+
+	t.Run("Without verification the user starts active", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, VerificationConfig{Required: false})
+
+		user, err := service.CreateUser(context.Background(), CreateUserCommand{Email: "test@example.com", Name: "Test User"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if user.Status != domain.StatusActive.String() || user.EmailVerifiedAt != nil {
+			t.Errorf("Expected active user without verified email, got %s", user.Status)
+		}
+		if len(repo.Events) != 1 {
+			t.Fatalf("Expected only the creation event, got %d events", len(repo.Events))
+		}
+		if created, ok := repo.Events[0].(domain.UserCreated); !ok || created.Status != domain.StatusActive.String() {
+			t.Errorf("Expected UserCreated with status %s, got %+v", domain.StatusActive, repo.Events[0])
+		}
+	})
+
+	t.Run("Create user with password", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		secret := "correct horse battery"
+		user, err := service.CreateUser(context.Background(), CreateUserCommand{
+			Email:    "test@example.com",
+			Name:     "Test User",
+			Password: &secret,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		stored, _ := repo.FindByID(context.Background(), user.ID)
+		if ok, _ := testHasher.Verify(secret, stored.Credentials().PasswordHash); !ok {
+			t.Error("Expected stored hash to match the password")
+		}
+	})
+
+	t.Run("Create user with weak password", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		weak := "short"
+		_, err := service.CreateUser(context.Background(), CreateUserCommand{
+			Email:    "test@example.com",
+			Name:     "Test User",
+			Password: &weak,
+		})
+		if !errors.Is(err, domain.ErrInvalidPassword) {
+			t.Errorf("Expected ErrInvalidPassword, got %v", err)
 		}
 	})
 
 	t.Run("Create user with existing email", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		existingUser, _ := domain.NewUser("test@example.com", "Existing User")
 		repo.FindByEmailFunc = func(ctx context.Context, email string) (*domain.User, error) {
 			if email == "test@example.com" {
 				return existingUser, nil
 			}
-			return nil, errors.New("user not found")
+			return nil, domain.ErrUserNotFound
 		}
 
 		cmd := CreateUserCommand{
@@ -151,8 +305,8 @@ func TestCreateUser(t *testing.T) {
 
 		user, err := service.CreateUser(context.Background(), cmd)
 
-		if err == nil {
-			t.Error("Expected error for duplicate email, got nil")
+		if !errors.Is(err, domain.ErrEmailAlreadyExists) {
+			t.Errorf("Expected ErrEmailAlreadyExists, got %v", err)
 		}
 
 		if user != nil {
@@ -162,7 +316,7 @@ func TestCreateUser(t *testing.T) {
 
 	t.Run("Create user with invalid data", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		cmd := CreateUserCommand{
 			Email: "",
@@ -171,8 +325,8 @@ func TestCreateUser(t *testing.T) {
 
 		user, err := service.CreateUser(context.Background(), cmd)
 
-		if err == nil {
-			t.Error("Expected error for invalid data, got nil")
+		if !errors.Is(err, domain.ErrInvalidUser) {
+			t.Errorf("Expected ErrInvalidUser, got %v", err)
 		}
 
 		if user != nil {
@@ -180,9 +334,28 @@ func TestCreateUser(t *testing.T) {
 		}
 	})
 
+	t.Run("Create user with lookup failure", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		lookupErr := errors.New("connection refused")
+		repo.FindByEmailFunc = func(ctx context.Context, email string) (*domain.User, error) {
+			return nil, lookupErr
+		}
+
+		_, err := service.CreateUser(context.Background(), CreateUserCommand{
+			Email: "test@example.com",
+			Name:  "Test User",
+		})
+
+		if !errors.Is(err, lookupErr) {
+			t.Errorf("Expected lookup error to be propagated, got %v", err)
+		}
+	})
+
 	t.Run("Create user with repository error", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		repo.SaveFunc = func(ctx context.Context, user *domain.User) error {
 			return errors.New("database error")
@@ -208,7 +381,7 @@ func TestCreateUser(t *testing.T) {
 func TestGetUser(t *testing.T) {
 	t.Run("Get existing user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		testUser, _ := domain.NewUser("test@example.com", "Test User")
 		repo.AddUser(testUser)
@@ -240,14 +413,14 @@ func TestGetUser(t *testing.T) {
 
 	t.Run("Get non-existent user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		query := GetUserQuery{ID: "non-existent-id"}
 
 		user, err := service.GetUser(context.Background(), query)
 
-		if err == nil {
-			t.Error("Expected error for non-existent user, got nil")
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound, got %v", err)
 		}
 
 		if user != nil {
@@ -259,7 +432,7 @@ func TestGetUser(t *testing.T) {
 func TestUpdateUser(t *testing.T) {
 	t.Run("Update existing user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		testUser, _ := domain.NewUser("test@example.com", "Original Name")
 		repo.AddUser(testUser)
@@ -291,7 +464,7 @@ func TestUpdateUser(t *testing.T) {
 
 	t.Run("Update non-existent user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		cmd := UpdateUserCommand{
 			ID:   "non-existent-id",
@@ -300,8 +473,8 @@ func TestUpdateUser(t *testing.T) {
 
 		user, err := service.UpdateUser(context.Background(), cmd)
 
-		if err == nil {
-			t.Error("Expected error for non-existent user, got nil")
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound, got %v", err)
 		}
 
 		if user != nil {
@@ -311,7 +484,7 @@ func TestUpdateUser(t *testing.T) {
 
 	t.Run("Update with invalid name", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		testUser, _ := domain.NewUser("test@example.com", "Original Name")
 		repo.AddUser(testUser)
@@ -331,12 +504,119 @@ func TestUpdateUser(t *testing.T) {
 			t.Errorf("Expected nil user, got %v", user)
 		}
 	})
+
+	t.Run("Update with stale expected version", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Original Name")
+		testUser.RestoreVersion(3)
+		repo.AddUser(testUser)
+
+		stale := int64(2)
+		cmd := UpdateUserCommand{ID: testUser.ID(), Name: "Updated Name", ExpectedVersion: &stale}
+
+		if _, err := service.UpdateUser(context.Background(), cmd); !errors.Is(err, domain.ErrVersionMismatch) {
+			t.Errorf("Expected ErrVersionMismatch, got %v", err)
+		}
+		if testUser.Name() != "Original Name" {
+			t.Errorf("Expected name to be kept, got %s", testUser.Name())
+		}
+
+		current := int64(3)
+		cmd.ExpectedVersion = &current
+		if _, err := service.UpdateUser(context.Background(), cmd); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Concurrent save with expected version fails the precondition", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		repo.SaveFunc = func(ctx context.Context, user *domain.User) error {
+			return domain.ErrVersionConflict
+		}
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Original Name")
+		repo.AddUser(testUser)
+
+		if _, err := service.UpdateUser(context.Background(), UpdateUserCommand{ID: testUser.ID(), Name: "Other"}); !errors.Is(err, domain.ErrVersionConflict) {
+			t.Errorf("Expected ErrVersionConflict without expected version, got %v", err)
+		}
+
+		expected := testUser.Version()
+		cmd := UpdateUserCommand{ID: testUser.ID(), Name: "Other", ExpectedVersion: &expected}
+		if _, err := service.UpdateUser(context.Background(), cmd); !errors.Is(err, domain.ErrVersionMismatch) {
+			t.Errorf("Expected ErrVersionMismatch, got %v", err)
+		}
+	})
+}
+
+func TestPatchUser(t *testing.T) {
+	t.Run("Applies present fields", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Original Name")
+		repo.AddUser(testUser)
+
+		cmd := PatchUserCommand{ID: testUser.ID(), Name: patch.Optional[string]{Present: true, Value: "Patched"}}
+		user, err := service.PatchUser(context.Background(), cmd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if user.Name != "Patched" {
+			t.Errorf("Expected name Patched, got %s", user.Name)
+		}
+	})
+
+	t.Run("Absent fields are kept", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Original Name")
+		repo.AddUser(testUser)
+
+		user, err := service.PatchUser(context.Background(), PatchUserCommand{ID: testUser.ID()})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if user.Name != "Original Name" {
+			t.Errorf("Expected name to be kept, got %s", user.Name)
+		}
+	})
+
+	t.Run("Required fields cannot be removed", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Original Name")
+		repo.AddUser(testUser)
+
+		cmd := PatchUserCommand{ID: testUser.ID(), Name: patch.Optional[string]{Present: true, Null: true}}
+		if _, err := service.PatchUser(context.Background(), cmd); !errors.Is(err, domain.ErrInvalidUser) {
+			t.Errorf("Expected ErrInvalidUser, got %v", err)
+		}
+	})
+
+	t.Run("Invariants are enforced by the domain", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Original Name")
+		repo.AddUser(testUser)
+
+		cmd := PatchUserCommand{ID: testUser.ID(), Name: patch.Optional[string]{Present: true, Value: strings.Repeat("a", domain.MaxNameLength+1)}}
+		if _, err := service.PatchUser(context.Background(), cmd); !errors.Is(err, domain.ErrInvalidUser) {
+			t.Errorf("Expected ErrInvalidUser, got %v", err)
+		}
+	})
 }
 
 func TestDeleteUser(t *testing.T) {
 	t.Run("Delete existing user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		testUser, _ := domain.NewUser("test@example.com", "Test User")
 		repo.AddUser(testUser)
@@ -353,29 +633,106 @@ func TestDeleteUser(t *testing.T) {
 		if err == nil {
 			t.Error("Expected user to be deleted, but it still exists")
 		}
+		if testUser.Status() != domain.StatusDeleted || testUser.DeletedAt() == nil {
+			t.Errorf("Expected soft delete, got status %s", testUser.Status())
+		}
 	})
 
 	t.Run("Delete non-existent user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		cmd := DeleteUserCommand{ID: "non-existent-id"}
 
 		err := service.DeleteUser(context.Background(), cmd)
 
-		if err == nil {
-			t.Error("Expected error for non-existent user, got nil")
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound, got %v", err)
 		}
 	})
 }
 
+func TestRestoreUser(t *testing.T) {
+	t.Run("Restore deleted user", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Test User")
+		repo.AddUser(testUser)
+		if err := service.DeleteUser(context.Background(), DeleteUserCommand{ID: testUser.ID()}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		user, err := service.RestoreUser(context.Background(), RestoreUserCommand{ID: testUser.ID()})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if user.Status != domain.StatusSuspended.String() || user.StatusChangedBy != ActorSystem {
+			t.Errorf("Expected restored user to be suspended by %s, got %s by %s", ActorSystem, user.Status, user.StatusChangedBy)
+		}
+		if _, err := repo.FindByID(context.Background(), testUser.ID()); err != nil {
+			t.Errorf("Expected restored user to be found, got %v", err)
+		}
+	})
+
+	t.Run("Restore user that is not deleted", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Test User")
+		repo.AddUser(testUser)
+
+		if _, err := service.RestoreUser(context.Background(), RestoreUserCommand{ID: testUser.ID()}); !errors.Is(err, domain.ErrUserNotDeleted) {
+			t.Errorf("Expected ErrUserNotDeleted, got %v", err)
+		}
+		if _, err := service.RestoreUser(context.Background(), RestoreUserCommand{ID: "non-existent-id"}); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound, got %v", err)
+		}
+	})
+}
+
+func TestPurger(t *testing.T) {
+	repo := NewMockUserRepository()
+	service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+	ctx := context.Background()
+
+	old, _ := domain.NewUser("old@example.com", "Old")
+	recent, _ := domain.NewUser("recent@example.com", "Recent")
+	kept, _ := domain.NewUser("kept@example.com", "Kept")
+	for _, user := range []*domain.User{old, recent, kept} {
+		repo.AddUser(user)
+	}
+	for _, user := range []*domain.User{old, recent} {
+		if err := service.DeleteUser(ctx, DeleteUserCommand{ID: user.ID()}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	deletedAt := time.Now().Add(-48 * time.Hour)
+	old.RestoreDeletedAt(&deletedAt)
+
+	purger := NewPurger(repo, PurgeConfig{Retention: 24 * time.Hour, Interval: time.Hour})
+	purged, err := purger.PurgeDeleted(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 purged user, got %d", purged)
+	}
+	if _, err := repo.FindDeletedByID(ctx, old.ID()); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("Expected old user to be purged, got %v", err)
+	}
+	if _, err := repo.FindDeletedByID(ctx, recent.ID()); err != nil {
+		t.Errorf("Expected recent user to be kept during retention, got %v", err)
+	}
+}
+
 func TestActivateDeactivateUser(t *testing.T) {
 	t.Run("Activate user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		testUser, _ := domain.NewUser("test@example.com", "Test User")
-		testUser.Deactivate()
+		_ = testUser.Suspend("chargeback", "admin")
 		repo.AddUser(testUser)
 
 		cmd := ActivateUserCommand{ID: testUser.ID()}
@@ -402,14 +759,15 @@ func TestActivateDeactivateUser(t *testing.T) {
 
 	t.Run("Deactivate user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		testUser, _ := domain.NewUser("test@example.com", "Test User")
 		repo.AddUser(testUser)
 
-		cmd := DeactivateUserCommand{ID: testUser.ID()}
+		cmd := DeactivateUserCommand{ID: testUser.ID(), Reason: "chargeback"}
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "admin-1", Kind: auth.KindUser})
 
-		user, err := service.DeactivateUser(context.Background(), cmd)
+		user, err := service.DeactivateUser(ctx, cmd)
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
@@ -419,26 +777,63 @@ func TestActivateDeactivateUser(t *testing.T) {
 			t.Fatal("Expected user to be returned, got nil")
 		}
 
-		if user.Status != domain.StatusInactive.String() {
-			t.Errorf("Expected status %s, got %s", domain.StatusInactive, user.Status)
+		if user.Status != domain.StatusSuspended.String() {
+			t.Errorf("Expected status %s, got %s", domain.StatusSuspended, user.Status)
+		}
+		if user.StatusReason != "chargeback" || user.StatusChangedBy != "user:admin-1" || user.StatusChangedAt == nil {
+			t.Errorf("Expected reason and actor of the change, got %q by %q", user.StatusReason, user.StatusChangedBy)
 		}
 
 		updatedUser, _ := repo.FindByID(context.Background(), testUser.ID())
-		if updatedUser.Status() != domain.StatusInactive {
-			t.Errorf("User not deactivated in repository. Expected status %s, got %s", domain.StatusInactive, updatedUser.Status())
+		if updatedUser.Status() != domain.StatusSuspended {
+			t.Errorf("User not suspended in repository. Expected status %s, got %s", domain.StatusSuspended, updatedUser.Status())
+		}
+	})
+
+	t.Run("Deactivate requires a reason", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Test User")
+		repo.AddUser(testUser)
+
+		if _, err := service.DeactivateUser(context.Background(), DeactivateUserCommand{ID: testUser.ID()}); !errors.Is(err, domain.ErrInvalidStatusChange) {
+			t.Errorf("Expected ErrInvalidStatusChange, got %v", err)
+		}
+	})
+
+	t.Run("Lock user", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Test User")
+		_ = testUser.VerifyEmail("user:" + testUser.ID())
+		repo.AddUser(testUser)
+
+		user, err := service.LockUser(context.Background(), LockUserCommand{ID: testUser.ID(), Reason: "leaked credentials"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if user.Status != domain.StatusLocked.String() {
+			t.Errorf("Expected status %s, got %s", domain.StatusLocked, user.Status)
+		}
+
+		// Bloquear de novo não é uma transição
+		if _, err := service.LockUser(context.Background(), LockUserCommand{ID: testUser.ID(), Reason: "again"}); !errors.Is(err, domain.ErrInvalidStatusTransition) {
+			t.Errorf("Expected ErrInvalidStatusTransition, got %v", err)
 		}
 	})
 
 	t.Run("Activate non-existent user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		cmd := ActivateUserCommand{ID: "non-existent-id"}
 
 		user, err := service.ActivateUser(context.Background(), cmd)
 
-		if err == nil {
-			t.Error("Expected error for non-existent user, got nil")
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound, got %v", err)
 		}
 
 		if user != nil {
@@ -448,14 +843,14 @@ func TestActivateDeactivateUser(t *testing.T) {
 
 	t.Run("Deactivate non-existent user", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		cmd := DeactivateUserCommand{ID: "non-existent-id"}
 
 		user, err := service.DeactivateUser(context.Background(), cmd)
 
-		if err == nil {
-			t.Error("Expected error for non-existent user, got nil")
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound, got %v", err)
 		}
 
 		if user != nil {
@@ -467,7 +862,7 @@ func TestActivateDeactivateUser(t *testing.T) {
 func TestListUsers(t *testing.T) {
 	t.Run("List users with pagination", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		for i := range 15 {
 			user, _ := domain.NewUser(
@@ -488,8 +883,8 @@ func TestListUsers(t *testing.T) {
 			t.Errorf("Unexpected error: %v", err)
 		}
 
-		if len(users1) != 5 {
-			t.Errorf("Expected 5 users on first page, got %d", len(users1))
+		if len(users1.Users) != 5 {
+			t.Errorf("Expected 5 users on first page, got %d", len(users1.Users))
 		}
 
 		query2 := ListUsersQuery{
@@ -503,8 +898,8 @@ func TestListUsers(t *testing.T) {
 			t.Errorf("Unexpected error: %v", err)
 		}
 
-		if len(users2) != 5 {
-			t.Errorf("Expected 5 users on second page, got %d", len(users2))
+		if len(users2.Users) != 5 {
+			t.Errorf("Expected 5 users on second page, got %d", len(users2.Users))
 		}
 
 		query3 := ListUsersQuery{
@@ -518,8 +913,8 @@ func TestListUsers(t *testing.T) {
 			t.Errorf("Unexpected error: %v", err)
 		}
 
-		if len(users3) != 5 {
-			t.Errorf("Expected 5 users on third page, got %d", len(users3))
+		if len(users3.Users) != 5 {
+			t.Errorf("Expected 5 users on third page, got %d", len(users3.Users))
 		}
 
 		query4 := ListUsersQuery{
@@ -533,14 +928,18 @@ func TestListUsers(t *testing.T) {
 			t.Errorf("Unexpected error: %v", err)
 		}
 
-		if len(users4) != 0 {
-			t.Errorf("Expected 0 users on fourth page, got %d", len(users4))
+		if len(users4.Users) != 0 {
+			t.Errorf("Expected 0 users on fourth page, got %d", len(users4.Users))
+		}
+
+		if users1.Total != 15 || users4.Total != 15 {
+			t.Errorf("Expected total 15 on every page, got %d and %d", users1.Total, users4.Total)
 		}
 	})
 
 	t.Run("List users with empty repository", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
 		query := ListUsersQuery{
 			Page:  1,
@@ -553,16 +952,39 @@ func TestListUsers(t *testing.T) {
 			t.Errorf("Unexpected error: %v", err)
 		}
 
-		if len(users) != 0 {
-			t.Errorf("Expected empty list, got %d users", len(users))
+		if len(users.Users) != 0 {
+			t.Errorf("Expected empty list, got %d users", len(users.Users))
+		}
+
+		if users.Total != 0 {
+			t.Errorf("Expected total 0, got %d", users.Total)
+		}
+	})
+
+	t.Run("List users with count error", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		repo.CountFunc = func(ctx context.Context, criteria domain.UserCriteria) (int64, error) {
+			return 0, errors.New("database error")
+		}
+
+		users, err := service.ListUsers(context.Background(), ListUsersQuery{Page: 1, Limit: 10})
+
+		if err == nil {
+			t.Error("Expected error from repository, got nil")
+		}
+
+		if users != nil {
+			t.Errorf("Expected nil users, got %v", users)
 		}
 	})
 
 	t.Run("List users with repository error", func(t *testing.T) {
 		repo := NewMockUserRepository()
-		service := NewUserService(repo)
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
 
-		repo.FindAllFunc = func(ctx context.Context, page, limit int) ([]*domain.User, error) {
+		repo.FindAllFunc = func(ctx context.Context, criteria domain.UserCriteria, page, limit int) ([]*domain.User, error) {
 			return nil, errors.New("database error")
 		}
 
@@ -582,3 +1004,203 @@ func TestListUsers(t *testing.T) {
 		}
 	})
 }
+
+func TestListUsersWithCursor(t *testing.T) {
+	newRepo := func() *MockUserRepository {
+		repo := NewMockUserRepository()
+		base := time.Unix(1700000000, 0)
+		for i := range 5 {
+			// Dois usuários por segundo, para exercitar o desempate por id
+			user, _ := domain.ReconstructUser(
+				fmt.Sprintf("id-%d", i),
+				fmt.Sprintf("user%d@example.com", i),
+				fmt.Sprintf("User %d", i),
+				domain.StatusActive.String(),
+				base.Add(time.Duration(i/2)*time.Second),
+			)
+			repo.AddUser(user)
+		}
+		return repo
+	}
+
+	t.Run("Walks all users without repetition", func(t *testing.T) {
+		service := NewUserService(newRepo(), MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		var seen []string
+		query := ListUsersQuery{Limit: 2, UseCursor: true}
+		for range 5 {
+			list, err := service.ListUsers(context.Background(), query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, user := range list.Users {
+				seen = append(seen, user.ID)
+			}
+			if list.NextCursor == "" {
+				break
+			}
+			query.Cursor = list.NextCursor
+		}
+
+		want := []string{"id-0", "id-1", "id-2", "id-3", "id-4"}
+		if fmt.Sprint(seen) != fmt.Sprint(want) {
+			t.Errorf("Expected %v, got %v", want, seen)
+		}
+	})
+
+	t.Run("Last page has no next cursor", func(t *testing.T) {
+		service := NewUserService(newRepo(), MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		list, err := service.ListUsers(context.Background(), ListUsersQuery{Limit: 5, UseCursor: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(list.Users) != 5 || list.NextCursor != "" {
+			t.Errorf("Expected 5 users and no cursor, got %d users and cursor %q", len(list.Users), list.NextCursor)
+		}
+	})
+
+	t.Run("Page mode hands over a cursor", func(t *testing.T) {
+		service := NewUserService(newRepo(), MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		list, err := service.ListUsers(context.Background(), ListUsersQuery{Page: 1, Limit: 2})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if list.NextCursor == "" {
+			t.Error("Expected next cursor when there are more pages")
+		}
+	})
+
+	t.Run("Rejects forged cursor", func(t *testing.T) {
+		service := NewUserService(newRepo(), MockTransactionManager{}, testCursors, testHasher, testVerification)
+		other, _ := pagination.NewCursorCodec("other-secret-with-at-least-32-bytes")
+		forged, _ := other.Encode(map[string]any{"t": 0, "id": ""})
+
+		_, err := service.ListUsers(context.Background(), ListUsersQuery{Limit: 2, UseCursor: true, Cursor: forged})
+		if !errors.Is(err, pagination.ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+}
+
+func TestListUsersCriteria(t *testing.T) {
+	t.Run("Filters reach the repository", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		active, _ := domain.NewUser("active@example.com", "Active")
+		suspended, _ := domain.NewUser("suspended@example.com", "Suspended")
+		_ = suspended.Suspend("chargeback", "admin")
+		repo.AddUser(active)
+		repo.AddUser(suspended)
+
+		list, err := service.ListUsers(context.Background(), ListUsersQuery{Page: 1, Limit: 10, Status: "suspended"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if list.Total != 1 || len(list.Users) != 1 || list.Users[0].ID != suspended.ID() {
+			t.Errorf("Expected only the suspended user, got %+v", list.Users)
+		}
+	})
+
+	t.Run("Sort is parsed into the criteria", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		var got domain.UserCriteria
+		repo.FindAllFunc = func(ctx context.Context, criteria domain.UserCriteria, page, limit int) ([]*domain.User, error) {
+			got = criteria
+			return nil, nil
+		}
+
+		if _, err := service.ListUsers(context.Background(), ListUsersQuery{Page: 1, Limit: 10, Sort: "-created_at,name"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		want := []domain.SortOrder{{Field: domain.SortByCreatedAt, Desc: true}, {Field: domain.SortByName}}
+		if fmt.Sprint(got.Sort) != fmt.Sprint(want) {
+			t.Errorf("Expected sort %v, got %v", want, got.Sort)
+		}
+	})
+
+	t.Run("Invalid queries are rejected", func(t *testing.T) {
+		service := NewUserService(NewMockUserRepository(), MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		invalid := []ListUsersQuery{
+			{Page: 1, Limit: 10, Status: "unknown"},
+			{Page: 1, Limit: 10, Sort: "password"},
+			{Limit: 10, UseCursor: true, Sort: "name"},
+		}
+		for _, query := range invalid {
+			if _, err := service.ListUsers(context.Background(), query); !errors.Is(err, domain.ErrInvalidCriteria) {
+				t.Errorf("Expected ErrInvalidCriteria for %+v, got %v", query, err)
+			}
+		}
+	})
+}
+
+func TestUserEvents(t *testing.T) {
+	t.Run("Persist events for each state change", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+		ctx := context.Background()
+
+		user, err := service.CreateUser(ctx, CreateUserCommand{Email: "test@example.com", Name: "Test User"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, err := service.UpdateUser(ctx, UpdateUserCommand{ID: user.ID, Name: "Updated Name"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := service.DeactivateUser(ctx, DeactivateUserCommand{ID: user.ID, Reason: "chargeback"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := service.ActivateUser(ctx, ActivateUserCommand{ID: user.ID}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := service.DeleteUser(ctx, DeleteUserCommand{ID: user.ID}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		want := []string{
+			domain.UserCreatedEvent,
+			domain.UserUpdatedEvent,
+			domain.UserSuspendedEvent,
+			domain.UserActivatedEvent,
+			domain.UserDeletedEvent,
+		}
+		got := repo.EventNames()
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Expected events %v, got %v", want, got)
+		}
+
+		created, ok := repo.Events[0].(domain.UserCreated)
+		if !ok {
+			t.Fatalf("Expected UserCreated, got %T", repo.Events[0])
+		}
+		if created.UserID != user.ID || created.Email != user.Email {
+			t.Errorf("Unexpected UserCreated payload: %+v", created)
+		}
+	})
+
+	t.Run("No events when the transition is rejected", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		service := NewUserService(repo, MockTransactionManager{}, testCursors, testHasher, testVerification)
+
+		testUser, _ := domain.NewUser("test@example.com", "Test User")
+		testUser.PullEvents()
+		repo.AddUser(testUser)
+
+		if _, err := service.LockUser(context.Background(), LockUserCommand{ID: testUser.ID(), Reason: "leaked credentials"}); !errors.Is(err, domain.ErrInvalidStatusTransition) {
+			t.Fatalf("Expected ErrInvalidStatusTransition, got %v", err)
+		}
+
+		if len(repo.Events) != 0 {
+			t.Errorf("Expected no events, got %v", repo.EventNames())
+		}
+	})
+}
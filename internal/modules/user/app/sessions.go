@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+// Refresh troca um refresh token por um novo par de tokens (rotação). Um token já
+// trocado revoga a sessão inteira, inclusive o token que o substituiu; isso vale também
+// para duas requisições simultâneas com o mesmo token, em que só a primeira rotação grava.
+func (s *AuthService) Refresh(ctx context.Context, cmd RefreshCommand) (*Tokens, error) {
+	presented := auth.HashSecret(cmd.RefreshToken)
+	session, err := s.sessions.FindByTokenHash(ctx, presented)
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshErr := session.Refresh(cmd.RefreshToken, s.cfg.Sessions, domain.SessionMetadata{UserAgent: cmd.UserAgent, IP: cmd.ClientIP}, s.now())
+	if errors.Is(refreshErr, domain.ErrRefreshTokenReused) {
+		logReuse(session, cmd)
+		if err := s.sessions.Save(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+	if refreshErr != nil {
+		return nil, refreshErr
+	}
+
+	user, err := s.repo.FindByID(ctx, session.UserID())
+	if errors.Is(err, domain.ErrUserNotFound) {
+		// Usuário excluído: a sessão é encerrada em vez de renovada
+		session.Revoke(domain.RevokeReasonUserDeleted, s.now())
+		if err := s.sessions.Save(ctx, session); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrSessionRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkActive(user); err != nil {
+		return nil, err
+	}
+
+	err = s.sessions.Rotate(ctx, session, presented)
+	if errors.Is(err, domain.ErrRefreshTokenReused) {
+		// Outra requisição trocou o mesmo token depois da leitura: é reuso
+		return nil, s.revokeReused(ctx, session.ID(), cmd)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, session, refreshToken)
+}
+
+// revokeReused revoga a sessão cuja rotação perdeu a corrida para outra requisição
+// com o mesmo token. Se ela já foi encerrada por outro motivo (logout), mantém o motivo.
+func (s *AuthService) revokeReused(ctx context.Context, sessionID string, cmd RefreshCommand) error {
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.IsRevoked() {
+		return domain.ErrSessionRevoked
+	}
+
+	logReuse(session, cmd)
+	session.Revoke(domain.RevokeReasonReuse, s.now())
+	if err := s.sessions.Save(ctx, session); err != nil {
+		return err
+	}
+	return domain.ErrRefreshTokenReused
+}
+
+func logReuse(session *domain.Session, cmd RefreshCommand) {
+	logger.WithFields(logrus.Fields{
+		"session_id": session.ID(),
+		"user_id":    session.UserID(),
+		"ip":         cmd.ClientIP,
+	}).Warn("Refresh token reuse detected, session revoked")
+}
+
+// Logout encerra a sessão do refresh token. Tokens desconhecidos são ignorados, para
+// que repetir o logout não seja um erro.
+func (s *AuthService) Logout(ctx context.Context, cmd LogoutCommand) error {
+	session, err := s.sessions.FindByTokenHash(ctx, auth.HashSecret(cmd.RefreshToken))
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	session.Revoke(domain.RevokeReasonLogout, s.now())
+	return s.sessions.Save(ctx, session)
+}
+
+// ListSessions lista as sessões ativas do usuário (o próprio ou users:admin)
+func (s *AuthService) ListSessions(ctx context.Context, query ListSessionsQuery) ([]*SessionInfo, error) {
+	principal, _, err := authorizeUser(ctx, s.authz, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindByID(ctx, query.UserID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessions.FindByUser(ctx, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	result := make([]*SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		if !session.IsActive(s.cfg.Sessions, now) {
+			continue
+		}
+		metadata := session.Metadata()
+		result = append(result, &SessionInfo{
+			ID:         session.ID(),
+			UserAgent:  metadata.UserAgent,
+			IP:         metadata.IP,
+			CreatedAt:  session.CreatedAt(),
+			LastUsedAt: session.LastUsedAt(),
+			ExpiresAt:  session.ExpiresAt(s.cfg.Sessions),
+			Current:    session.ID() == principal.SessionID,
+		})
+	}
+	return result, nil
+}
+
+// RevokeSession encerra uma sessão do usuário (o próprio ou users:admin)
+func (s *AuthService) RevokeSession(ctx context.Context, cmd RevokeSessionCommand) error {
+	if _, _, err := authorizeUser(ctx, s.authz, cmd.UserID); err != nil {
+		return err
+	}
+
+	session, err := s.sessions.FindByID(ctx, cmd.SessionID)
+	if err != nil {
+		return err
+	}
+	// Sessões de outro usuário se comportam como inexistentes
+	if session.UserID() != cmd.UserID {
+		return domain.ErrSessionNotFound
+	}
+
+	session.Revoke(domain.RevokeReasonRevoked, s.now())
+	return s.sessions.Save(ctx, session)
+}
+
+// RevokeAllSessions encerra todas as sessões do usuário (o próprio ou users:admin),
+// inclusive a que fez a requisição
+func (s *AuthService) RevokeAllSessions(ctx context.Context, cmd RevokeAllSessionsCommand) error {
+	if _, _, err := authorizeUser(ctx, s.authz, cmd.UserID); err != nil {
+		return err
+	}
+
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		return s.revokeSessions(ctx, cmd.UserID, "", domain.RevokeReasonRevoked)
+	})
+}
+
+// revokeSessions revoga as sessões abertas do usuário, exceto keep
+func (s *AuthService) revokeSessions(ctx context.Context, userID, keep, reason string) error {
+	sessions, err := s.sessions.FindByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for _, session := range sessions {
+		if session.ID() == keep {
+			continue
+		}
+		session.Revoke(reason, now)
+		if err := s.sessions.Save(ctx, session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
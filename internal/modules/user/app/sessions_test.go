@@ -0,0 +1,326 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+// MockSessionRepository guarda cópias das sessões em memória, como um banco, e indexa
+// todos os hashes já emitidos
+type MockSessionRepository struct {
+	sessions map[string]domain.Session
+	tokens   map[string]string
+}
+
+func NewMockSessionRepository() *MockSessionRepository {
+	return &MockSessionRepository{
+		sessions: make(map[string]domain.Session),
+		tokens:   make(map[string]string),
+	}
+}
+
+func (m *MockSessionRepository) Save(ctx context.Context, session *domain.Session) error {
+	m.sessions[session.ID()] = *session
+	m.tokens[session.TokenHash()] = session.ID()
+	return nil
+}
+
+func (m *MockSessionRepository) Rotate(ctx context.Context, session *domain.Session, previousHash string) error {
+	stored, ok := m.sessions[session.ID()]
+	if !ok || stored.TokenHash() != previousHash || stored.IsRevoked() {
+		return domain.ErrRefreshTokenReused
+	}
+	return m.Save(ctx, session)
+}
+
+func (m *MockSessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, domain.ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+func (m *MockSessionRepository) FindByTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	id, ok := m.tokens[hash]
+	if !ok {
+		return nil, domain.ErrSessionNotFound
+	}
+	return m.FindByID(ctx, id)
+}
+
+func (m *MockSessionRepository) FindByUser(ctx context.Context, userID string) ([]*domain.Session, error) {
+	var result []*domain.Session
+	for _, session := range m.sessions {
+		if session.UserID() == userID && !session.IsRevoked() {
+			result = append(result, &session)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].LastUsedAt().After(result[j].LastUsedAt()) })
+	return result, nil
+}
+
+// racingSessions executa interleave logo depois da primeira leitura por token, simulando
+// uma requisição simultânea que termina entre a leitura e a gravação da outra
+type racingSessions struct {
+	*MockSessionRepository
+	interleave func()
+}
+
+func (r *racingSessions) FindByTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	session, err := r.MockSessionRepository.FindByTokenHash(ctx, hash)
+	if next := r.interleave; next != nil {
+		r.interleave = nil
+		next()
+	}
+	return session, err
+}
+
+func login(t *testing.T, service *AuthService, userAgent string) *Tokens {
+	t.Helper()
+
+	tokens, err := service.Login(context.Background(), LoginCommand{Email: "ana@example.com", Password: testPassword, UserAgent: userAgent})
+	if err != nil {
+		t.Fatalf("Unexpected login error: %v", err)
+	}
+	return tokens
+}
+
+func TestRefresh(t *testing.T) {
+	t.Run("Rotates the refresh token", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		addUserWithPassword(t, repo, "ana@example.com")
+		service, issuer, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		first := login(t, service, "curl")
+
+		second, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: first.RefreshToken, UserAgent: "firefox"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if second.RefreshToken == first.RefreshToken || second.SessionID != first.SessionID {
+			t.Errorf("Expected a new refresh token on the same session, got %+v", second)
+		}
+		if principal := issuer.Issued[len(issuer.Issued)-1]; principal.SessionID != first.SessionID {
+			t.Errorf("Expected access token bound to session %s, got %s", first.SessionID, principal.SessionID)
+		}
+	})
+
+	t.Run("Reusing a rotated token revokes the whole family", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		addUserWithPassword(t, repo, "ana@example.com")
+		service, _, sessions := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		first := login(t, service, "curl")
+		second, _ := service.Refresh(context.Background(), RefreshCommand{RefreshToken: first.RefreshToken})
+
+		_, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: first.RefreshToken})
+		if !errors.Is(err, domain.ErrRefreshTokenReused) {
+			t.Fatalf("Expected ErrRefreshTokenReused, got %v", err)
+		}
+
+		session, _ := sessions.FindByID(context.Background(), first.SessionID)
+		if session.RevokeReason() != domain.RevokeReasonReuse {
+			t.Errorf("Expected session revoked for reuse, got %q", session.RevokeReason())
+		}
+		if _, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: second.RefreshToken}); !errors.Is(err, domain.ErrSessionRevoked) {
+			t.Errorf("Expected the newest token to be revoked too, got %v", err)
+		}
+	})
+
+	t.Run("Concurrent refreshes with the same token revoke the family", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		addUserWithPassword(t, repo, "ana@example.com")
+		sessions := &racingSessions{MockSessionRepository: NewMockSessionRepository()}
+		service, err := NewAuthService(repo, sessions, MockTransactionManager{}, testHasher, &MockTokenIssuer{}, scopeAuthorizer{}, NewLoginThrottle(0, 0), testAuthConfig)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		first := login(t, service, "curl")
+
+		var winner *Tokens
+		var winnerErr error
+		sessions.interleave = func() {
+			winner, winnerErr = service.Refresh(context.Background(), RefreshCommand{RefreshToken: first.RefreshToken})
+		}
+
+		_, err = service.Refresh(context.Background(), RefreshCommand{RefreshToken: first.RefreshToken})
+		if winnerErr != nil {
+			t.Fatalf("Expected the first refresh to succeed, got %v", winnerErr)
+		}
+		if !errors.Is(err, domain.ErrRefreshTokenReused) {
+			t.Fatalf("Expected ErrRefreshTokenReused for the losing refresh, got %v", err)
+		}
+
+		session, _ := sessions.FindByID(context.Background(), first.SessionID)
+		if session.RevokeReason() != domain.RevokeReasonReuse {
+			t.Errorf("Expected session revoked for reuse, got %q", session.RevokeReason())
+		}
+		if _, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: winner.RefreshToken}); !errors.Is(err, domain.ErrSessionRevoked) {
+			t.Errorf("Expected the winning token to be revoked too, got %v", err)
+		}
+	})
+
+	t.Run("Idle and absolute expiry", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		start := time.Now()
+		service.now = func() time.Time { return start }
+
+		tokens := login(t, service, "curl")
+		service.now = func() time.Time { return start.Add(testAuthConfig.Sessions.IdleTimeout) }
+		if _, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: tokens.RefreshToken}); !errors.Is(err, domain.ErrSessionExpired) {
+			t.Errorf("Expected idle session to expire, got %v", err)
+		}
+
+		// Refreshes frequentes não estendem a sessão além do limite absoluto
+		service.now = func() time.Time { return start }
+		tokens = login(t, service, "curl")
+		for elapsed := 30 * time.Minute; elapsed < testAuthConfig.Sessions.AbsoluteTimeout; elapsed += 30 * time.Minute {
+			service.now = func() time.Time { return start.Add(elapsed) }
+			next, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: tokens.RefreshToken})
+			if err != nil {
+				t.Fatalf("Unexpected error after %v: %v", elapsed, err)
+			}
+			tokens = next
+		}
+		service.now = func() time.Time { return start.Add(testAuthConfig.Sessions.AbsoluteTimeout) }
+		if _, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: tokens.RefreshToken}); !errors.Is(err, domain.ErrSessionExpired) {
+			t.Errorf("Expected absolute expiry, got %v", err)
+		}
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		service, _, _ := newAuthService(t, NewMockUserRepository(), NewLoginThrottle(0, 0))
+
+		if _, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: "unknown"}); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+			t.Errorf("Expected ErrInvalidRefreshToken, got %v", err)
+		}
+	})
+
+	t.Run("Deleted user cannot refresh", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		tokens := login(t, service, "curl")
+
+		user.Delete("admin")
+		if _, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: tokens.RefreshToken}); !errors.Is(err, domain.ErrSessionRevoked) {
+			t.Fatalf("Expected ErrSessionRevoked, got %v", err)
+		}
+		if _, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: tokens.RefreshToken}); !errors.Is(err, domain.ErrSessionRevoked) {
+			t.Errorf("Expected the session to stay revoked, got %v", err)
+		}
+	})
+
+	t.Run("Logout ends the session", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		tokens := login(t, service, "curl")
+
+		if err := service.Logout(context.Background(), LogoutCommand{RefreshToken: tokens.RefreshToken}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := service.Refresh(context.Background(), RefreshCommand{RefreshToken: tokens.RefreshToken}); !errors.Is(err, domain.ErrSessionRevoked) {
+			t.Errorf("Expected ErrSessionRevoked, got %v", err)
+		}
+	})
+}
+
+func TestManageSessions(t *testing.T) {
+	setup := func(t *testing.T) (*AuthService, *domain.User, *Tokens, *Tokens) {
+		repo := NewMockUserRepository()
+		user := addUserWithPassword(t, repo, "ana@example.com")
+		service, _, _ := newAuthService(t, repo, NewLoginThrottle(0, 0))
+		return service, user, login(t, service, "curl"), login(t, service, "firefox")
+	}
+
+	t.Run("Lists active sessions marking the current one", func(t *testing.T) {
+		service, user, laptop, _ := setup(t)
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser, SessionID: laptop.SessionID})
+
+		sessions, err := service.ListSessions(ctx, ListSessionsQuery{UserID: user.ID()})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(sessions) != 2 {
+			t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+		}
+		for _, session := range sessions {
+			if session.Current != (session.ID == laptop.SessionID) {
+				t.Errorf("Unexpected current flag on %+v", session)
+			}
+			if session.ExpiresAt == nil {
+				t.Errorf("Expected expiry on %+v", session)
+			}
+		}
+	})
+
+	t.Run("Revokes one session", func(t *testing.T) {
+		service, user, laptop, phone := setup(t)
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser})
+
+		if err := service.RevokeSession(ctx, RevokeSessionCommand{UserID: user.ID(), SessionID: phone.SessionID}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		sessions, _ := service.ListSessions(ctx, ListSessionsQuery{UserID: user.ID()})
+		if len(sessions) != 1 || sessions[0].ID != laptop.SessionID {
+			t.Errorf("Expected only session %s left, got %+v", laptop.SessionID, sessions)
+		}
+	})
+
+	t.Run("Revokes all sessions", func(t *testing.T) {
+		service, user, _, _ := setup(t)
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "key-1", Kind: auth.KindAPIKey, Scopes: []string{ScopeAdmin}})
+
+		if err := service.RevokeAllSessions(ctx, RevokeAllSessionsCommand{UserID: user.ID()}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if sessions, _ := service.ListSessions(ctx, ListSessionsQuery{UserID: user.ID()}); len(sessions) != 0 {
+			t.Errorf("Expected no sessions left, got %d", len(sessions))
+		}
+	})
+
+	t.Run("Session of another user is not found", func(t *testing.T) {
+		service, _, laptop, _ := setup(t)
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "key-1", Kind: auth.KindAPIKey, Scopes: []string{ScopeAdmin}})
+
+		err := service.RevokeSession(ctx, RevokeSessionCommand{UserID: "another-user", SessionID: laptop.SessionID})
+		if !errors.Is(err, domain.ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Other users cannot list sessions", func(t *testing.T) {
+		service, user, _, _ := setup(t)
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "someone-else", Kind: auth.KindUser})
+
+		if _, err := service.ListSessions(ctx, ListSessionsQuery{UserID: user.ID()}); !errors.Is(err, auth.ErrPermissionDenied) {
+			t.Errorf("Expected ErrPermissionDenied, got %v", err)
+		}
+	})
+
+	t.Run("Password change keeps only the current session", func(t *testing.T) {
+		service, user, laptop, _ := setup(t)
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: user.ID(), Kind: auth.KindUser, SessionID: laptop.SessionID})
+
+		err := service.ChangePassword(ctx, ChangePasswordCommand{UserID: user.ID(), CurrentPassword: testPassword, NewPassword: "another long passphrase"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		sessions, _ := service.ListSessions(ctx, ListSessionsQuery{UserID: user.ID()})
+		if len(sessions) != 1 || !sessions[0].Current {
+			t.Errorf("Expected only the current session left, got %+v", sessions)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+var ErrTooManyLoginAttempts = apperror.RateLimited("TOO_MANY_LOGIN_ATTEMPTS", "too many failed login attempts from this address, try again later")
+
+// maxThrottleEntries dispara a limpeza das janelas já expiradas
+const maxThrottleEntries = 10_000
+
+// LoginThrottle conta falhas de login por chave (IP) em janelas fixas. Complementa o
+// bloqueio por conta, que sozinho não impede testar uma senha em muitas contas.
+// O estado é local à instância.
+type LoginThrottle struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*throttleEntry
+}
+
+type throttleEntry struct {
+	failures int
+	resetAt  time.Time
+}
+
+// NewLoginThrottle cria o limitador; limit <= 0 desliga o limite
+func NewLoginThrottle(limit int, window time.Duration) *LoginThrottle {
+	return &LoginThrottle{limit: limit, window: window, entries: map[string]*throttleEntry{}}
+}
+
+// Allow informa se key ainda pode tentar login
+func (t *LoginThrottle) Allow(key string, now time.Time) bool {
+	if t.limit <= 0 {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	return !ok || !now.Before(entry.resetAt) || entry.failures < t.limit
+}
+
+// Fail registra uma tentativa malsucedida de key
+func (t *LoginThrottle) Fail(key string, now time.Time) {
+	if t.limit <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if !ok || !now.Before(entry.resetAt) {
+		if len(t.entries) >= maxThrottleEntries {
+			t.prune(now)
+		}
+		entry = &throttleEntry{resetAt: now.Add(t.window)}
+		t.entries[key] = entry
+	}
+	entry.failures++
+}
+
+func (t *LoginThrottle) prune(now time.Time) {
+	for key, entry := range t.entries {
+		if !now.Before(entry.resetAt) {
+			delete(t.entries, key)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+// Política de senha. O máximo evita hashes caros com entradas enormes.
+const (
+	MinPasswordLength = 12
+	MaxPasswordLength = 128
+)
+
+// PasswordHasher gera e confere hashes de senha (implementado por password.Hasher)
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	NeedsRehash(encoded string) bool
+}
+
+// LockoutPolicy bloqueia a conta por Duration após MaxFailedAttempts senhas erradas seguidas
+type LockoutPolicy struct {
+	MaxFailedAttempts int
+	Duration          time.Duration
+}
+
+// Credentials é o estado de autenticação do usuário
+type Credentials struct {
+	PasswordHash string
+	FailedLogins int
+	LockedUntil  *time.Time
+	LastLoginAt  *time.Time
+}
+
+// ValidatePassword aplica a política de senha; email impede senhas derivadas do próprio login
+func ValidatePassword(password, email string) error {
+	var violations []apperror.FieldError
+
+	length := utf8.RuneCountInString(password)
+	switch {
+	case length < MinPasswordLength:
+		violations = append(violations, violation("password", FieldCodeMin, fmt.Sprintf("password must be at least %d characters", MinPasswordLength)))
+	case length > MaxPasswordLength:
+		violations = append(violations, violation("password", FieldCodeMax, fmt.Sprintf("password must be at most %d characters", MaxPasswordLength)))
+	}
+
+	lowered := strings.ToLower(password)
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	switch {
+	case email != "" && (lowered == strings.ToLower(email) || (utf8.RuneCountInString(local) >= 4 && strings.Contains(lowered, local))):
+		violations = append(violations, violation("password", FieldCodeWeak, "password must not contain the email address"))
+	case length > 0 && strings.Count(password, password[:1]) == len(password):
+		violations = append(violations, violation("password", FieldCodeWeak, "password must not repeat a single character"))
+	}
+
+	if len(violations) > 0 {
+		return ErrInvalidPassword.WithFields(violations...)
+	}
+	return nil
+}
+
+// RestoreCredentials recarrega o estado de autenticação; usado pelo repositório ao reconstruir o agregado
+func (u *User) RestoreCredentials(credentials Credentials) {
+	u.credentials = credentials
+}
+
+func (u *User) Credentials() Credentials {
+	return u.credentials
+}
+
+func (u *User) HasPassword() bool {
+	return u.credentials.PasswordHash != ""
+}
+
+// IsLockedOut informa se o login está bloqueado por excesso de tentativas
+func (u *User) IsLockedOut(now time.Time) bool {
+	return u.credentials.LockedUntil != nil && now.Before(*u.credentials.LockedUntil)
+}
+
+// SetPassword valida a política, gera o hash e desbloqueia a conta
+func (u *User) SetPassword(password string, hasher PasswordHasher) error {
+	if err := ValidatePassword(password, u.email); err != nil {
+		return err
+	}
+
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	u.credentials.PasswordHash = hash
+	u.credentials.FailedLogins = 0
+	u.credentials.LockedUntil = nil
+	u.record(UserPasswordChanged{UserID: u.id, OccurredAt: time.Now()})
+	return nil
+}
+
+// Authenticate confere a senha como VerifyPassword e, no acerto, registra o login
+func (u *User) Authenticate(password string, hasher PasswordHasher, policy LockoutPolicy, now time.Time) error {
+	if err := u.VerifyPassword(password, hasher, policy, now); err != nil {
+		return err
+	}
+
+	u.credentials.LastLoginAt = &now
+	return nil
+}
+
+// VerifyPassword confere a senha e atualiza o controle de tentativas: erros seguidos
+// bloqueiam a conta conforme policy e um acerto zera o contador. Se os parâmetros
+// do hash mudaram, a senha é rehasheada. O agregado deve ser salvo em qualquer caso.
+func (u *User) VerifyPassword(password string, hasher PasswordHasher, policy LockoutPolicy, now time.Time) error {
+	if u.IsLockedOut(now) {
+		return ErrAccountLocked
+	}
+	if !u.HasPassword() {
+		return ErrInvalidCredentials
+	}
+
+	ok, err := hasher.Verify(password, u.credentials.PasswordHash)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		u.credentials.FailedLogins++
+		if policy.MaxFailedAttempts > 0 && u.credentials.FailedLogins >= policy.MaxFailedAttempts {
+			lockedUntil := now.Add(policy.Duration)
+			u.credentials.LockedUntil = &lockedUntil
+			u.credentials.FailedLogins = 0
+			return ErrAccountLocked
+		}
+		return ErrInvalidCredentials
+	}
+
+	u.credentials.FailedLogins = 0
+	u.credentials.LockedUntil = nil
+
+	if hasher.NeedsRehash(u.credentials.PasswordHash) {
+		// Falhar no rehash não impede o login; o hash antigo continua válido
+		if hash, err := hasher.Hash(password); err == nil {
+			u.credentials.PasswordHash = hash
+		}
+	}
+	return nil
+}
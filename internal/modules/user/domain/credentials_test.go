@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeHasher usa "v<versão>:<senha>" como hash, para os testes controlarem o rehash
+type fakeHasher struct {
+	version string
+}
+
+func (h fakeHasher) Hash(password string) (string, error) {
+	return h.version + ":" + password, nil
+}
+
+func (h fakeHasher) Verify(password, encoded string) (bool, error) {
+	_, stored, _ := strings.Cut(encoded, ":")
+	return stored == password, nil
+}
+
+func (h fakeHasher) NeedsRehash(encoded string) bool {
+	return !strings.HasPrefix(encoded, h.version+":")
+}
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		code     string
+	}{
+		{name: "Valid", password: "correct horse battery"},
+		{name: "Too short", password: "short", code: FieldCodeMin},
+		{name: "Too long", password: strings.Repeat("ab", MaxPasswordLength), code: FieldCodeMax},
+		{name: "Contains email", password: "vinicius-secret-123", code: FieldCodeWeak},
+		{name: "Repeated character", password: strings.Repeat("a", MinPasswordLength), code: FieldCodeWeak},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePassword(tt.password, "vinicius@teste.com")
+			if tt.code == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidPassword) {
+				t.Fatalf("Expected ErrInvalidPassword, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "password") {
+				t.Errorf("Expected violation on password, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	now := time.Now()
+	policy := LockoutPolicy{MaxFailedAttempts: 3, Duration: 15 * time.Minute}
+
+	newUser := func(t *testing.T) *User {
+		user, _ := NewUser("ana@example.com", "Ana")
+		if err := user.SetPassword("correct horse battery", fakeHasher{version: "v1"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		user.PullEvents()
+		return user
+	}
+
+	t.Run("Correct password records the login", func(t *testing.T) {
+		user := newUser(t)
+
+		if err := user.Authenticate("correct horse battery", fakeHasher{version: "v1"}, policy, now); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if last := user.Credentials().LastLoginAt; last == nil || !last.Equal(now) {
+			t.Errorf("Expected last login at %v, got %v", now, last)
+		}
+	})
+
+	t.Run("Consecutive failures lock the account", func(t *testing.T) {
+		user := newUser(t)
+		hasher := fakeHasher{version: "v1"}
+
+		for i := 1; i < policy.MaxFailedAttempts; i++ {
+			if err := user.Authenticate("wrong", hasher, policy, now); !errors.Is(err, ErrInvalidCredentials) {
+				t.Fatalf("Expected ErrInvalidCredentials on attempt %d, got %v", i, err)
+			}
+		}
+		if err := user.Authenticate("wrong", hasher, policy, now); !errors.Is(err, ErrAccountLocked) {
+			t.Fatalf("Expected ErrAccountLocked, got %v", err)
+		}
+
+		if err := user.Authenticate("correct horse battery", hasher, policy, now.Add(time.Minute)); !errors.Is(err, ErrAccountLocked) {
+			t.Errorf("Expected correct password to be refused while locked, got %v", err)
+		}
+		if err := user.Authenticate("correct horse battery", hasher, policy, now.Add(policy.Duration)); err != nil {
+			t.Errorf("Expected login after the lockout expires, got %v", err)
+		}
+	})
+
+	t.Run("Success resets the failure counter", func(t *testing.T) {
+		user := newUser(t)
+		hasher := fakeHasher{version: "v1"}
+
+		_ = user.Authenticate("wrong", hasher, policy, now)
+		_ = user.Authenticate("correct horse battery", hasher, policy, now)
+
+		if user.Credentials().FailedLogins != 0 {
+			t.Errorf("Expected failure counter reset, got %d", user.Credentials().FailedLogins)
+		}
+	})
+
+	t.Run("Outdated hash is replaced on login", func(t *testing.T) {
+		user := newUser(t)
+
+		if err := user.Authenticate("correct horse battery", fakeHasher{version: "v2"}, policy, now); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if hash := user.Credentials().PasswordHash; hash != "v2:correct horse battery" {
+			t.Errorf("Expected rehashed password, got %q", hash)
+		}
+	})
+
+	t.Run("User without password cannot log in", func(t *testing.T) {
+		user, _ := NewUser("ana@example.com", "Ana")
+		if err := user.Authenticate("anything at all", fakeHasher{version: "v1"}, policy, now); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+		}
+	})
+}
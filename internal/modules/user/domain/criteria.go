@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+var ErrInvalidCriteria = apperror.Validation("INVALID_LIST_QUERY", "invalid user list query")
+
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByName      SortField = "name"
+	SortByEmail     SortField = "email"
+)
+
+// sortable é a lista branca de campos aceitos em sort
+var sortable = map[SortField]bool{
+	SortByCreatedAt: true,
+	SortByName:      true,
+	SortByEmail:     true,
+}
+
+type SortOrder struct {
+	Field SortField
+	Desc  bool
+}
+
+// DefaultSort é a ordenação estável usada pela paginação por cursor (o id desempata)
+var DefaultSort = []SortOrder{{Field: SortByCreatedAt}}
+
+// UserCriteria especifica a consulta de listagem de usuários. Campos vazios não filtram.
+type UserCriteria struct {
+	Status        Status
+	EmailPrefix   string
+	NameContains  string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          []SortOrder
+	// IncludeDeleted inclui os usuários excluídos logicamente, que ficam de fora por padrão
+	IncludeDeleted bool
+}
+
+// ParseSort interpreta expressões como "-created_at,name" (prefixo "-" = decrescente)
+func ParseSort(expr string) ([]SortOrder, error) {
+	if strings.TrimSpace(expr) == "" {
+		return DefaultSort, nil
+	}
+
+	seen := map[SortField]bool{}
+	var orders []SortOrder
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		field := SortField(strings.TrimPrefix(part, "-"))
+
+		if !sortable[field] {
+			return nil, ErrInvalidCriteria.WithFields(violation("sort", "oneof",
+				fmt.Sprintf("sort field %q is not supported (allowed: created_at, name, email)", field)))
+		}
+		if seen[field] {
+			return nil, ErrInvalidCriteria.WithFields(violation("sort", "unique",
+				fmt.Sprintf("sort field %q is repeated", field)))
+		}
+
+		seen[field] = true
+		orders = append(orders, SortOrder{Field: field, Desc: desc})
+	}
+
+	return orders, nil
+}
+
+// Validate confere as regras que envolvem mais de um campo
+func (c UserCriteria) Validate() error {
+	var violations []apperror.FieldError
+
+	if c.Status != "" && !c.Status.IsValid() {
+		violations = append(violations, violation("status", "oneof", fmt.Sprintf("unknown status %q", c.Status)))
+	}
+	if c.Status == StatusDeleted && !c.IncludeDeleted {
+		violations = append(violations, violation("status", "include_deleted", "status deleted requires include_deleted=true"))
+	}
+	if c.CreatedAfter != nil && c.CreatedBefore != nil && !c.CreatedAfter.Before(*c.CreatedBefore) {
+		violations = append(violations, violation("created_before", "gtfield", "created_before must be after created_after"))
+	}
+
+	if len(violations) > 0 {
+		return ErrInvalidCriteria.WithFields(violations...)
+	}
+	return nil
+}
+
+// HasDefaultSort informa se a ordenação é a padrão, única compatível com a paginação por cursor
+func (c UserCriteria) HasDefaultSort() bool {
+	return len(c.Sort) == 0 || (len(c.Sort) == 1 && c.Sort[0] == DefaultSort[0])
+}
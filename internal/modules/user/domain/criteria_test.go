@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name      string
+		expr      string
+		want      []SortOrder
+		expectErr bool
+	}{
+		{name: "Empty uses default", expr: "", want: DefaultSort},
+		{name: "Multiple fields", expr: "-created_at, name", want: []SortOrder{{Field: SortByCreatedAt, Desc: true}, {Field: SortByName}}},
+		{name: "Unknown field", expr: "status", expectErr: true},
+		{name: "Raw SQL", expr: "name; DROP TABLE users", expectErr: true},
+		{name: "Repeated field", expr: "name,-name", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.expr)
+
+			if tt.expectErr {
+				if !errors.Is(err, ErrInvalidCriteria) {
+					t.Errorf("Expected ErrInvalidCriteria, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCriteriaValidate(t *testing.T) {
+	after, before := time.Unix(200, 0), time.Unix(100, 0)
+
+	if err := (UserCriteria{Status: StatusSuspended}).Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := (UserCriteria{Status: "unknown"}).Validate(); !errors.Is(err, ErrInvalidCriteria) {
+		t.Errorf("Expected ErrInvalidCriteria for unknown status, got %v", err)
+	}
+	if err := (UserCriteria{CreatedAfter: &after, CreatedBefore: &before}).Validate(); !errors.Is(err, ErrInvalidCriteria) {
+		t.Errorf("Expected ErrInvalidCriteria for inverted range, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// PendingEmail é o e-mail aguardando confirmação; vazio quando não há troca em andamento
+func (u *User) PendingEmail() string {
+	return u.pendingEmail
+}
+
+// EmailChangeRequestedAt é o momento do pedido de troca em andamento; nil quando não há
+func (u *User) EmailChangeRequestedAt() *time.Time {
+	return u.emailChangeRequestedAt
+}
+
+// RestorePendingEmail recarrega a troca de e-mail em andamento; usado pelo repositório ao reconstruir o agregado
+func (u *User) RestorePendingEmail(email string, requestedAt *time.Time) {
+	u.pendingEmail = email
+	u.emailChangeRequestedAt = requestedAt
+}
+
+// RequestEmailChange registra o novo e-mail como pendente; o atual continua valendo até
+// a confirmação. Um novo pedido substitui o anterior.
+func (u *User) RequestEmailChange(email, actor string) error {
+	email = strings.TrimSpace(email)
+	if violations := validateEmail(email); len(violations) > 0 {
+		return ErrInvalidUser.WithFields(violations...)
+	}
+	if email == u.email {
+		return ErrInvalidUser.WithFields(violation("email", FieldCodeUnchanged, "email must differ from the current email"))
+	}
+
+	now := time.Now()
+	u.pendingEmail = email
+	u.emailChangeRequestedAt = &now
+	u.record(UserEmailChangeRequested{UserID: u.id, OldEmail: u.email, NewEmail: email, Actor: actor, OccurredAt: now})
+	u.record(UserEmailChangeNotice{UserID: u.id, OldEmail: u.email, NewEmail: email, OccurredAt: now})
+	return nil
+}
+
+// ConfirmEmailChange troca o e-mail pelo pendente. O link chegou ao novo endereço, então
+// ele já fica confirmado (e um usuário pendente de verificação passa a ativo).
+func (u *User) ConfirmEmailChange(actor string) error {
+	if u.pendingEmail == "" {
+		return ErrNoPendingEmailChange
+	}
+
+	old := u.email
+	u.email = u.pendingEmail
+	u.pendingEmail = ""
+	u.emailChangeRequestedAt = nil
+	u.emailVerifiedAt = nil
+	u.record(UserEmailChanged{UserID: u.id, OldEmail: old, NewEmail: u.email, Actor: actor, OccurredAt: time.Now()})
+
+	return u.VerifyEmail(actor)
+}
+
+// CancelEmailChange descarta a troca em andamento; o link enviado deixa de valer
+func (u *User) CancelEmailChange(actor string) error {
+	if u.pendingEmail == "" {
+		return ErrNoPendingEmailChange
+	}
+
+	cancelled := u.pendingEmail
+	u.pendingEmail = ""
+	u.emailChangeRequestedAt = nil
+	u.record(UserEmailChangeCancelled{UserID: u.id, Email: cancelled, Actor: actor, OccurredAt: time.Now()})
+	return nil
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequestEmailChange(t *testing.T) {
+	t.Run("Records the new email as pending", func(t *testing.T) {
+		user := newVerifiedUser(t)
+
+		if err := user.RequestEmailChange("  novo@teste.com ", "user:"+user.ID()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if user.Email() != "usuario@teste.com" {
+			t.Errorf("Expected the current email to be kept, got %s", user.Email())
+		}
+		if user.PendingEmail() != "novo@teste.com" || user.EmailChangeRequestedAt() == nil {
+			t.Errorf("Expected pending email novo@teste.com, got %q", user.PendingEmail())
+		}
+
+		events := user.PullEvents()
+		if len(events) != 2 {
+			t.Fatalf("Expected UserEmailChangeRequested and UserEmailChangeNotice, got %v", events)
+		}
+		requested, ok := events[0].(UserEmailChangeRequested)
+		if !ok {
+			t.Fatalf("Expected UserEmailChangeRequested, got %v", events[0])
+		}
+		if requested.OldEmail != "usuario@teste.com" || requested.NewEmail != "novo@teste.com" {
+			t.Errorf("Expected old and new emails in the event, got %+v", requested)
+		}
+		if notice, ok := events[1].(UserEmailChangeNotice); !ok || notice.OldEmail != "usuario@teste.com" {
+			t.Errorf("Expected UserEmailChangeNotice to the current email, got %v", events[1])
+		}
+	})
+
+	t.Run("Rejects invalid and unchanged emails", func(t *testing.T) {
+		user := newVerifiedUser(t)
+
+		for _, email := range []string{"", "not-an-email", "usuario@teste.com"} {
+			if err := user.RequestEmailChange(email, "user:"+user.ID()); !errors.Is(err, ErrInvalidUser) {
+				t.Errorf("Expected ErrInvalidUser for %q, got %v", email, err)
+			}
+		}
+		if user.PendingEmail() != "" {
+			t.Errorf("Expected no pending email, got %s", user.PendingEmail())
+		}
+	})
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	t.Run("Swaps the email and keeps it verified", func(t *testing.T) {
+		user := newVerifiedUser(t)
+		_ = user.RequestEmailChange("novo@teste.com", "user:"+user.ID())
+		user.PullEvents()
+
+		if err := user.ConfirmEmailChange("user:" + user.ID()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if user.Email() != "novo@teste.com" || user.PendingEmail() != "" || user.EmailChangeRequestedAt() != nil {
+			t.Errorf("Expected email novo@teste.com without pending change, got %s (%q)", user.Email(), user.PendingEmail())
+		}
+		if user.EmailVerifiedAt() == nil {
+			t.Error("Expected the new email to be verified")
+		}
+
+		events := user.PullEvents()
+		if len(events) != 2 || events[0].EventName() != UserEmailChangedEvent || events[1].EventName() != UserEmailVerifiedEvent {
+			t.Errorf("Expected email changed and verified events, got %v", events)
+		}
+	})
+
+	t.Run("Activates a user pending verification", func(t *testing.T) {
+		user, _ := NewUser("typo@teste.con", "Test User")
+		_ = user.RequestEmailChange("typo@teste.com", "user:admin-1")
+
+		if err := user.ConfirmEmailChange("user:" + user.ID()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if user.Status() != StatusActive {
+			t.Errorf("Expected status active, got %s", user.Status())
+		}
+	})
+
+	t.Run("Fails without a pending change", func(t *testing.T) {
+		user := newVerifiedUser(t)
+
+		if err := user.ConfirmEmailChange("user:" + user.ID()); !errors.Is(err, ErrNoPendingEmailChange) {
+			t.Errorf("Expected ErrNoPendingEmailChange, got %v", err)
+		}
+	})
+}
+
+func TestCancelEmailChange(t *testing.T) {
+	user := newVerifiedUser(t)
+	_ = user.RequestEmailChange("novo@teste.com", "user:"+user.ID())
+	user.PullEvents()
+
+	if err := user.CancelEmailChange("user:" + user.ID()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.PendingEmail() != "" || user.Email() != "usuario@teste.com" {
+		t.Errorf("Expected the pending email to be discarded, got %q", user.PendingEmail())
+	}
+	if events := user.PullEvents(); len(events) != 1 || events[0].EventName() != UserEmailChangeCancelledEvent {
+		t.Errorf("Expected UserEmailChangeCancelled, got %v", events)
+	}
+
+	if err := user.CancelEmailChange("user:" + user.ID()); !errors.Is(err, ErrNoPendingEmailChange) {
+		t.Errorf("Expected ErrNoPendingEmailChange, got %v", err)
+	}
+}
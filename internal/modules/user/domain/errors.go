@@ -0,0 +1,43 @@
+package domain
+
+import "github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+
+// Erros do módulo user. Compare com errors.Is; os códigos são expostos na API e devem ser estáveis.
+var (
+	ErrUserNotFound       = apperror.NotFound("USER_NOT_FOUND", "user not found")
+	ErrEmailAlreadyExists = apperror.Conflict("EMAIL_ALREADY_EXISTS", "user with this email already exists")
+	ErrInvalidUser        = apperror.Validation("INVALID_USER", "invalid user data")
+	ErrInvalidPassword    = apperror.Validation("INVALID_PASSWORD", "password does not meet the password policy")
+	ErrInvalidCredentials = apperror.Unauthorized("INVALID_CREDENTIALS", "invalid email or password")
+	ErrAccountLocked      = apperror.RateLimited("ACCOUNT_LOCKED", "too many failed login attempts, try again later")
+	ErrUserInactive       = apperror.Forbidden("USER_INACTIVE", "user is not active")
+	ErrIncorrectPassword  = apperror.Forbidden("INCORRECT_PASSWORD", "current password is incorrect")
+	ErrUserNotDeleted     = apperror.Conflict("USER_NOT_DELETED", "user is not deleted")
+
+	ErrEmailNotVerified     = apperror.Forbidden("EMAIL_NOT_VERIFIED", "email address has not been verified yet")
+	ErrEmailAlreadyVerified = apperror.Conflict("EMAIL_ALREADY_VERIFIED", "email address is already verified")
+	ErrNoPendingEmailChange = apperror.Conflict("NO_PENDING_EMAIL_CHANGE", "there is no pending email change")
+
+	ErrInvalidStatusTransition = apperror.Conflict("INVALID_STATUS_TRANSITION", "user status transition is not allowed")
+	ErrInvalidStatusChange     = apperror.Validation("INVALID_STATUS_CHANGE", "status change requires a reason and an actor")
+
+	ErrVersionConflict = apperror.Conflict("VERSION_CONFLICT", "user was modified by another request, reload it and try again")
+	ErrVersionMismatch = apperror.PreconditionFailed("PRECONDITION_FAILED", "user version does not match If-Match")
+
+	ErrSessionNotFound     = apperror.NotFound("SESSION_NOT_FOUND", "session not found")
+	ErrInvalidRefreshToken = apperror.Unauthorized("INVALID_REFRESH_TOKEN", "refresh token is invalid")
+	ErrRefreshTokenReused  = apperror.Unauthorized("REFRESH_TOKEN_REUSED", "refresh token was already used; the session has been revoked")
+	ErrSessionExpired      = apperror.Unauthorized("SESSION_EXPIRED", "session has expired, log in again")
+	ErrSessionRevoked      = apperror.Unauthorized("SESSION_REVOKED", "session has been revoked, log in again")
+)
+
+// Códigos das violações de campo
+const (
+	FieldCodeRequired = "required"
+	FieldCodeEmail    = "email"
+	FieldCodeMax      = "max"
+	FieldCodeMin      = "min"
+	FieldCodeWeak     = "weak"
+	// FieldCodeUnchanged indica um valor novo igual ao atual
+	FieldCodeUnchanged = "unchanged"
+)
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	UserCreatedEvent   = "user.created"
+	UserUpdatedEvent   = "user.updated"
+	UserActivatedEvent = "user.activated"
+	UserSuspendedEvent = "user.suspended"
+	UserLockedEvent    = "user.locked"
+	UserDeletedEvent   = "user.deleted"
+	UserRestoredEvent  = "user.restored"
+
+	UserEmailVerifiedEvent        = "user.email_verified"
+	UserEmailChangeRequestedEvent = "user.email_change_requested"
+	UserEmailChangeNoticeEvent    = "user.email_change_notice"
+	UserEmailChangedEvent         = "user.email_changed"
+	UserEmailChangeCancelledEvent = "user.email_change_cancelled"
+
+	UserPasswordChangedEvent = "user.password_changed"
+)
+
+// UserCreated traz o status inicial: pending_verification quando o e-mail precisa ser confirmado
+type UserCreated struct {
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserCreated) EventName() string {
+	return UserCreatedEvent
+}
+
+type UserUpdated struct {
+	UserID     string    `json:"user_id"`
+	Name       string    `json:"name"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserUpdated) EventName() string {
+	return UserUpdatedEvent
+}
+
+// Actor identifica quem fez a mudança (ex.: "user:<id>", "api_key:<id>")
+type UserActivated struct {
+	UserID     string    `json:"user_id"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserActivated) EventName() string {
+	return UserActivatedEvent
+}
+
+type UserSuspended struct {
+	UserID     string    `json:"user_id"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserSuspended) EventName() string {
+	return UserSuspendedEvent
+}
+
+type UserLocked struct {
+	UserID     string    `json:"user_id"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserLocked) EventName() string {
+	return UserLockedEvent
+}
+
+type UserDeleted struct {
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserDeleted) EventName() string {
+	return UserDeletedEvent
+}
+
+type UserRestored struct {
+	UserID     string    `json:"user_id"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserRestored) EventName() string {
+	return UserRestoredEvent
+}
+
+// UserPasswordChanged não carrega o hash: consumidores só precisam saber que a senha mudou
+type UserPasswordChanged struct {
+	UserID     string    `json:"user_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserPasswordChanged) EventName() string {
+	return UserPasswordChangedEvent
+}
+
+// UserEmailVerified indica a confirmação do e-mail; um usuário pendente passa a ativo
+type UserEmailVerified struct {
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserEmailVerified) EventName() string {
+	return UserEmailVerifiedEvent
+}
+
+// UserEmailChangeRequested dispara a confirmação para NewEmail
+type UserEmailChangeRequested struct {
+	UserID     string    `json:"user_id"`
+	OldEmail   string    `json:"old_email"`
+	NewEmail   string    `json:"new_email"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserEmailChangeRequested) EventName() string {
+	return UserEmailChangeRequestedEvent
+}
+
+// UserEmailChangeNotice dispara o aviso para OldEmail. Fica separado da confirmação para
+// que a outbox reentregue cada e-mail sozinho quando o outro falha.
+type UserEmailChangeNotice struct {
+	UserID     string    `json:"user_id"`
+	OldEmail   string    `json:"old_email"`
+	NewEmail   string    `json:"new_email"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserEmailChangeNotice) EventName() string {
+	return UserEmailChangeNoticeEvent
+}
+
+// UserEmailChanged indica que o novo e-mail foi confirmado e passou a valer
+type UserEmailChanged struct {
+	UserID     string    `json:"user_id"`
+	OldEmail   string    `json:"old_email"`
+	NewEmail   string    `json:"new_email"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserEmailChanged) EventName() string {
+	return UserEmailChangedEvent
+}
+
+// UserEmailChangeCancelled traz o e-mail pendente descartado
+type UserEmailChangeCancelled struct {
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (UserEmailChangeCancelled) EventName() string {
+	return UserEmailChangeCancelledEvent
+}
@@ -2,13 +2,29 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
+// UserQueryServiceContract é o nome do contrato UserQueryService no registro de módulos
+const UserQueryServiceContract = "user.UserQueryService"
+
 type UserInfo struct {
 	ID     string `json:"id"`
 	Email  string `json:"email"`
 	Name   string `json:"name"`
 	Status string `json:"status"`
+	// EmailVerifiedAt é omitido enquanto o e-mail não foi confirmado
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// PendingEmail é o novo e-mail aguardando confirmação, se houver
+	PendingEmail string `json:"pending_email,omitempty"`
+	// StatusReason, StatusChangedBy e StatusChangedAt descrevem a última mudança de status
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedBy string     `json:"status_changed_by,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	// Version muda a cada alteração do usuário; é a base do ETag
+	Version int64 `json:"version"`
+	// DeletedAt só é preenchido para usuários excluídos logicamente
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type UserQueryService interface {
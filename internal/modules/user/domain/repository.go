@@ -2,12 +2,52 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
+// UserCursor é a posição de um usuário na ordenação padrão da listagem (created_at, id)
+type UserCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// CursorOf retorna a posição de user na ordenação padrão
+func CursorOf(user *User) UserCursor {
+	return UserCursor{CreatedAt: user.CreatedAt(), ID: user.ID()}
+}
+
+// UserRepository persiste o agregado User. Save grava os eventos pendentes do agregado
+// (PullEvents) na mesma transação da alteração. As buscas ignoram os usuários excluídos
+// logicamente, exceto FindDeletedByID e as listagens com IncludeDeleted.
 type UserRepository interface {
 	Save(ctx context.Context, user *User) error
+	// SaveCredentials grava só as credenciais (hash da senha, tentativas, bloqueio e último
+	// login) e os eventos pendentes. Não confere nem incrementa a versão: elas não fazem
+	// parte da representação do usuário, e um login não deve invalidar o ETag de quem edita.
+	SaveCredentials(ctx context.Context, user *User) error
 	FindByID(ctx context.Context, id string) (*User, error)
+	// FindDeletedByID busca apenas entre os usuários excluídos logicamente
+	FindDeletedByID(ctx context.Context, id string) (*User, error)
 	FindByEmail(ctx context.Context, email string) (*User, error)
-	FindAll(ctx context.Context, page, limit int) ([]*User, error)
-	Delete(ctx context.Context, id string) error
+	FindAll(ctx context.Context, criteria UserCriteria, page, limit int) ([]*User, error)
+	// FindAfter lista por keyset na ordenação padrão, a partir da posição seguinte a after (nil começa do início)
+	FindAfter(ctx context.Context, criteria UserCriteria, after *UserCursor, limit int) ([]*User, error)
+	Count(ctx context.Context, criteria UserCriteria) (int64, error)
+	// PurgeDeleted apaga de vez até limit usuários excluídos antes de before e retorna quantos apagou
+	PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// SessionRepository persiste sessões. Save registra o hash do refresh token vigente
+// na família da sessão, para que FindByTokenHash encontre a sessão também por tokens
+// já trocados (detecção de reuso).
+type SessionRepository interface {
+	Save(ctx context.Context, session *Session) error
+	// Rotate grava o refresh do token apenas se o token vigente ainda for previousHash e a
+	// sessão não estiver revogada; caso contrário outra requisição trocou o mesmo token
+	// antes e o retorno é ErrRefreshTokenReused
+	Rotate(ctx context.Context, session *Session, previousHash string) error
+	FindByID(ctx context.Context, id string) (*Session, error)
+	FindByTokenHash(ctx context.Context, hash string) (*Session, error)
+	// FindByUser lista as sessões não revogadas do usuário, das mais recentes para as mais antigas
+	FindByUser(ctx context.Context, userID string) ([]*Session, error)
 }
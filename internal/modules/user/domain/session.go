@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+// Motivos de revogação de sessão
+const (
+	RevokeReasonLogout      = "logout"
+	RevokeReasonRevoked     = "revoked"
+	RevokeReasonReuse       = "refresh_token_reuse"
+	RevokeReasonPassword    = "password_changed"
+	RevokeReasonUserDeleted = "user_deleted"
+)
+
+// Tamanhos máximos dos metadados, alinhados às colunas da tabela sessions
+const (
+	MaxUserAgentLength = 255
+	MaxIPLength        = 45
+)
+
+// SessionPolicy define quando uma sessão expira: sem uso por IdleTimeout ou,
+// em qualquer caso, AbsoluteTimeout após o login. Zero desliga o limite.
+type SessionPolicy struct {
+	IdleTimeout     time.Duration
+	AbsoluteTimeout time.Duration
+}
+
+// SessionMetadata descreve o dispositivo que usa a sessão
+type SessionMetadata struct {
+	UserAgent string
+	IP        string
+}
+
+// Session é um login de um usuário. Cada refresh troca o refresh token (rotação);
+// apresentar um token já trocado indica vazamento e revoga a sessão inteira, que
+// é a família de todos os tokens emitidos a partir do mesmo login.
+type Session struct {
+	id           string
+	userID       string
+	metadata     SessionMetadata
+	tokenHash    string
+	createdAt    time.Time
+	lastUsedAt   time.Time
+	revokedAt    *time.Time
+	revokeReason string
+}
+
+// NewSession abre uma sessão e retorna o refresh token em texto puro, que só existe
+// neste momento: apenas o hash é persistido
+func NewSession(userID string, metadata SessionMetadata, now time.Time) (*Session, string, error) {
+	session := &Session{
+		id:         uuid.New().String(),
+		userID:     userID,
+		metadata:   metadata.truncated(),
+		createdAt:  now,
+		lastUsedAt: now,
+	}
+
+	token, err := session.issueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+func ReconstructSession(id, userID string, metadata SessionMetadata, tokenHash string, createdAt, lastUsedAt time.Time, revokedAt *time.Time, revokeReason string) *Session {
+	return &Session{
+		id:           id,
+		userID:       userID,
+		metadata:     metadata,
+		tokenHash:    tokenHash,
+		createdAt:    createdAt,
+		lastUsedAt:   lastUsedAt,
+		revokedAt:    revokedAt,
+		revokeReason: revokeReason,
+	}
+}
+
+func (s *Session) ID() string {
+	return s.id
+}
+
+func (s *Session) UserID() string {
+	return s.userID
+}
+
+func (s *Session) Metadata() SessionMetadata {
+	return s.metadata
+}
+
+// TokenHash é o hash do refresh token vigente
+func (s *Session) TokenHash() string {
+	return s.tokenHash
+}
+
+func (s *Session) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+func (s *Session) LastUsedAt() time.Time {
+	return s.lastUsedAt
+}
+
+func (s *Session) RevokedAt() *time.Time {
+	return s.revokedAt
+}
+
+func (s *Session) RevokeReason() string {
+	return s.revokeReason
+}
+
+func (s *Session) IsRevoked() bool {
+	return s.revokedAt != nil
+}
+
+// ExpiresAt é o instante em que a sessão expira se não for usada de novo
+func (s *Session) ExpiresAt(policy SessionPolicy) *time.Time {
+	var expiresAt *time.Time
+	if policy.IdleTimeout > 0 {
+		idle := s.lastUsedAt.Add(policy.IdleTimeout)
+		expiresAt = &idle
+	}
+	if policy.AbsoluteTimeout > 0 {
+		absolute := s.createdAt.Add(policy.AbsoluteTimeout)
+		if expiresAt == nil || absolute.Before(*expiresAt) {
+			expiresAt = &absolute
+		}
+	}
+	return expiresAt
+}
+
+// IsActive informa se a sessão ainda pode ser renovada
+func (s *Session) IsActive(policy SessionPolicy, now time.Time) bool {
+	if s.IsRevoked() {
+		return false
+	}
+	expiresAt := s.ExpiresAt(policy)
+	return expiresAt == nil || now.Before(*expiresAt)
+}
+
+// Refresh confere o refresh token apresentado e o troca por um novo. Um token antigo
+// da mesma família revoga a sessão e retorna ErrRefreshTokenReused; o agregado deve
+// ser salvo também nesse caso.
+func (s *Session) Refresh(token string, policy SessionPolicy, metadata SessionMetadata, now time.Time) (string, error) {
+	if !auth.SecretMatches(token, s.tokenHash) {
+		if s.IsRevoked() {
+			return "", ErrSessionRevoked
+		}
+		s.Revoke(RevokeReasonReuse, now)
+		return "", ErrRefreshTokenReused
+	}
+
+	switch {
+	case s.IsRevoked():
+		return "", ErrSessionRevoked
+	case !s.IsActive(policy, now):
+		return "", ErrSessionExpired
+	}
+
+	next, err := s.issueToken()
+	if err != nil {
+		return "", err
+	}
+
+	s.metadata = metadata.truncated()
+	s.lastUsedAt = now
+	return next, nil
+}
+
+// Revoke encerra a sessão; revogar de novo mantém a data e o motivo originais
+func (s *Session) Revoke(reason string, now time.Time) {
+	if s.revokedAt != nil {
+		return
+	}
+	s.revokedAt = &now
+	s.revokeReason = reason
+}
+
+func (s *Session) issueToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	s.tokenHash = auth.HashSecret(token)
+	return token, nil
+}
+
+func (m SessionMetadata) truncated() SessionMetadata {
+	return SessionMetadata{UserAgent: truncate(m.UserAgent, MaxUserAgentLength), IP: truncate(m.IP, MaxIPLength)}
+}
+
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
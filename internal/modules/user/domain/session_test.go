@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+func TestSession(t *testing.T) {
+	now := time.Now()
+	policy := SessionPolicy{IdleTimeout: time.Hour, AbsoluteTimeout: 24 * time.Hour}
+
+	t.Run("Stores only the token hash", func(t *testing.T) {
+		session, token, err := NewSession("user-1", SessionMetadata{UserAgent: strings.Repeat("x", 300), IP: "10.0.0.1"}, now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if session.TokenHash() != auth.HashSecret(token) || strings.Contains(session.TokenHash(), token) {
+			t.Errorf("Expected stored hash of the token, got %q", session.TokenHash())
+		}
+		if len(session.Metadata().UserAgent) != MaxUserAgentLength {
+			t.Errorf("Expected user agent truncated to %d, got %d", MaxUserAgentLength, len(session.Metadata().UserAgent))
+		}
+	})
+
+	t.Run("Expiry is the earliest of idle and absolute", func(t *testing.T) {
+		session, _, _ := NewSession("user-1", SessionMetadata{}, now)
+
+		if expiresAt := session.ExpiresAt(policy); !expiresAt.Equal(now.Add(time.Hour)) {
+			t.Errorf("Expected idle expiry, got %v", expiresAt)
+		}
+
+		late := ReconstructSession(session.ID(), "user-1", SessionMetadata{}, session.TokenHash(), now, now.Add(23*time.Hour+30*time.Minute), nil, "")
+		if expiresAt := late.ExpiresAt(policy); !expiresAt.Equal(now.Add(24 * time.Hour)) {
+			t.Errorf("Expected absolute expiry, got %v", expiresAt)
+		}
+
+		if session.ExpiresAt(SessionPolicy{}) != nil {
+			t.Error("Expected no expiry without limits")
+		}
+	})
+
+	t.Run("Old token revokes the session", func(t *testing.T) {
+		session, first, _ := NewSession("user-1", SessionMetadata{}, now)
+		second, err := session.Refresh(first, policy, SessionMetadata{IP: "10.0.0.2"}, now.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if session.Metadata().IP != "10.0.0.2" || !session.LastUsedAt().Equal(now.Add(time.Minute)) {
+			t.Errorf("Expected usage to be recorded, got %+v at %v", session.Metadata(), session.LastUsedAt())
+		}
+
+		if _, err := session.Refresh(first, policy, SessionMetadata{}, now.Add(2*time.Minute)); !errors.Is(err, ErrRefreshTokenReused) {
+			t.Fatalf("Expected ErrRefreshTokenReused, got %v", err)
+		}
+		if _, err := session.Refresh(second, policy, SessionMetadata{}, now.Add(3*time.Minute)); !errors.Is(err, ErrSessionRevoked) {
+			t.Errorf("Expected ErrSessionRevoked, got %v", err)
+		}
+	})
+}
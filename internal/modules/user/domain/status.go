@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+type Status string
+
+const (
+	// StatusPendingVerification aguarda a confirmação do e-mail
+	StatusPendingVerification Status = "pending_verification"
+	StatusActive              Status = "active"
+	// StatusSuspended bloqueia o acesso por decisão administrativa; exige motivo
+	StatusSuspended Status = "suspended"
+	// StatusLocked bloqueia o acesso por segurança (ex.: credencial comprometida); exige motivo
+	StatusLocked Status = "locked"
+	// StatusDeleted marca a exclusão lógica; o registro é apagado de vez após a retenção
+	StatusDeleted Status = "deleted"
+)
+
+// MaxStatusReasonLength acompanha a coluna status_reason
+const MaxStatusReasonLength = 255
+
+// transitions lista, para cada status, os status para os quais o usuário pode ir.
+// Manter o mesmo status não é uma transição.
+var transitions = map[Status][]Status{
+	StatusPendingVerification: {StatusActive, StatusSuspended, StatusDeleted},
+	StatusActive:              {StatusSuspended, StatusLocked, StatusDeleted},
+	StatusSuspended:           {StatusActive, StatusDeleted},
+	StatusLocked:              {StatusActive, StatusSuspended, StatusDeleted},
+	// A restauração devolve o usuário suspenso; a reativação é uma decisão à parte
+	StatusDeleted: {StatusSuspended},
+}
+
+func (s Status) String() string {
+	return string(s)
+}
+
+func (s Status) IsValid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// CanTransitionTo informa se a máquina de estados permite ir de s para next
+func (s Status) CanTransitionTo(next Status) bool {
+	return slices.Contains(transitions[s], next)
+}
+
+// StatusChange descreve a última transição de status: quem fez, por quê e quando.
+// At zero indica que o status nunca mudou desde a criação.
+type StatusChange struct {
+	Reason string
+	Actor  string
+	At     time.Time
+}
+
+// transition aplica a mudança de status se a máquina de estados permitir
+func (u *User) transition(next Status, change StatusChange) error {
+	if !u.status.CanTransitionTo(next) {
+		return ErrInvalidStatusTransition.WithMessage(fmt.Sprintf("cannot change user status from %s to %s", u.status, next))
+	}
+
+	u.status = next
+	u.statusChange = change
+	return nil
+}
+
+// newStatusChange valida o autor e, quando exigido, o motivo da transição
+func newStatusChange(reason, actor string, reasonRequired bool) (StatusChange, error) {
+	reason = strings.TrimSpace(reason)
+
+	var violations []apperror.FieldError
+	switch {
+	case reason == "" && reasonRequired:
+		violations = append(violations, violation("reason", FieldCodeRequired, "reason is required"))
+	case utf8.RuneCountInString(reason) > MaxStatusReasonLength:
+		violations = append(violations, violation("reason", FieldCodeMax, fmt.Sprintf("reason must be at most %d characters", MaxStatusReasonLength)))
+	}
+	if strings.TrimSpace(actor) == "" {
+		violations = append(violations, violation("actor", FieldCodeRequired, "actor is required"))
+	}
+
+	if len(violations) > 0 {
+		return StatusChange{}, ErrInvalidStatusChange.WithFields(violations...)
+	}
+	return StatusChange{Reason: reason, Actor: actor, At: time.Now()}, nil
+}
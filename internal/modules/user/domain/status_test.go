@@ -0,0 +1,44 @@
+package domain
+
+import "testing"
+
+func TestStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		allowed  bool
+	}{
+		{StatusPendingVerification, StatusActive, true},
+		{StatusPendingVerification, StatusLocked, false},
+		{StatusActive, StatusSuspended, true},
+		{StatusActive, StatusLocked, true},
+		{StatusActive, StatusActive, false},
+		{StatusActive, StatusPendingVerification, false},
+		{StatusSuspended, StatusActive, true},
+		{StatusSuspended, StatusLocked, false},
+		{StatusLocked, StatusActive, true},
+		{StatusLocked, StatusSuspended, true},
+		{StatusLocked, StatusDeleted, true},
+		{StatusDeleted, StatusSuspended, true},
+		{StatusDeleted, StatusActive, false},
+		{"inactive", StatusActive, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.allowed {
+				t.Errorf("Expected CanTransitionTo = %v, got %v", tt.allowed, got)
+			}
+		})
+	}
+}
+
+func TestStatusIsValid(t *testing.T) {
+	for _, status := range []Status{StatusPendingVerification, StatusActive, StatusSuspended, StatusLocked, StatusDeleted} {
+		if !status.IsValid() {
+			t.Errorf("Expected %s to be valid", status)
+		}
+	}
+	if Status("inactive").IsValid() {
+		t.Error("Expected inactive to be invalid")
+	}
+}
@@ -2,44 +2,104 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/events"
 )
 
-type Status string
-
+// Limites alinhados às colunas VARCHAR(255) da tabela users
 const (
-	StatusActive   Status = "active"
-	StatusInactive Status = "inactive"
+	MaxEmailLength = 255
+	MaxNameLength  = 255
 )
 
-func (s Status) String() string {
-	return string(s)
-}
-
 type User struct {
-	id        string
-	email     string
-	name      string
-	status    Status
-	createdAt time.Time
+	id     string
+	email  string
+	name   string
+	status Status
+	// statusChange é a última transição de status
+	statusChange StatusChange
+	createdAt    time.Time
+	// emailVerifiedAt é nil enquanto o e-mail atual não foi confirmado
+	emailVerifiedAt *time.Time
+	// pendingEmail aguarda confirmação desde emailChangeRequestedAt; vazio sem troca em andamento
+	pendingEmail           string
+	emailChangeRequestedAt *time.Time
+	deletedAt              *time.Time
+	// version é incrementada pelo repositório a cada gravação; zero indica usuário ainda não gravado
+	version int64
+
+	credentials Credentials
+
+	events []events.Event
 }
 
+// NewUser cria o usuário pendente de verificação do e-mail; VerifyEmail o torna ativo
 func NewUser(email, name string) (*User, error) {
-	if email == "" || name == "" {
-		return nil, errors.New("email and name required")
+	user, err := newUser(email, name)
+	if err != nil {
+		return nil, err
+	}
+
+	user.recordCreated()
+	return user, nil
+}
+
+// NewActiveUser cria o usuário já ativo, para cadastros sem verificação de e-mail.
+// UserCreated sai com o status ativo, sem passar por pending_verification.
+func NewActiveUser(email, name, actor string) (*User, error) {
+	user, err := newUser(email, name)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := newStatusChange("", actor, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.transition(StatusActive, change); err != nil {
+		return nil, err
+	}
+
+	user.recordCreated()
+	return user, nil
+}
+
+func newUser(email, name string) (*User, error) {
+	email = strings.TrimSpace(email)
+	name = strings.TrimSpace(name)
+
+	violations := append(validateEmail(email), validateName(name)...)
+	if len(violations) > 0 {
+		return nil, ErrInvalidUser.WithFields(violations...)
 	}
 
 	return &User{
 		id:        uuid.New().String(),
 		email:     email,
 		name:      name,
-		status:    StatusActive,
+		status:    StatusPendingVerification,
 		createdAt: time.Now(),
 	}, nil
 }
 
+func (u *User) recordCreated() {
+	u.record(UserCreated{
+		UserID:     u.id,
+		Email:      u.email,
+		Name:       u.name,
+		Status:     u.status.String(),
+		OccurredAt: u.createdAt,
+	})
+}
+
 func ReconstructUser(id, email, name, status string, createdAt time.Time) (*User, error) {
 	if id == "" || email == "" || name == "" {
 		return nil, errors.New("id, email and name required")
@@ -74,18 +134,206 @@ func (u *User) CreatedAt() time.Time {
 	return u.createdAt
 }
 
+// LastStatusChange é a última transição de status
+func (u *User) LastStatusChange() StatusChange {
+	return u.statusChange
+}
+
+// RestoreStatusChange recarrega a última transição; usado pelo repositório ao reconstruir o agregado
+func (u *User) RestoreStatusChange(change StatusChange) {
+	u.statusChange = change
+}
+
+// EmailVerifiedAt é o momento da confirmação do e-mail atual; nil se não confirmado
+func (u *User) EmailVerifiedAt() *time.Time {
+	return u.emailVerifiedAt
+}
+
+// RestoreEmailVerifiedAt recarrega a confirmação do e-mail; usado pelo repositório ao reconstruir o agregado
+func (u *User) RestoreEmailVerifiedAt(verifiedAt *time.Time) {
+	u.emailVerifiedAt = verifiedAt
+}
+
+// DeletedAt é o momento da exclusão lógica; nil para usuários não excluídos
+func (u *User) DeletedAt() *time.Time {
+	return u.deletedAt
+}
+
+// RestoreDeletedAt recarrega o momento da exclusão; usado pelo repositório ao reconstruir o agregado
+func (u *User) RestoreDeletedAt(deletedAt *time.Time) {
+	u.deletedAt = deletedAt
+}
+
+func (u *User) Version() int64 {
+	return u.version
+}
+
+// RestoreVersion recarrega a versão gravada; usado pelo repositório ao reconstruir e ao gravar o agregado
+func (u *User) RestoreVersion(version int64) {
+	u.version = version
+}
+
+// CheckVersion confere a versão esperada pelo cliente (If-Match); nil dispensa a verificação
+func (u *User) CheckVersion(expected *int64) error {
+	if expected != nil && *expected != u.version {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
 func (u *User) UpdateName(name string) error {
-	if name == "" {
-		return errors.New("name required")
+	name = strings.TrimSpace(name)
+	if violations := validateName(name); len(violations) > 0 {
+		return ErrInvalidUser.WithFields(violations...)
 	}
+	if u.name == name {
+		return nil
+	}
+
 	u.name = name
+	u.record(UserUpdated{UserID: u.id, Name: u.name, OccurredAt: time.Now()})
+	return nil
+}
+
+// Activate reativa um usuário suspenso, bloqueado ou ainda não verificado
+func (u *User) Activate(actor string) error {
+	change, err := newStatusChange("", actor, false)
+	if err != nil {
+		return err
+	}
+	if err := u.transition(StatusActive, change); err != nil {
+		return err
+	}
+
+	u.record(UserActivated{UserID: u.id, Actor: actor, OccurredAt: change.At})
+	return nil
+}
+
+// VerifyEmail confirma o e-mail atual. Usuários pendentes passam a ativos; os demais
+// mantêm o status (um suspenso continua suspenso).
+func (u *User) VerifyEmail(actor string) error {
+	if u.emailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	if u.status == StatusPendingVerification {
+		change, err := newStatusChange("email verified", actor, true)
+		if err != nil {
+			return err
+		}
+		if err := u.transition(StatusActive, change); err != nil {
+			return err
+		}
+		now = change.At
+	}
+
+	u.emailVerifiedAt = &now
+	u.record(UserEmailVerified{UserID: u.id, Email: u.email, OccurredAt: now})
+	return nil
+}
+
+// Suspend bloqueia o acesso por decisão administrativa; motivo e autor são obrigatórios
+func (u *User) Suspend(reason, actor string) error {
+	change, err := newStatusChange(reason, actor, true)
+	if err != nil {
+		return err
+	}
+	if err := u.transition(StatusSuspended, change); err != nil {
+		return err
+	}
+
+	u.record(UserSuspended{UserID: u.id, Reason: change.Reason, Actor: actor, OccurredAt: change.At})
+	return nil
+}
+
+// Lock bloqueia o acesso por segurança; motivo e autor são obrigatórios
+func (u *User) Lock(reason, actor string) error {
+	change, err := newStatusChange(reason, actor, true)
+	if err != nil {
+		return err
+	}
+	if err := u.transition(StatusLocked, change); err != nil {
+		return err
+	}
+
+	u.record(UserLocked{UserID: u.id, Reason: change.Reason, Actor: actor, OccurredAt: change.At})
+	return nil
+}
+
+// Delete exclui o usuário logicamente; o e-mail continua reservado até a remoção definitiva
+func (u *User) Delete(actor string) error {
+	change, err := newStatusChange("", actor, false)
+	if err != nil {
+		return err
+	}
+	if err := u.transition(StatusDeleted, change); err != nil {
+		return err
+	}
+
+	u.deletedAt = &change.At
+	u.record(UserDeleted{UserID: u.id, Email: u.email, Actor: actor, OccurredAt: change.At})
+	return nil
+}
+
+// Restore desfaz a exclusão lógica. O usuário volta suspenso: a reativação é uma
+// decisão à parte.
+func (u *User) Restore(actor string) error {
+	if u.status != StatusDeleted {
+		return ErrUserNotDeleted
+	}
+
+	change, err := newStatusChange("restored after deletion", actor, true)
+	if err != nil {
+		return err
+	}
+	if err := u.transition(StatusSuspended, change); err != nil {
+		return err
+	}
+
+	u.deletedAt = nil
+	u.record(UserRestored{UserID: u.id, Actor: actor, OccurredAt: change.At})
+	return nil
+}
+
+// PullEvents retorna os eventos registrados desde a última chamada e limpa a lista
+func (u *User) PullEvents() []events.Event {
+	pending := u.events
+	u.events = nil
+	return pending
+}
+
+func validateEmail(email string) []apperror.FieldError {
+	switch {
+	case email == "":
+		return []apperror.FieldError{violation("email", FieldCodeRequired, "email is required")}
+	case utf8.RuneCountInString(email) > MaxEmailLength:
+		return []apperror.FieldError{violation("email", FieldCodeMax, fmt.Sprintf("email must be at most %d characters", MaxEmailLength))}
+	}
+
+	// Aceita apenas o endereço puro, sem nome de exibição ("Fulano <a@b.com>")
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return []apperror.FieldError{violation("email", FieldCodeEmail, "email must be a valid email address")}
+	}
+
+	return nil
+}
+
+func validateName(name string) []apperror.FieldError {
+	switch {
+	case name == "":
+		return []apperror.FieldError{violation("name", FieldCodeRequired, "name is required")}
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		return []apperror.FieldError{violation("name", FieldCodeMax, fmt.Sprintf("name must be at most %d characters", MaxNameLength))}
+	}
+
 	return nil
 }
 
-func (u *User) Activate() {
-	u.status = StatusActive
+func violation(field, code, message string) apperror.FieldError {
+	return apperror.FieldError{Field: field, Code: code, Message: message}
 }
 
-func (u *User) Deactivate() {
-	u.status = StatusInactive
+func (u *User) record(event events.Event) {
+	u.events = append(u.events, event)
 }
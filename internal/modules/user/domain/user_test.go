@@ -1,8 +1,13 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
 )
 
 func TestNewUser(t *testing.T) {
@@ -66,8 +71,8 @@ func TestNewUser(t *testing.T) {
 				t.Errorf("Expected name %s, got %s", tt.userName, user.Name())
 			}
 
-			if user.Status() != StatusActive {
-				t.Errorf("Expected status %s, got %s", StatusActive, user.Status())
+			if user.Status() != StatusPendingVerification {
+				t.Errorf("Expected status %s, got %s", StatusPendingVerification, user.Status())
 			}
 
 			now := time.Now()
@@ -79,6 +84,82 @@ func TestNewUser(t *testing.T) {
 	}
 }
 
+func TestNewUserValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		userName string
+		want     map[string]string
+	}{
+		{
+			name:     "All violations reported together",
+			email:    "",
+			userName: "",
+			want:     map[string]string{"email": FieldCodeRequired, "name": FieldCodeRequired},
+		},
+		{
+			name:     "Whitespace-only values",
+			email:    "   ",
+			userName: "\t ",
+			want:     map[string]string{"email": FieldCodeRequired, "name": FieldCodeRequired},
+		},
+		{
+			name:     "Invalid email format",
+			email:    "not-an-email",
+			userName: "Test User",
+			want:     map[string]string{"email": FieldCodeEmail},
+		},
+		{
+			name:     "Email with display name",
+			email:    "Test <usuario@teste.com>",
+			userName: "Test User",
+			want:     map[string]string{"email": FieldCodeEmail},
+		},
+		{
+			name:     "Too long values",
+			email:    strings.Repeat("a", MaxEmailLength) + "@teste.com",
+			userName: strings.Repeat("é", MaxNameLength+1),
+			want:     map[string]string{"email": FieldCodeMax, "name": FieldCodeMax},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewUser(tt.email, tt.userName)
+
+			if !errors.Is(err, ErrInvalidUser) {
+				t.Fatalf("Expected ErrInvalidUser, got %v", err)
+			}
+
+			appErr, _ := apperror.As(err)
+			got := map[string]string{}
+			for _, field := range appErr.Fields {
+				got[field.Field] = field.Code
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected violations %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("Values are trimmed", func(t *testing.T) {
+		user, err := NewUser(" usuario@teste.com ", "  Test User ")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if user.Email() != "usuario@teste.com" || user.Name() != "Test User" {
+			t.Errorf("Expected trimmed values, got %q and %q", user.Email(), user.Name())
+		}
+	})
+
+	t.Run("Name at the length limit is accepted", func(t *testing.T) {
+		if _, err := NewUser("usuario@teste.com", strings.Repeat("é", MaxNameLength)); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
 func TestReconstructUser(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -184,6 +265,16 @@ func TestUpdateName(t *testing.T) {
 			newName:     "",
 			expectError: true,
 		},
+		{
+			name:        "Whitespace-only name",
+			newName:     "   ",
+			expectError: true,
+		},
+		{
+			name:        "Too long name",
+			newName:     strings.Repeat("a", MaxNameLength+1),
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -209,51 +300,240 @@ func TestUpdateName(t *testing.T) {
 	}
 }
 
-func TestActivateDeactivate(t *testing.T) {
-	t.Run("Activate inactive user", func(t *testing.T) {
+func TestSuspendActivateLock(t *testing.T) {
+	t.Run("Suspend records reason and actor", func(t *testing.T) {
 		user, _ := NewUser("usuario@teste.com", "Test User")
-		user.Deactivate()
+		user.PullEvents()
 
-		if user.Status() != StatusInactive {
-			t.Errorf("Expected status %s after deactivate, got %s", StatusInactive, user.Status())
+		if err := user.Suspend("  chargeback  ", "user:admin-1"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		user.Activate()
+		if user.Status() != StatusSuspended {
+			t.Errorf("Expected status %s, got %s", StatusSuspended, user.Status())
+		}
+		change := user.LastStatusChange()
+		if change.Reason != "chargeback" || change.Actor != "user:admin-1" || change.At.IsZero() {
+			t.Errorf("Expected the status change to be recorded, got %+v", change)
+		}
+		events := user.PullEvents()
+		if len(events) != 1 || events[0].EventName() != UserSuspendedEvent {
+			t.Fatalf("Expected one %s event, got %v", UserSuspendedEvent, events)
+		}
+		if event := events[0].(UserSuspended); event.Reason != "chargeback" || event.Actor != "user:admin-1" {
+			t.Errorf("Expected reason and actor in the event, got %+v", event)
+		}
+	})
+
+	t.Run("Activate suspended user", func(t *testing.T) {
+		user, _ := NewUser("usuario@teste.com", "Test User")
+		_ = user.Suspend("chargeback", "admin")
+
+		if err := user.Activate("admin"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
 
 		if user.Status() != StatusActive {
 			t.Errorf("Expected status %s after activate, got %s", StatusActive, user.Status())
 		}
+		if change := user.LastStatusChange(); change.Reason != "" || change.Actor != "admin" {
+			t.Errorf("Expected the previous reason to be cleared, got %+v", change)
+		}
+	})
+
+	t.Run("Lock active user", func(t *testing.T) {
+		user := newVerifiedUser(t)
+
+		if err := user.Lock("leaked credentials", "system"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if user.Status() != StatusLocked {
+			t.Errorf("Expected status %s, got %s", StatusLocked, user.Status())
+		}
+	})
+
+	t.Run("Illegal transitions are rejected", func(t *testing.T) {
+		user := newVerifiedUser(t)
+
+		if err := user.Activate("admin"); !errors.Is(err, ErrInvalidStatusTransition) {
+			t.Errorf("Expected ErrInvalidStatusTransition activating an active user, got %v", err)
+		}
+
+		_ = user.Suspend("chargeback", "admin")
+		if err := user.Lock("leaked credentials", "admin"); !errors.Is(err, ErrInvalidStatusTransition) {
+			t.Errorf("Expected ErrInvalidStatusTransition locking a suspended user, got %v", err)
+		}
+		if user.Status() != StatusSuspended || user.LastStatusChange().Reason != "chargeback" {
+			t.Errorf("Expected the rejected transition to change nothing, got %s", user.Status())
+		}
+		if events := user.PullEvents(); len(events) != 1 {
+			t.Errorf("Expected only the suspension event, got %v", events)
+		}
 	})
 
-	t.Run("Deactivate active user", func(t *testing.T) {
+	t.Run("Reason and actor are required", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			reason string
+			actor  string
+			field  string
+		}{
+			{name: "Missing reason", reason: "  ", actor: "admin", field: "reason"},
+			{name: "Reason too long", reason: strings.Repeat("a", MaxStatusReasonLength+1), actor: "admin", field: "reason"},
+			{name: "Missing actor", reason: "chargeback", actor: "", field: "actor"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				user := newVerifiedUser(t)
+
+				err := user.Suspend(tt.reason, tt.actor)
+				if !errors.Is(err, ErrInvalidStatusChange) {
+					t.Fatalf("Expected ErrInvalidStatusChange, got %v", err)
+				}
+				var appErr *apperror.Error
+				if !errors.As(err, &appErr) || len(appErr.Fields) != 1 || appErr.Fields[0].Field != tt.field {
+					t.Errorf("Expected a violation on %s, got %v", tt.field, err)
+				}
+				if user.Status() != StatusActive {
+					t.Errorf("Expected status to stay %s, got %s", StatusActive, user.Status())
+				}
+			})
+		}
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	t.Run("Pending user becomes active", func(t *testing.T) {
 		user, _ := NewUser("usuario@teste.com", "Test User")
+		user.PullEvents()
 
-		if user.Status() != StatusActive {
-			t.Errorf("Expected initial status %s, got %s", StatusActive, user.Status())
+		if err := user.VerifyEmail("user:" + user.ID()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if user.Status() != StatusActive || user.EmailVerifiedAt() == nil {
+			t.Errorf("Expected active user with verified email, got status %s", user.Status())
+		}
+		if change := user.LastStatusChange(); change.Actor != "user:"+user.ID() {
+			t.Errorf("Expected the user as actor, got %+v", change)
+		}
+		if events := user.PullEvents(); len(events) != 1 || events[0].EventName() != UserEmailVerifiedEvent {
+			t.Errorf("Expected one %s event, got %v", UserEmailVerifiedEvent, events)
+		}
+	})
+
+	t.Run("Suspended user keeps the status", func(t *testing.T) {
+		user, _ := NewUser("usuario@teste.com", "Test User")
+		_ = user.Suspend("chargeback", "admin")
+
+		if err := user.VerifyEmail("user:" + user.ID()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		user.Deactivate()
+		if user.Status() != StatusSuspended || user.EmailVerifiedAt() == nil {
+			t.Errorf("Expected suspended user with verified email, got status %s", user.Status())
+		}
+	})
 
-		if user.Status() != StatusInactive {
-			t.Errorf("Expected status %s after deactivate, got %s", StatusInactive, user.Status())
+	t.Run("Email can only be verified once", func(t *testing.T) {
+		user := newVerifiedUser(t)
+
+		if err := user.VerifyEmail("user:" + user.ID()); !errors.Is(err, ErrEmailAlreadyVerified) {
+			t.Errorf("Expected ErrEmailAlreadyVerified, got %v", err)
 		}
 	})
 }
 
-func TestStatusString(t *testing.T) {
-	tests := []struct {
-		status Status
-		want   string
-	}{
-		{StatusActive, "active"},
-		{StatusInactive, "inactive"},
+// newVerifiedUser cria um usuário já ativo, sem eventos pendentes
+func newVerifiedUser(t *testing.T) *User {
+	t.Helper()
+
+	user, _ := NewUser("usuario@teste.com", "Test User")
+	if err := user.VerifyEmail("user:" + user.ID()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
 	}
+	user.PullEvents()
+	return user
+}
 
-	for _, tt := range tests {
-		t.Run(tt.want, func(t *testing.T) {
-			if got := tt.status.String(); got != tt.want {
-				t.Errorf("Status.String() = %v, want %v", got, tt.want)
-			}
-		})
+func TestDeleteRestore(t *testing.T) {
+	t.Run("Delete marks the user as deleted", func(t *testing.T) {
+		user, _ := NewUser("usuario@teste.com", "Test User")
+		user.PullEvents()
+
+		if err := user.Delete("admin"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if user.Status() != StatusDeleted || user.DeletedAt() == nil {
+			t.Errorf("Expected deleted user, got status %s", user.Status())
+		}
+		if events := user.PullEvents(); len(events) != 1 || events[0].EventName() != UserDeletedEvent {
+			t.Errorf("Expected one %s event, got %v", UserDeletedEvent, events)
+		}
+	})
+
+	t.Run("Restore brings the user back suspended", func(t *testing.T) {
+		user, _ := NewUser("usuario@teste.com", "Test User")
+		_ = user.Delete("admin")
+		user.PullEvents()
+
+		if err := user.Restore("admin"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if user.Status() != StatusSuspended || user.DeletedAt() != nil {
+			t.Errorf("Expected suspended user without deleted_at, got status %s", user.Status())
+		}
+		if user.LastStatusChange().Reason == "" {
+			t.Error("Expected the restore to record a reason")
+		}
+		if events := user.PullEvents(); len(events) != 1 || events[0].EventName() != UserRestoredEvent {
+			t.Errorf("Expected one %s event, got %v", UserRestoredEvent, events)
+		}
+	})
+
+	t.Run("Restore of a user that is not deleted", func(t *testing.T) {
+		user, _ := NewUser("usuario@teste.com", "Test User")
+
+		if err := user.Restore("admin"); !errors.Is(err, ErrUserNotDeleted) {
+			t.Errorf("Expected ErrUserNotDeleted, got %v", err)
+		}
+	})
+}
+
+func TestPullEvents(t *testing.T) {
+	user, _ := NewUser("usuario@teste.com", "Test User")
+
+	pending := user.PullEvents()
+	if len(pending) != 1 || pending[0].EventName() != UserCreatedEvent {
+		t.Fatalf("Expected a single %s event, got %v", UserCreatedEvent, pending)
+	}
+
+	if len(user.PullEvents()) != 0 {
+		t.Error("Expected events to be cleared after pull")
+	}
+
+	_ = user.UpdateName("Test User")
+	_ = user.Lock("leaked credentials", "admin")
+	if len(user.PullEvents()) != 0 {
+		t.Error("Expected no events when nothing changes")
+	}
+
+	_ = user.UpdateName("New Name")
+	_ = user.Suspend("chargeback", "admin")
+	_ = user.Delete("admin")
+
+	want := []string{UserUpdatedEvent, UserSuspendedEvent, UserDeletedEvent}
+	pending = user.PullEvents()
+	if len(pending) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(pending))
+	}
+	for i, name := range want {
+		if pending[i].EventName() != name {
+			t.Errorf("Expected event %s at %d, got %s", name, i, pending[i].EventName())
+		}
 	}
 }
@@ -1,12 +1,88 @@
 package http
 
+import "time"
+
+// Os limites de tamanho acompanham as colunas VARCHAR(255); strings chegam sem espaços nas pontas (validation.BindJSON)
 type CreateUserRequest struct {
-	Email string `json:"email"`
-	Name  string `json:"name"`
+	Email string `json:"email" binding:"required,email,max=255"`
+	Name  string `json:"name" binding:"required,max=255"`
+	// Password é opcional; a política de senha é aplicada pelo domínio
+	Password *string `json:"password,omitempty" trim:"-"`
 }
 
 type UpdateUserRequest struct {
-	Name string `json:"name"`
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// StatusChangeRequest é o corpo de PUT /users/:id/deactivate e PUT /users/:id/lock
+type StatusChangeRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// ListUsersRequest são os filtros e a ordenação de GET /users (page, limit e cursor são lidos à parte)
+type ListUsersRequest struct {
+	Status        string     `form:"status"`
+	Email         string     `form:"email" binding:"max=255"`
+	Name          string     `form:"name" binding:"max=255"`
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort          string     `form:"sort"`
+	// IncludeDeleted lista também os usuários excluídos; exige users:admin
+	IncludeDeleted bool `form:"include_deleted"`
+}
+
+// Senhas não passam pelo trim: espaços nas pontas fazem parte delas
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,max=255"`
+	Password string `json:"password" binding:"required" trim:"-"`
+}
+
+// CurrentPassword é dispensado quando um administrador redefine a senha de outro usuário
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" trim:"-"`
+	NewPassword     string `json:"new_password" binding:"required" trim:"-"`
+}
+
+// VerifyEmailRequest é o corpo de POST /users/verify-email, com o token do link
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+// EmailChangeRequest é o corpo de POST /users/:id/email-change
+type EmailChangeRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+// RefreshTokenRequest é o corpo de POST /auth/refresh e POST /auth/logout
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenResponse struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int64     `json:"expires_in"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+	SessionID    string    `json:"session_id"`
+}
+
+type SessionResponse struct {
+	ID         string     `json:"id"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Current    bool       `json:"current"`
+}
+
+type SessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
 }
 
 type UserResponse struct {
@@ -14,15 +90,31 @@ type UserResponse struct {
 	Email  string `json:"email"`
 	Name   string `json:"name"`
 	Status string `json:"status"`
+	// EmailVerifiedAt é omitido enquanto o e-mail não foi confirmado
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// PendingEmail é o novo e-mail aguardando confirmação, se houver; só para o próprio usuário ou users:admin
+	PendingEmail string `json:"pending_email,omitempty"`
+	// Motivo, autor e momento da última mudança de status; motivo e autor só para users:admin
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedBy string     `json:"status_changed_by,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
 }
 
 type UsersResponse struct {
-	Users []UserResponse `json:"users"`
-	Page  int            `json:"page"`
-	Limit int            `json:"limit"`
-	Total int            `json:"total"`
+	Users      []UserResponse `json:"users"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	Total      int64          `json:"total"`
+	TotalPages int            `json:"total_pages"`
+	HasNext    bool           `json:"has_next"`
+	NextCursor string         `json:"next_cursor,omitempty"`
 }
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+// UsersCursorResponse é a resposta da paginação por cursor, que não calcula o total
+type UsersCursorResponse struct {
+	Users      []UserResponse `json:"users"`
+	Limit      int            `json:"limit"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	HasNext    bool           `json:"has_next"`
 }
@@ -3,157 +3,629 @@ package http
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/app"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/etag"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/pagination"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/patch"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/problem"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/validation"
+)
+
+var errMissingID = apperror.BadRequest("MISSING_USER_ID", "user ID is required")
+
+// Campos de UserResponse que o PATCH aceita e os que só mudam por rotas próprias
+// (status por /activate e /deactivate)
+var (
+	patchableFields = []string{"name"}
+	immutableFields = []string{"id", "email", "status"}
 )
 
 type UserHandlers struct {
 	service *app.UserService
+	auth    *app.AuthService
+	emails  *app.EmailService
+	keys    auth.APIKeyResolver
+	tokens  auth.TokenVerifier
+	authz   auth.Authorizer
+	limits  ratelimit.Limits
+	// idempotent é o middleware de Idempotency-Key das rotas que modificam estado
+	idempotent gin.HandlerFunc
 }
 
-func NewUserHandlers(service *app.UserService) *UserHandlers {
-	return &UserHandlers{service: service}
+func NewUserHandlers(service *app.UserService, authService *app.AuthService, emails *app.EmailService, keys auth.APIKeyResolver, tokens auth.TokenVerifier, authz auth.Authorizer, limits ratelimit.Limits, idempotent gin.HandlerFunc) *UserHandlers {
+	return &UserHandlers{service: service, auth: authService, emails: emails, keys: keys, tokens: tokens, authz: authz, limits: limits, idempotent: idempotent}
 }
 
 func (h *UserHandlers) CreateUser(c *gin.Context) {
 	var req CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
 		return
 	}
 
 	cmd := app.CreateUserCommand{
-		Email: req.Email,
-		Name:  req.Name,
+		Email:    req.Email,
+		Name:     req.Name,
+		Password: req.Password,
 	}
 
 	user, err := h.service.CreateUser(c.Request.Context(), cmd)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		problem.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, UserResponse{
-		ID:     user.ID,
-		Email:  user.Email,
-		Name:   user.Name,
-		Status: user.Status,
-	})
+	h.respondUser(c, http.StatusCreated, user)
 }
 
 func (h *UserHandlers) GetUser(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "User ID is required"})
+		problem.Respond(c, errMissingID)
 		return
 	}
 
 	query := app.GetUserQuery{ID: id}
 	user, err := h.service.GetUser(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		problem.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, UserResponse{
-		ID:     user.ID,
-		Email:  user.Email,
-		Name:   user.Name,
-		Status: user.Status,
-	})
+	// O cliente que já tem esta versão recebe 304 sem corpo
+	tag := etag.Format(user.Version)
+	if etag.NoneMatch(c.GetHeader(etag.HeaderIfNoneMatch), tag) {
+		c.Header(etag.HeaderETag, tag)
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	h.respondUser(c, http.StatusOK, user)
 }
 
 func (h *UserHandlers) UpdateUser(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "User ID is required"})
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	expected, err := etag.ExpectedVersion(c.GetHeader(etag.HeaderIfMatch))
+	if err != nil {
+		problem.Respond(c, err)
 		return
 	}
 
 	var req UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
 		return
 	}
 
 	cmd := app.UpdateUserCommand{
-		ID:   id,
-		Name: req.Name,
+		ID:              id,
+		Name:            req.Name,
+		ExpectedVersion: expected,
 	}
 
 	user, err := h.service.UpdateUser(c.Request.Context(), cmd)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		problem.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, UserResponse{
-		ID:     user.ID,
-		Email:  user.Email,
-		Name:   user.Name,
-		Status: user.Status,
-	})
+	h.respondUser(c, http.StatusOK, user)
+}
+
+// PatchUser aceita JSON Merge Patch e, para membros de primeiro nível, JSON Patch
+func (h *UserHandlers) PatchUser(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	expected, err := etag.ExpectedVersion(c.GetHeader(etag.HeaderIfMatch))
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	doc, err := patch.Bind(c)
+	if err != nil {
+		c.Header(patch.HeaderAcceptPatch, patch.AcceptPatch())
+		problem.Respond(c, err)
+		return
+	}
+	if err := doc.Check(patchableFields, immutableFields); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	name, err := patch.Field[string](doc, "name")
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.PatchUserCommand{ID: id, Name: name, ExpectedVersion: expected}
+	user, err := h.service.PatchUser(c.Request.Context(), cmd)
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	h.respondUser(c, http.StatusOK, user)
 }
 
 func (h *UserHandlers) DeleteUser(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "User ID is required"})
+		problem.Respond(c, errMissingID)
 		return
 	}
 
-	cmd := app.DeleteUserCommand{ID: id}
+	expected, err := etag.ExpectedVersion(c.GetHeader(etag.HeaderIfMatch))
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.DeleteUserCommand{ID: id, ExpectedVersion: expected}
 	if err := h.service.DeleteUser(c.Request.Context(), cmd); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		problem.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandlers) ChangePassword(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.ChangePasswordCommand{
+		UserID:          id,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}
+
+	if err := h.auth.ChangePassword(c.Request.Context(), cmd); err != nil {
+		problem.Respond(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
+func (h *UserHandlers) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.LoginCommand{
+		Email:     req.Email,
+		Password:  req.Password,
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+
+	tokens, err := h.auth.Login(c.Request.Context(), cmd)
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	respondTokens(c, tokens)
+}
+
+// VerifyEmail consome o token do link de confirmação e devolve o usuário ativado
+func (h *UserHandlers) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	user, err := h.emails.VerifyEmail(c.Request.Context(), app.VerifyEmailCommand{Token: req.Token})
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	h.respondUser(c, http.StatusOK, user)
+}
+
+// ResendVerification responde 202 exista ou não o e-mail, para não revelar cadastros
+func (h *UserHandlers) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	if err := h.emails.ResendVerification(c.Request.Context(), app.ResendVerificationCommand{Email: req.Email}); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// RequestEmailChange registra o novo e-mail; a troca só vale depois da confirmação, por
+// isso a resposta é 202 com o e-mail pendente em pending_email
+func (h *UserHandlers) RequestEmailChange(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	expected, err := etag.ExpectedVersion(c.GetHeader(etag.HeaderIfMatch))
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	var req EmailChangeRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.RequestEmailChangeCommand{UserID: id, Email: req.Email, ExpectedVersion: expected}
+	user, err := h.emails.RequestEmailChange(c.Request.Context(), cmd)
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	h.respondUser(c, http.StatusAccepted, user)
+}
+
+func (h *UserHandlers) CancelEmailChange(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	expected, err := etag.ExpectedVersion(c.GetHeader(etag.HeaderIfMatch))
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.CancelEmailChangeCommand{UserID: id, ExpectedVersion: expected}
+	user, err := h.emails.CancelEmailChange(c.Request.Context(), cmd)
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	h.respondUser(c, http.StatusOK, user)
+}
+
+// ConfirmEmailChange consome o token enviado ao novo endereço e devolve o usuário já com o novo e-mail
+func (h *UserHandlers) ConfirmEmailChange(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	user, err := h.emails.ConfirmEmailChange(c.Request.Context(), app.ConfirmEmailChangeCommand{Token: req.Token})
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	h.respondUser(c, http.StatusOK, user)
+}
+
+func (h *UserHandlers) Refresh(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.RefreshCommand{
+		RefreshToken: req.RefreshToken,
+		ClientIP:     c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+	}
+
+	tokens, err := h.auth.Refresh(c.Request.Context(), cmd)
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	respondTokens(c, tokens)
+}
+
+func (h *UserHandlers) Logout(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	if err := h.auth.Logout(c.Request.Context(), app.LogoutCommand{RefreshToken: req.RefreshToken}); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandlers) ListSessions(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	sessions, err := h.auth.ListSessions(c.Request.Context(), app.ListSessionsQuery{UserID: id})
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	response := SessionsResponse{Sessions: make([]SessionResponse, len(sessions))}
+	for i, session := range sessions {
+		response.Sessions[i] = SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.Current,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandlers) RevokeSession(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	cmd := app.RevokeSessionCommand{UserID: id, SessionID: c.Param("session_id")}
+	if err := h.auth.RevokeSession(c.Request.Context(), cmd); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandlers) RevokeAllSessions(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	if err := h.auth.RevokeAllSessions(c.Request.Context(), app.RevokeAllSessionsCommand{UserID: id}); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// authorizeAdmin exige users:admin para as opções restritas das rotas públicas
+func (h *UserHandlers) authorizeAdmin(c *gin.Context) error {
+	principal, ok := auth.PrincipalFrom(c)
+	if !ok {
+		return auth.ErrUnauthenticated
+	}
+
+	allowed, err := h.authz.Can(c.Request.Context(), principal, app.ScopeAdmin)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return auth.ErrPermissionDenied.WithMessage("missing required permission: " + app.ScopeAdmin)
+	}
+	return nil
+}
+
+// respondUser escreve o usuário com o ETag da sua versão, usado em If-Match e If-None-Match
+func (h *UserHandlers) respondUser(c *gin.Context, status int, user *domain.UserInfo) {
+	c.Header(etag.HeaderETag, etag.Format(user.Version))
+	c.JSON(status, toUserResponse(user, h.viewerOf(c)))
+}
+
+// viewer é quem lê a resposta; as leituras são públicas, então os campos de moderação
+// só aparecem para users:admin e o e-mail pendente, para o próprio usuário ou um admin
+type viewer struct {
+	admin   bool
+	subject string
+}
+
+// owns informa se o viewer é o próprio usuário (token de usuário, não API key)
+func (v viewer) owns(user *domain.UserInfo) bool {
+	return v.subject != "" && v.subject == user.ID
+}
+
+// viewerOf resolve o viewer da requisição; sem principal, ou se o autorizador falhar, nada extra é exibido
+func (h *UserHandlers) viewerOf(c *gin.Context) viewer {
+	principal, ok := auth.PrincipalFrom(c)
+	if !ok {
+		return viewer{}
+	}
+
+	admin, err := h.authz.Can(c.Request.Context(), principal, app.ScopeAdmin)
+	v := viewer{admin: err == nil && admin}
+	if principal.Kind == auth.KindUser {
+		v.subject = principal.Subject
+	}
+	return v
+}
+
+func toUserResponse(user *domain.UserInfo, v viewer) UserResponse {
+	response := UserResponse{
+		ID:              user.ID,
+		Email:           user.Email,
+		Name:            user.Name,
+		Status:          user.Status,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		StatusChangedAt: user.StatusChangedAt,
+		DeletedAt:       user.DeletedAt,
+	}
+	if v.admin || v.owns(user) {
+		response.PendingEmail = user.PendingEmail
+	}
+	if v.admin {
+		response.StatusReason = user.StatusReason
+		response.StatusChangedBy = user.StatusChangedBy
+	}
+	return response
+}
+
+// respondTokens responde o login e o refresh; tokens nunca devem ficar em cache
+func respondTokens(c *gin.Context, tokens *app.Tokens) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    int64(time.Until(tokens.ExpiresAt).Seconds()),
+		ExpiresAt:    tokens.ExpiresAt,
+		RefreshToken: tokens.RefreshToken,
+		SessionID:    tokens.SessionID,
+	})
+}
+
+// RestoreUser desfaz a exclusão lógica; o usuário volta suspenso
+func (h *UserHandlers) RestoreUser(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	expected, err := etag.ExpectedVersion(c.GetHeader(etag.HeaderIfMatch))
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.RestoreUserCommand{ID: id, ExpectedVersion: expected}
+	user, err := h.service.RestoreUser(c.Request.Context(), cmd)
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	h.respondUser(c, http.StatusOK, user)
+}
+
 func (h *UserHandlers) ActivateUser(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "User ID is required"})
+		problem.Respond(c, errMissingID)
 		return
 	}
 
-	cmd := app.ActivateUserCommand{ID: id}
+	expected, err := etag.ExpectedVersion(c.GetHeader(etag.HeaderIfMatch))
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.ActivateUserCommand{ID: id, ExpectedVersion: expected}
 	user, err := h.service.ActivateUser(c.Request.Context(), cmd)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		problem.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, UserResponse{
-		ID:     user.ID,
-		Email:  user.Email,
-		Name:   user.Name,
-		Status: user.Status,
-	})
+	h.respondUser(c, http.StatusOK, user)
 }
 
+// DeactivateUser suspende o usuário; o motivo é obrigatório
 func (h *UserHandlers) DeactivateUser(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "User ID is required"})
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	expected, err := etag.ExpectedVersion(c.GetHeader(etag.HeaderIfMatch))
+	if err != nil {
+		problem.Respond(c, err)
 		return
 	}
 
-	cmd := app.DeactivateUserCommand{ID: id}
+	var req StatusChangeRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.DeactivateUserCommand{ID: id, Reason: req.Reason, ExpectedVersion: expected}
 	user, err := h.service.DeactivateUser(c.Request.Context(), cmd)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		problem.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, UserResponse{
-		ID:     user.ID,
-		Email:  user.Email,
-		Name:   user.Name,
-		Status: user.Status,
-	})
+	h.respondUser(c, http.StatusOK, user)
+}
+
+// LockUser bloqueia o usuário por segurança; o motivo é obrigatório
+func (h *UserHandlers) LockUser(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.Respond(c, errMissingID)
+		return
+	}
+
+	expected, err := etag.ExpectedVersion(c.GetHeader(etag.HeaderIfMatch))
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	var req StatusChangeRequest
+	if err := validation.BindJSON(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	cmd := app.LockUserCommand{ID: id, Reason: req.Reason, ExpectedVersion: expected}
+	user, err := h.service.LockUser(c.Request.Context(), cmd)
+	if err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	h.respondUser(c, http.StatusOK, user)
 }
 
 func (h *UserHandlers) ListUsers(c *gin.Context) {
@@ -169,31 +641,73 @@ func (h *UserHandlers) ListUsers(c *gin.Context) {
 		limit = 10
 	}
 
+	var req ListUsersRequest
+	if err := validation.BindQuery(c, &req); err != nil {
+		problem.Respond(c, err)
+		return
+	}
+
+	if req.IncludeDeleted {
+		if err := h.authorizeAdmin(c); err != nil {
+			problem.Respond(c, err)
+			return
+		}
+	}
+
+	// A presença do parâmetro cursor (mesmo vazio) ativa a paginação por cursor
+	cursor, useCursor := c.GetQuery("cursor")
+
 	query := app.ListUsersQuery{
-		Page:  page,
-		Limit: limit,
+		Page:          page,
+		Limit:         limit,
+		Status:        req.Status,
+		EmailPrefix:   req.Email,
+		NameContains:  req.Name,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Sort:          req.Sort,
+		UseCursor:     useCursor,
+		Cursor:        cursor,
+
+		IncludeDeleted: req.IncludeDeleted,
 	}
 
-	users, err := h.service.ListUsers(c.Request.Context(), query)
+	list, err := h.service.ListUsers(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		problem.Respond(c, err)
 		return
 	}
 
-	userResponses := make([]UserResponse, len(users))
-	for i, user := range users {
-		userResponses[i] = UserResponse{
-			ID:     user.ID,
-			Email:  user.Email,
-			Name:   user.Name,
-			Status: user.Status,
+	v := h.viewerOf(c)
+	userResponses := make([]UserResponse, len(list.Users))
+	for i, user := range list.Users {
+		userResponses[i] = toUserResponse(user, v)
+	}
+
+	if useCursor {
+		if list.NextCursor != "" {
+			pagination.SetCursorLinkHeader(c, list.NextCursor)
 		}
+
+		c.JSON(http.StatusOK, UsersCursorResponse{
+			Users:      userResponses,
+			Limit:      limit,
+			NextCursor: list.NextCursor,
+			HasNext:    list.NextCursor != "",
+		})
+		return
 	}
 
+	meta := pagination.Page{Number: page, Limit: limit, Total: list.Total}
+	pagination.SetLinkHeader(c, meta)
+
 	c.JSON(http.StatusOK, UsersResponse{
-		Users: userResponses,
-		Page:  page,
-		Limit: limit,
-		Total: len(users),
+		Users:      userResponses,
+		Page:       page,
+		Limit:      limit,
+		Total:      list.Total,
+		TotalPages: meta.TotalPages(),
+		HasNext:    meta.HasNext(),
+		NextCursor: list.NextCursor,
 	})
 }
@@ -0,0 +1,368 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/app"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/infra"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/etag"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/outbox"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/pagination"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/patch"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/validation"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Credenciais aceitas pelo testKeys no header X-API-Key
+const (
+	writerKey = "writer"
+	adminKey  = "admin"
+)
+
+// testKeys resolve as API keys de teste para principais com os escopos correspondentes
+type testKeys map[string]*auth.Principal
+
+func (k testKeys) ResolveAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	principal, ok := k[key]
+	if !ok {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	return principal, nil
+}
+
+// scopeAuthorizer concede apenas o que os escopos do principal concedem, como o rbac sem papéis
+type scopeAuthorizer struct{}
+
+func (scopeAuthorizer) Can(ctx context.Context, principal *auth.Principal, permission string) (bool, error) {
+	return principal.HasScope(permission), nil
+}
+
+// testServer monta as rotas de usuários sobre um SQLite temporário
+type testServer struct {
+	router *gin.Engine
+	repo   *infra.GormUserRepository
+	keys   testKeys
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "users.db")), &gorm.Config{
+		Logger:         gormlogger.Default.LogMode(gormlogger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&infra.UserModel{}, &outbox.MessageModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	tx := transaction.NewManager(db)
+	repo := infra.NewGormUserRepository(db, tx)
+	cursors, err := pagination.NewCursorCodec("test-secret-with-at-least-32-bytes")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	service := app.NewUserService(repo, tx, cursors, nil, app.VerificationConfig{})
+	keys := testKeys{
+		writerKey: {Subject: "writer", Kind: auth.KindAPIKey, Scopes: []string{app.ScopeWrite}},
+		adminKey:  {Subject: "admin", Kind: auth.KindAPIKey, Scopes: []string{app.ScopeAdmin}},
+	}
+	policy := ratelimit.Policy{Name: "test", Requests: 1000, Window: time.Minute}
+	limits := ratelimit.Limits{Store: ratelimit.NewMemoryStore(), PerIP: policy, PerKey: policy, Auth: policy}
+	handlers := NewUserHandlers(service, nil, nil, keys, nil, scopeAuthorizer{}, limits, func(c *gin.Context) { c.Next() })
+
+	gin.SetMode(gin.TestMode)
+	validation.Setup()
+	router := gin.New()
+	handlers.RegisterRoutes(router.Group("/users"))
+
+	return &testServer{router: router, repo: repo, keys: keys}
+}
+
+// addUser grava um usuário direto no repositório
+func (s *testServer) addUser(t *testing.T, email string) *domain.User {
+	t.Helper()
+
+	user, err := domain.NewUser(email, "Ana")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.repo.Save(context.Background(), user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return user
+}
+
+// do envia a requisição; headers são pares nome, valor
+func (s *testServer) do(method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		request.Header.Set(headers[i], headers[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestConditionalRequests(t *testing.T) {
+	server := newTestServer(t)
+	user := server.addUser(t, "ana@example.com")
+	path := "/users/" + user.ID()
+
+	t.Run("GET returns the ETag of the version", func(t *testing.T) {
+		recorder := server.do(http.MethodGet, path, "")
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", recorder.Code)
+		}
+		if tag := recorder.Header().Get(etag.HeaderETag); tag != etag.Format(1) {
+			t.Errorf("Expected ETag %s, got %q", etag.Format(1), tag)
+		}
+	})
+
+	t.Run("If-None-Match with the current ETag returns 304", func(t *testing.T) {
+		recorder := server.do(http.MethodGet, path, "", etag.HeaderIfNoneMatch, etag.Format(1))
+
+		if recorder.Code != http.StatusNotModified || recorder.Body.Len() != 0 {
+			t.Errorf("Expected status 304 without body, got %d %q", recorder.Code, recorder.Body.String())
+		}
+		if tag := recorder.Header().Get(etag.HeaderETag); tag != etag.Format(1) {
+			t.Errorf("Expected ETag %s on 304, got %q", etag.Format(1), tag)
+		}
+	})
+
+	t.Run("If-Match with the current ETag updates and returns the new one", func(t *testing.T) {
+		recorder := server.do(http.MethodPut, path, `{"name": "Ana Maria"}`, "X-API-Key", writerKey, etag.HeaderIfMatch, etag.Format(1))
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if tag := recorder.Header().Get(etag.HeaderETag); tag != etag.Format(2) {
+			t.Errorf("Expected ETag %s, got %q", etag.Format(2), tag)
+		}
+	})
+
+	t.Run("If-Match with a stale ETag returns 412", func(t *testing.T) {
+		recorder := server.do(http.MethodPut, path, `{"name": "Stale"}`, "X-API-Key", writerKey, etag.HeaderIfMatch, etag.Format(1))
+
+		if recorder.Code != http.StatusPreconditionFailed {
+			t.Errorf("Expected status 412, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if current := server.do(http.MethodGet, path, ""); !strings.Contains(current.Body.String(), "Ana Maria") {
+			t.Errorf("Expected the stored name to be kept, got %s", current.Body.String())
+		}
+	})
+
+	t.Run("If-None-Match with an old ETag returns the user", func(t *testing.T) {
+		recorder := server.do(http.MethodGet, path, "", etag.HeaderIfNoneMatch, etag.Format(1))
+
+		if recorder.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", recorder.Code)
+		}
+	})
+}
+
+func TestPatchUser(t *testing.T) {
+	server := newTestServer(t)
+	user := server.addUser(t, "ana@example.com")
+	path := "/users/" + user.ID()
+
+	t.Run("Plain JSON is rejected with Accept-Patch", func(t *testing.T) {
+		recorder := server.do(http.MethodPatch, path, `{"name": "Ana Maria"}`, "X-API-Key", writerKey)
+
+		if recorder.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("Expected status 415, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if accept := recorder.Header().Get(patch.HeaderAcceptPatch); accept != patch.AcceptPatch() {
+			t.Errorf("Expected Accept-Patch %q, got %q", patch.AcceptPatch(), accept)
+		}
+	})
+
+	t.Run("Merge patch updates the name", func(t *testing.T) {
+		recorder := server.do(http.MethodPatch, path, `{"name": "Ana Maria"}`,
+			"X-API-Key", writerKey, "Content-Type", patch.ContentTypeMergePatch)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if !strings.Contains(recorder.Body.String(), `"name":"Ana Maria"`) {
+			t.Errorf("Expected the patched name, got %s", recorder.Body.String())
+		}
+		if accept := recorder.Header().Get(patch.HeaderAcceptPatch); accept != "" {
+			t.Errorf("Expected no Accept-Patch on success, got %q", accept)
+		}
+	})
+
+	t.Run("JSON Patch updates the name", func(t *testing.T) {
+		recorder := server.do(http.MethodPatch, path, `[{"op": "replace", "path": "/name", "value": "Ana Clara"}]`,
+			"X-API-Key", writerKey, "Content-Type", patch.ContentTypeJSONPatch)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if !strings.Contains(recorder.Body.String(), `"name":"Ana Clara"`) {
+			t.Errorf("Expected the patched name, got %s", recorder.Body.String())
+		}
+	})
+
+	t.Run("Immutable field is rejected", func(t *testing.T) {
+		recorder := server.do(http.MethodPatch, path, `{"email": "other@example.com"}`,
+			"X-API-Key", writerKey, "Content-Type", patch.ContentTypeMergePatch)
+
+		if recorder.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status 422, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+	})
+}
+
+func TestListIncludeDeleted(t *testing.T) {
+	server := newTestServer(t)
+	server.addUser(t, "ana@example.com")
+	deleted := server.addUser(t, "bia@example.com")
+	if err := deleted.Delete("admin"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := server.repo.Save(context.Background(), deleted); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("Deleted users are hidden by default", func(t *testing.T) {
+		recorder := server.do(http.MethodGet, "/users/", "")
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", recorder.Code)
+		}
+		if strings.Contains(recorder.Body.String(), "bia@example.com") {
+			t.Errorf("Expected the deleted user to be hidden, got %s", recorder.Body.String())
+		}
+	})
+
+	t.Run("Anonymous request is rejected", func(t *testing.T) {
+		recorder := server.do(http.MethodGet, "/users/?include_deleted=true", "")
+
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Write scope is not enough", func(t *testing.T) {
+		recorder := server.do(http.MethodGet, "/users/?include_deleted=true", "", "X-API-Key", writerKey)
+
+		if recorder.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Admin sees deleted users", func(t *testing.T) {
+		recorder := server.do(http.MethodGet, "/users/?include_deleted=true", "", "X-API-Key", adminKey)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if !strings.Contains(recorder.Body.String(), "bia@example.com") {
+			t.Errorf("Expected the deleted user to be listed, got %s", recorder.Body.String())
+		}
+	})
+}
+
+func TestStatusFieldsVisibility(t *testing.T) {
+	server := newTestServer(t)
+	user := server.addUser(t, "ana@example.com")
+	if err := user.Suspend("chargeback under review", "api_key:admin"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := server.repo.Save(context.Background(), user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("Public reads hide the reason and the actor", func(t *testing.T) {
+		for _, path := range []string{"/users/" + user.ID(), "/users/"} {
+			body := server.do(http.MethodGet, path, "").Body.String()
+
+			if strings.Contains(body, "status_reason") || strings.Contains(body, "status_changed_by") {
+				t.Errorf("Expected %s to hide the moderation fields, got %s", path, body)
+			}
+			if !strings.Contains(body, "status_changed_at") {
+				t.Errorf("Expected %s to keep status_changed_at, got %s", path, body)
+			}
+		}
+	})
+
+	t.Run("Write scope does not see them either", func(t *testing.T) {
+		body := server.do(http.MethodGet, "/users/"+user.ID(), "", "X-API-Key", writerKey).Body.String()
+
+		if strings.Contains(body, "status_reason") {
+			t.Errorf("Expected the reason to be hidden, got %s", body)
+		}
+	})
+
+	t.Run("Admin sees the reason and the actor", func(t *testing.T) {
+		for _, path := range []string{"/users/" + user.ID(), "/users/"} {
+			body := server.do(http.MethodGet, path, "", "X-API-Key", adminKey).Body.String()
+
+			if !strings.Contains(body, `"status_reason":"chargeback under review"`) || !strings.Contains(body, `"status_changed_by":"api_key:admin"`) {
+				t.Errorf("Expected %s to show the moderation fields, got %s", path, body)
+			}
+		}
+	})
+}
+
+func TestPendingEmailVisibility(t *testing.T) {
+	server := newTestServer(t)
+	user := server.addUser(t, "ana@example.com")
+	other := server.addUser(t, "bia@example.com")
+	if err := user.RequestEmailChange("ana.new@example.com", "user:"+user.ID()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := server.repo.Save(context.Background(), user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Sessões de usuário chegam como principal KindUser; aqui vêm pelo resolver de teste
+	server.keys["self"] = &auth.Principal{Subject: user.ID(), Kind: auth.KindUser, Scopes: []string{app.ScopeWrite}}
+	server.keys["other"] = &auth.Principal{Subject: other.ID(), Kind: auth.KindUser, Scopes: []string{app.ScopeWrite}}
+	path := "/users/" + user.ID()
+
+	tests := []struct {
+		name    string
+		headers []string
+		visible bool
+	}{
+		{name: "Anonymous", visible: false},
+		{name: "Another user", headers: []string{"X-API-Key", "other"}, visible: false},
+		{name: "Write scope", headers: []string{"X-API-Key", writerKey}, visible: false},
+		{name: "The user themself", headers: []string{"X-API-Key", "self"}, visible: true},
+		{name: "Admin", headers: []string{"X-API-Key", adminKey}, visible: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, target := range []string{path, "/users/"} {
+				body := server.do(http.MethodGet, target, "", tt.headers...).Body.String()
+
+				if visible := strings.Contains(body, "ana.new@example.com"); visible != tt.visible {
+					t.Errorf("Expected pending_email visible=%v on %s, got %s", tt.visible, target, body)
+				}
+			}
+		})
+	}
+}
@@ -2,24 +2,62 @@ package http
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/app"
 	"github.com/vynazevedo/go-modular-monolith/internal/shared/middleware"
 )
 
 func (h *UserHandlers) RegisterRoutes(router *gin.RouterGroup) {
-	// Rotas protegidas
-	protected := router.Group("/", middleware.ValidateAPIKey())
+	// Rotas protegidas; as permissões valem tanto como escopo da credencial quanto por papel (rbac)
+	protected := router.Group("/",
+		middleware.Authenticate(h.keys, h.tokens),
+		middleware.RateLimit(h.limits.Store, h.limits.PerKey, middleware.ByPrincipal),
+		h.idempotent,
+	)
 	{
-		protected.POST("/", h.CreateUser)
-		protected.PUT("/:id", h.UpdateUser)
-		protected.DELETE("/:id", h.DeleteUser)
-		protected.PUT("/:id/activate", h.ActivateUser)
-		protected.PUT("/:id/deactivate", h.DeactivateUser)
+		protected.POST("/", middleware.RequirePermission(h.authz, app.ScopeWrite), h.CreateUser)
+		protected.PUT("/:id", middleware.RequirePermission(h.authz, app.ScopeWrite), h.UpdateUser)
+		protected.PATCH("/:id", middleware.RequirePermission(h.authz, app.ScopeWrite), h.PatchUser)
+		protected.DELETE("/:id", middleware.RequirePermission(h.authz, app.ScopeAdmin), h.DeleteUser)
+		protected.POST("/:id/restore", middleware.RequirePermission(h.authz, app.ScopeAdmin), h.RestoreUser)
+		protected.PUT("/:id/activate", middleware.RequirePermission(h.authz, app.ScopeAdmin), h.ActivateUser)
+		protected.PUT("/:id/deactivate", middleware.RequirePermission(h.authz, app.ScopeAdmin), h.DeactivateUser)
+		protected.PUT("/:id/lock", middleware.RequirePermission(h.authz, app.ScopeAdmin), h.LockUser)
+		// O próprio usuário ou users:admin; a regra fica nos casos de uso
+		protected.PUT("/:id/password", h.ChangePassword)
+		protected.POST("/:id/email-change", h.RequestEmailChange)
+		protected.DELETE("/:id/email-change", h.CancelEmailChange)
+		protected.GET("/:id/sessions", h.ListSessions)
+		protected.DELETE("/:id/sessions", h.RevokeAllSessions)
+		protected.DELETE("/:id/sessions/:session_id", h.RevokeSession)
 	}
 
 	// Rotas públicas (apenas leitura)
-	public := router.Group("/", middleware.OptionalAPIKey())
+	public := router.Group("/", middleware.OptionalAuthenticate(h.keys, h.tokens), middleware.RateLimit(h.limits.Store, h.limits.PerKey, middleware.ByPrincipal))
 	{
 		public.GET("/:id", h.GetUser)
 		public.GET("/", h.ListUsers)
 	}
+
+	// Confirmação de e-mail: pública (o usuário pendente não consegue fazer login),
+	// com o limite por IP das rotas de autenticação
+	verification := router.Group("/verify-email", middleware.RateLimit(h.limits.Store, h.limits.Auth, middleware.ByClientIP))
+	{
+		verification.POST("", h.VerifyEmail)
+		verification.POST("/resend", h.ResendVerification)
+	}
+
+	// Confirmação da troca de e-mail: o link chega ao novo endereço, talvez sem sessão aberta
+	emailChange := router.Group("/email-change", middleware.RateLimit(h.limits.Store, h.limits.Auth, middleware.ByClientIP))
+	{
+		emailChange.POST("/confirm", h.ConfirmEmailChange)
+	}
+}
+
+// RegisterAuthRoutes registra login, refresh e logout, que são públicos por definição:
+// a credencial vai no corpo. O limite por IP é mais estrito que o do restante da API.
+func (h *UserHandlers) RegisterAuthRoutes(router *gin.RouterGroup) {
+	router.Use(middleware.RateLimit(h.limits.Store, h.limits.Auth, middleware.ByClientIP))
+	router.POST("/login", h.Login)
+	router.POST("/refresh", h.Refresh)
+	router.POST("/logout", h.Logout)
 }
@@ -0,0 +1,63 @@
+package infra
+
+import (
+	"strings"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sortColumns traduz os campos de ordenação do domínio para colunas; nada vindo do cliente chega cru ao SQL
+var sortColumns = map[domain.SortField]string{
+	domain.SortByCreatedAt: "created_at",
+	domain.SortByName:      "name",
+	domain.SortByEmail:     "email",
+}
+
+// likeEscaper escapa os curingas do LIKE. "!" funciona como ESCAPE tanto no MySQL quanto no SQLite.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// applyFilters aplica os filtros da especificação à consulta
+func applyFilters(query *gorm.DB, criteria domain.UserCriteria) *gorm.DB {
+	if !criteria.IncludeDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+	if criteria.Status != "" {
+		query = query.Where("status = ?", criteria.Status.String())
+	}
+	if criteria.EmailPrefix != "" {
+		// Prefixo aproveita o índice idx_users_email
+		query = query.Where("email LIKE ? ESCAPE '!'", likeEscaper.Replace(criteria.EmailPrefix)+"%")
+	}
+	if criteria.NameContains != "" {
+		query = query.Where("name LIKE ? ESCAPE '!'", "%"+likeEscaper.Replace(criteria.NameContains)+"%")
+	}
+	if criteria.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", criteria.CreatedAfter.Unix())
+	}
+	if criteria.CreatedBefore != nil {
+		query = query.Where("created_at < ?", criteria.CreatedBefore.Unix())
+	}
+	return query
+}
+
+// applySort ordena pela especificação (ou pela ordenação padrão) e desempata pelo id
+func applySort(query *gorm.DB, criteria domain.UserCriteria) *gorm.DB {
+	sort := criteria.Sort
+	if len(sort) == 0 {
+		sort = domain.DefaultSort
+	}
+
+	columns := make([]clause.OrderByColumn, 0, len(sort)+1)
+	for _, order := range sort {
+		column, ok := sortColumns[order.Field]
+		if !ok {
+			continue
+		}
+		columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: order.Desc})
+	}
+	columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: "id"}})
+
+	return query.Order(clause.OrderBy{Columns: columns})
+}
@@ -6,104 +6,241 @@ import (
 	"time"
 
 	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/outbox"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
 	"gorm.io/gorm"
 )
 
 type UserModel struct {
-	ID        string `gorm:"primaryKey"`
-	Email     string `gorm:"size:255;uniqueIndex"`
-	Name      string
-	Status    string
-	CreatedAt int64
+	ID     string `gorm:"primaryKey"`
+	Email  string `gorm:"size:255;uniqueIndex"`
+	Name   string
+	Status string
+	// StatusReason, StatusChangedBy e StatusChangedAt guardam a última transição de status
+	StatusReason    string `gorm:"size:255"`
+	StatusChangedBy string `gorm:"size:100"`
+	StatusChangedAt *int64
+	CreatedAt       int64
+	PasswordHash    string
+	FailedLogins    int
+	LockedUntil     *int64
+	LastLoginAt     *int64
+	// EmailVerifiedAt é nil enquanto o e-mail atual não foi confirmado
+	EmailVerifiedAt *int64
+	// PendingEmail aguarda confirmação desde EmailChangeRequestedAt; sem índice único, a
+	// unicidade é conferida na confirmação
+	PendingEmail           string `gorm:"size:255"`
+	EmailChangeRequestedAt *int64
+	DeletedAt              *int64 `gorm:"index"`
+	Version                int64  `gorm:"not null;default:1"`
 }
 
 func (UserModel) TableName() string {
 	return "users"
 }
 
+// GormUserRepository participa da transação presente no contexto (transaction.Manager)
 type GormUserRepository struct {
 	db *gorm.DB
+	tx transaction.Manager
 }
 
-func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
-	return &GormUserRepository{db: db}
+func NewGormUserRepository(db *gorm.DB, tx transaction.Manager) *GormUserRepository {
+	return &GormUserRepository{db: db, tx: tx}
 }
 
+// Save insere o usuário novo (versão zero) ou atualiza o existente somente se a versão
+// gravada ainda for a carregada; caso contrário retorna ErrVersionConflict. Na atualização,
+// as credenciais ficam de fora: são gravadas só por SaveCredentials.
 func (r *GormUserRepository) Save(ctx context.Context, user *domain.User) error {
+	credentials := user.Credentials()
+	change := user.LastStatusChange()
 	model := UserModel{
-		ID:        user.ID(),
-		Email:     user.Email(),
-		Name:      user.Name(),
-		Status:    user.Status().String(),
-		CreatedAt: user.CreatedAt().Unix(),
+		ID:                     user.ID(),
+		Email:                  user.Email(),
+		Name:                   user.Name(),
+		Status:                 user.Status().String(),
+		StatusReason:           change.Reason,
+		StatusChangedBy:        change.Actor,
+		CreatedAt:              user.CreatedAt().Unix(),
+		PasswordHash:           credentials.PasswordHash,
+		FailedLogins:           credentials.FailedLogins,
+		LockedUntil:            toUnix(credentials.LockedUntil),
+		LastLoginAt:            toUnix(credentials.LastLoginAt),
+		EmailVerifiedAt:        toUnix(user.EmailVerifiedAt()),
+		PendingEmail:           user.PendingEmail(),
+		EmailChangeRequestedAt: toUnix(user.EmailChangeRequestedAt()),
+		DeletedAt:              toUnix(user.DeletedAt()),
+		Version:                user.Version() + 1,
 	}
+	if !change.At.IsZero() {
+		model.StatusChangedAt = toUnix(&change.At)
+	}
+
+	return r.tx.Do(ctx, func(ctx context.Context) error {
+		conn := transaction.DB(ctx, r.db)
+		if err := writeUser(conn, model, user.Version()); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return domain.ErrEmailAlreadyExists.Wrap(err)
+			}
+			return err
+		}
 
-	result := r.db.WithContext(ctx).Save(&model)
-	return result.Error
+		user.RestoreVersion(model.Version)
+		return outbox.Append(ctx, conn, user.PullEvents()...)
+	})
 }
 
-func (r *GormUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
-	var model UserModel
-	result := r.db.WithContext(ctx).First(&model, "id = ?", id)
+func writeUser(conn *gorm.DB, model UserModel, current int64) error {
+	if current == 0 {
+		return conn.Create(&model).Error
+	}
+
+	result := conn.Model(&UserModel{}).
+		Where("id = ? AND version = ?", model.ID, current).
+		Updates(map[string]any{
+			"email":                     model.Email,
+			"name":                      model.Name,
+			"status":                    model.Status,
+			"status_reason":             model.StatusReason,
+			"status_changed_by":         model.StatusChangedBy,
+			"status_changed_at":         model.StatusChangedAt,
+			"email_verified_at":         model.EmailVerifiedAt,
+			"pending_email":             model.PendingEmail,
+			"email_change_requested_at": model.EmailChangeRequestedAt,
+			"deleted_at":                model.DeletedAt,
+			"version":                   model.Version,
+		})
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return staleOrMissing(conn, model.ID)
+	}
+	return nil
+}
+
+func (r *GormUserRepository) SaveCredentials(ctx context.Context, user *domain.User) error {
+	credentials := user.Credentials()
+
+	return r.tx.Do(ctx, func(ctx context.Context) error {
+		conn := transaction.DB(ctx, r.db)
+		err := conn.Model(&UserModel{}).
+			Where("id = ? AND deleted_at IS NULL", user.ID()).
+			Updates(map[string]any{
+				"password_hash": credentials.PasswordHash,
+				"failed_logins": credentials.FailedLogins,
+				"locked_until":  toUnix(credentials.LockedUntil),
+				"last_login_at": toUnix(credentials.LastLoginAt),
+			}).Error
+		if err != nil {
+			return err
 		}
-		return nil, result.Error
+
+		return outbox.Append(ctx, conn, user.PullEvents()...)
+	})
+}
+
+// staleOrMissing explica por que a operação condicionada à versão não afetou nenhuma linha
+func staleOrMissing(conn *gorm.DB, id string) error {
+	var count int64
+	if err := conn.Model(&UserModel{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrUserNotFound
 	}
+	return domain.ErrVersionConflict
+}
 
-	createdAt := time.Unix(model.CreatedAt, 0)
+func (r *GormUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.findOne(ctx, "id = ? AND deleted_at IS NULL", id)
+}
 
-	return domain.ReconstructUser(
-		model.ID,
-		model.Email,
-		model.Name,
-		model.Status,
-		createdAt,
-	)
+func (r *GormUserRepository) FindDeletedByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.findOne(ctx, "id = ? AND deleted_at IS NOT NULL", id)
 }
 
 func (r *GormUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.findOne(ctx, "email = ? AND deleted_at IS NULL", email)
+}
+
+func (r *GormUserRepository) findOne(ctx context.Context, query string, args ...any) (*domain.User, error) {
 	var model UserModel
-	result := r.db.WithContext(ctx).First(&model, "email = ?", email)
+	result := transaction.DB(ctx, r.db).Where(query, args...).First(&model)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, result.Error
 	}
 
-	createdAt := time.Unix(model.CreatedAt, 0)
-
-	return domain.ReconstructUser(
-		model.ID,
-		model.Email,
-		model.Name,
-		model.Status,
-		createdAt,
-	)
+	return toDomainUser(model)
 }
 
-func (r *GormUserRepository) FindAll(ctx context.Context, page, limit int) ([]*domain.User, error) {
+func (r *GormUserRepository) FindAll(ctx context.Context, criteria domain.UserCriteria, page, limit int) ([]*domain.User, error) {
 	var models []UserModel
 	offset := (page - 1) * limit
 
-	result := r.db.WithContext(ctx).Offset(offset).Limit(limit).Find(&models)
+	query := applySort(applyFilters(transaction.DB(ctx, r.db), criteria), criteria)
+	result := query.Offset(offset).Limit(limit).Find(&models)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
+	return toDomainUsers(models)
+}
+
+// FindAfter usa o índice idx_users_created_at (que no InnoDB inclui a chave primária)
+// para continuar a partir de (created_at, id) sem varrer as linhas anteriores
+func (r *GormUserRepository) FindAfter(ctx context.Context, criteria domain.UserCriteria, after *domain.UserCursor, limit int) ([]*domain.User, error) {
+	criteria.Sort = domain.DefaultSort
+	query := applySort(applyFilters(transaction.DB(ctx, r.db), criteria), criteria).Limit(limit)
+	if after != nil {
+		createdAt := after.CreatedAt.Unix()
+		query = query.Where("created_at > ? OR (created_at = ? AND id > ?)", createdAt, createdAt, after.ID)
+	}
+
+	var models []UserModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return toDomainUsers(models)
+}
+
+func toDomainUser(model UserModel) (*domain.User, error) {
+	user, err := domain.ReconstructUser(
+		model.ID,
+		model.Email,
+		model.Name,
+		model.Status,
+		time.Unix(model.CreatedAt, 0),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	user.RestoreCredentials(domain.Credentials{
+		PasswordHash: model.PasswordHash,
+		FailedLogins: model.FailedLogins,
+		LockedUntil:  fromUnix(model.LockedUntil),
+		LastLoginAt:  fromUnix(model.LastLoginAt),
+	})
+	if changedAt := fromUnix(model.StatusChangedAt); changedAt != nil {
+		user.RestoreStatusChange(domain.StatusChange{Reason: model.StatusReason, Actor: model.StatusChangedBy, At: *changedAt})
+	}
+	user.RestoreEmailVerifiedAt(fromUnix(model.EmailVerifiedAt))
+	user.RestorePendingEmail(model.PendingEmail, fromUnix(model.EmailChangeRequestedAt))
+	user.RestoreDeletedAt(fromUnix(model.DeletedAt))
+	user.RestoreVersion(model.Version)
+	return user, nil
+}
+
+func toDomainUsers(models []UserModel) ([]*domain.User, error) {
 	users := make([]*domain.User, len(models))
 	for i, model := range models {
-		createdAt := time.Unix(model.CreatedAt, 0)
-
-		user, err := domain.ReconstructUser(
-			model.ID,
-			model.Email,
-			model.Name,
-			model.Status,
-			createdAt,
-		)
+		user, err := toDomainUser(model)
 		if err != nil {
 			return nil, err
 		}
@@ -113,15 +250,45 @@ func (r *GormUserRepository) FindAll(ctx context.Context, page, limit int) ([]*d
 	return users, nil
 }
 
-func (r *GormUserRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Delete(&UserModel{}, "id = ?", id)
-	if result.Error != nil {
-		return result.Error
+func (r *GormUserRepository) Count(ctx context.Context, criteria domain.UserCriteria) (int64, error) {
+	var total int64
+	if err := applyFilters(transaction.DB(ctx, r.db).Model(&UserModel{}), criteria).Count(&total).Error; err != nil {
+		return 0, err
 	}
+	return total, nil
+}
 
-	if result.RowsAffected == 0 {
-		return errors.New("user not found")
+// PurgeDeleted apaga em lote; as linhas de outras tabelas que referenciam o usuário
+// (sessões, papéis) são removidas pelas chaves estrangeiras com ON DELETE CASCADE
+func (r *GormUserRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var ids []string
+	conn := transaction.DB(ctx, r.db)
+	err := conn.Model(&UserModel{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before.Unix()).
+		Order("deleted_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
 	}
 
-	return nil
+	// Repete a condição: um usuário restaurado entre as duas consultas não é apagado
+	result := conn.Where("id IN ? AND deleted_at IS NOT NULL AND deleted_at < ?", ids, before.Unix()).Delete(&UserModel{})
+	return result.RowsAffected, result.Error
+}
+
+func toUnix(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	unix := t.Unix()
+	return &unix
+}
+
+func fromUnix(unix *int64) *time.Time {
+	if unix == nil {
+		return nil
+	}
+	t := time.Unix(*unix, 0)
+	return &t
 }
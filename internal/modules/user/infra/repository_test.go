@@ -0,0 +1,443 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/outbox"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func newTestRepository(t *testing.T) (*GormUserRepository, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "users.db")), &gorm.Config{
+		Logger:         gormlogger.Default.LogMode(gormlogger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&UserModel{}, &outbox.MessageModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	return NewGormUserRepository(db, transaction.NewManager(db)), db
+}
+
+// plainHasher guarda a senha com um prefixo; suficiente para testar a persistência
+type plainHasher struct{}
+
+func (plainHasher) Hash(password string) (string, error) { return "plain:" + password, nil }
+
+func (plainHasher) Verify(password, encoded string) (bool, error) {
+	return encoded == "plain:"+password, nil
+}
+
+func (plainHasher) NeedsRehash(encoded string) bool { return false }
+
+func ids(users []*domain.User) []string {
+	result := make([]string, len(users))
+	for i, user := range users {
+		result[i] = user.ID()
+	}
+	return result
+}
+
+func TestFindAfter(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	// Inseridos fora de ordem; c e b compartilham o mesmo created_at
+	for _, model := range []UserModel{
+		{ID: "c", Email: "c@example.com", Name: "C", Status: "active", CreatedAt: 200},
+		{ID: "a", Email: "a@example.com", Name: "A", Status: "active", CreatedAt: 100},
+		{ID: "d", Email: "d@example.com", Name: "D", Status: "active", CreatedAt: 300},
+		{ID: "b", Email: "b@example.com", Name: "B", Status: "active", CreatedAt: 200},
+	} {
+		if err := db.Create(&model).Error; err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+
+	t.Run("Starts from the beginning", func(t *testing.T) {
+		users, err := repo.FindAfter(ctx, domain.UserCriteria{}, nil, 3)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := fmt.Sprint(ids(users)); got != "[a b c]" {
+			t.Errorf("Expected [a b c], got %s", got)
+		}
+	})
+
+	t.Run("Continues after ties on created_at", func(t *testing.T) {
+		after := &domain.UserCursor{CreatedAt: time.Unix(200, 0), ID: "b"}
+		users, err := repo.FindAfter(ctx, domain.UserCriteria{}, after, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := fmt.Sprint(ids(users)); got != "[c d]" {
+			t.Errorf("Expected [c d], got %s", got)
+		}
+	})
+
+	t.Run("Offset pagination uses the same order", func(t *testing.T) {
+		users, err := repo.FindAll(ctx, domain.UserCriteria{}, 2, 2)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := fmt.Sprint(ids(users)); got != "[c d]" {
+			t.Errorf("Expected [c d], got %s", got)
+		}
+	})
+}
+
+func TestCriteria(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	for _, model := range []UserModel{
+		{ID: "1", Email: "ana@corp.com", Name: "Ana Souza", Status: "active", CreatedAt: 100},
+		{ID: "2", Email: "bruno@corp.com", Name: "Bruno Lima", Status: "suspended", CreatedAt: 200},
+		{ID: "3", Email: "carla@other.com", Name: "Carla Souza", Status: "active", CreatedAt: 300},
+		{ID: "4", Email: "an_a@corp.com", Name: "100% Ana", Status: "active", CreatedAt: 400},
+	} {
+		if err := db.Create(&model).Error; err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+
+	after, before := time.Unix(150, 0), time.Unix(350, 0)
+	byName, _ := domain.ParseSort("-name")
+
+	tests := []struct {
+		name     string
+		criteria domain.UserCriteria
+		want     string
+	}{
+		{name: "Status", criteria: domain.UserCriteria{Status: domain.StatusActive}, want: "[1 3 4]"},
+		{name: "Email prefix", criteria: domain.UserCriteria{EmailPrefix: "b"}, want: "[2]"},
+		{name: "Wildcards are literal", criteria: domain.UserCriteria{EmailPrefix: "an_"}, want: "[4]"},
+		{name: "Name contains", criteria: domain.UserCriteria{NameContains: "Souza"}, want: "[1 3]"},
+		{name: "Percent is literal", criteria: domain.UserCriteria{NameContains: "0%"}, want: "[4]"},
+		{name: "Created range", criteria: domain.UserCriteria{CreatedAfter: &after, CreatedBefore: &before}, want: "[2 3]"},
+		{name: "Combined", criteria: domain.UserCriteria{Status: domain.StatusActive, NameContains: "Souza", Sort: byName}, want: "[3 1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := repo.FindAll(ctx, tt.criteria, 1, 10)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := fmt.Sprint(ids(users)); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+
+			total, err := repo.Count(ctx, tt.criteria)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if int(total) != len(users) {
+				t.Errorf("Expected count %d, got %d", len(users), total)
+			}
+		})
+	}
+
+	t.Run("Keyset respects filters", func(t *testing.T) {
+		cursor := &domain.UserCursor{CreatedAt: time.Unix(100, 0), ID: "1"}
+		users, err := repo.FindAfter(ctx, domain.UserCriteria{Status: domain.StatusActive}, cursor, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := fmt.Sprint(ids(users)); got != "[3 4]" {
+			t.Errorf("Expected [3 4], got %s", got)
+		}
+	})
+}
+
+func TestSaveDuplicateEmail(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	first, _ := domain.NewUser("dup@example.com", "First")
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	second, _ := domain.NewUser("dup@example.com", "Second")
+	if err := repo.Save(ctx, second); !errors.Is(err, domain.ErrEmailAlreadyExists) {
+		t.Errorf("Expected ErrEmailAlreadyExists, got %v", err)
+	}
+}
+
+func TestSaveVersion(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	user, _ := domain.NewUser("version@example.com", "Version")
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.Version() != 1 {
+		t.Errorf("Expected version 1 after insert, got %d", user.Version())
+	}
+
+	t.Run("Increments the version on update", func(t *testing.T) {
+		loaded, _ := repo.FindByID(ctx, user.ID())
+		_ = loaded.UpdateName("Updated")
+		if err := repo.Save(ctx, loaded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		reloaded, _ := repo.FindByID(ctx, user.ID())
+		if reloaded.Version() != 2 || reloaded.Name() != "Updated" {
+			t.Errorf("Expected version 2 with the new name, got %d %q", reloaded.Version(), reloaded.Name())
+		}
+	})
+
+	t.Run("Rejects a stale update", func(t *testing.T) {
+		_ = user.UpdateName("Stale")
+		if err := repo.Save(ctx, user); !errors.Is(err, domain.ErrVersionConflict) {
+			t.Errorf("Expected ErrVersionConflict, got %v", err)
+		}
+
+		reloaded, _ := repo.FindByID(ctx, user.ID())
+		if reloaded.Name() != "Updated" {
+			t.Errorf("Expected the stored name to be kept, got %q", reloaded.Name())
+		}
+	})
+
+	t.Run("Credentials are saved without a new version", func(t *testing.T) {
+		editing, _ := repo.FindByID(ctx, user.ID())
+		login, _ := repo.FindByID(ctx, user.ID())
+		if err := login.SetPassword("correct horse battery", plainHasher{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		_ = login.VerifyPassword("wrong password", plainHasher{}, domain.LockoutPolicy{MaxFailedAttempts: 5}, time.Now())
+		if err := repo.SaveCredentials(ctx, login); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Quem carregou o usuário antes do login continua podendo salvar
+		_ = editing.UpdateName("Edited")
+		if err := repo.Save(ctx, editing); err != nil {
+			t.Fatalf("Expected the edit to keep its version, got %v", err)
+		}
+
+		reloaded, _ := repo.FindByID(ctx, user.ID())
+		if reloaded.Version() != 3 || reloaded.Credentials().FailedLogins != 1 {
+			t.Errorf("Expected version 3 keeping the failed login, got %d with %d failures", reloaded.Version(), reloaded.Credentials().FailedLogins)
+		}
+	})
+
+	t.Run("Rejects a stale save of a deleted user", func(t *testing.T) {
+		user.Delete("admin")
+		if err := repo.Save(ctx, user); !errors.Is(err, domain.ErrVersionConflict) {
+			t.Errorf("Expected ErrVersionConflict, got %v", err)
+		}
+	})
+}
+
+func TestSaveStatusChange(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	user, _ := domain.NewUser("status@example.com", "Status")
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reloaded, _ := repo.FindByID(ctx, user.ID())
+	if change := reloaded.LastStatusChange(); !change.At.IsZero() {
+		t.Errorf("Expected no status change for a new user, got %+v", change)
+	}
+
+	_ = reloaded.VerifyEmail("user:" + user.ID())
+	_ = reloaded.Lock("leaked credentials", "user:admin-1")
+	if err := repo.Save(ctx, reloaded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reloaded, _ = repo.FindByID(ctx, user.ID())
+	change := reloaded.LastStatusChange()
+	if reloaded.Status() != domain.StatusLocked || change.Reason != "leaked credentials" || change.Actor != "user:admin-1" || change.At.IsZero() {
+		t.Errorf("Expected the locked status with its change, got %s %+v", reloaded.Status(), change)
+	}
+	if reloaded.EmailVerifiedAt() == nil {
+		t.Error("Expected the email verification to be persisted")
+	}
+}
+
+func TestSavePendingEmail(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	user, _ := domain.NewUser("current@example.com", "Current")
+	_ = user.RequestEmailChange("new@example.com", "user:"+user.ID())
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reloaded, _ := repo.FindByID(ctx, user.ID())
+	if reloaded.PendingEmail() != "new@example.com" || reloaded.EmailChangeRequestedAt() == nil {
+		t.Errorf("Expected the pending email to be persisted, got %q", reloaded.PendingEmail())
+	}
+	if _, err := repo.FindByEmail(ctx, "new@example.com"); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("Expected the pending email not to be found as the user email, got %v", err)
+	}
+
+	_ = reloaded.ConfirmEmailChange("user:" + user.ID())
+	if err := repo.Save(ctx, reloaded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reloaded, _ = repo.FindByEmail(ctx, "new@example.com")
+	if reloaded == nil || reloaded.PendingEmail() != "" || reloaded.EmailChangeRequestedAt() != nil {
+		t.Errorf("Expected the email to be swapped without a pending change, got %+v", reloaded)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	user, _ := domain.NewUser("deleted@example.com", "Deleted")
+	other, _ := domain.NewUser("other@example.com", "Other")
+	for _, u := range []*domain.User{user, other} {
+		if err := repo.Save(ctx, u); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	user.Delete("admin")
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("Default queries skip deleted users", func(t *testing.T) {
+		if _, err := repo.FindByID(ctx, user.ID()); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound by id, got %v", err)
+		}
+		if _, err := repo.FindByEmail(ctx, user.Email()); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound by email, got %v", err)
+		}
+		if total, _ := repo.Count(ctx, domain.UserCriteria{}); total != 1 {
+			t.Errorf("Expected 1 listed user, got %d", total)
+		}
+		if total, _ := repo.Count(ctx, domain.UserCriteria{IncludeDeleted: true}); total != 2 {
+			t.Errorf("Expected 2 users including deleted, got %d", total)
+		}
+	})
+
+	t.Run("Finds the deleted user for restore", func(t *testing.T) {
+		found, err := repo.FindDeletedByID(ctx, user.ID())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if found.Status() != domain.StatusDeleted || found.DeletedAt() == nil {
+			t.Errorf("Expected deleted user, got status %s", found.Status())
+		}
+		if _, err := repo.FindDeletedByID(ctx, other.ID()); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("Expected ErrUserNotFound for active user, got %v", err)
+		}
+	})
+
+	t.Run("Purges only users deleted before the cutoff", func(t *testing.T) {
+		purged, err := repo.PurgeDeleted(ctx, user.DeletedAt().Add(-time.Hour), 10)
+		if err != nil || purged != 0 {
+			t.Errorf("Expected nothing purged before retention, got %d, %v", purged, err)
+		}
+
+		purged, err = repo.PurgeDeleted(ctx, time.Now().Add(time.Hour), 10)
+		if err != nil || purged != 1 {
+			t.Errorf("Expected 1 purged user, got %d, %v", purged, err)
+		}
+		if total, _ := repo.Count(ctx, domain.UserCriteria{IncludeDeleted: true}); total != 1 {
+			t.Errorf("Expected only the active user left, got %d", total)
+		}
+	})
+}
+
+func TestSessionRepository(t *testing.T) {
+	_, db := newTestRepository(t)
+	if err := db.AutoMigrate(&SessionModel{}, &RefreshTokenModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	repo := NewGormSessionRepository(db, transaction.NewManager(db))
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+
+	session, first, _ := domain.NewSession("user-1", domain.SessionMetadata{UserAgent: "curl", IP: "10.0.0.1"}, now)
+	if err := repo.Save(ctx, session); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := session.Refresh(first, domain.SessionPolicy{}, domain.SessionMetadata{}, now.Add(time.Minute))
+	if err := repo.Save(ctx, session); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("Finds the session by current and rotated tokens", func(t *testing.T) {
+		for _, token := range []string{first, second} {
+			found, err := repo.FindByTokenHash(ctx, auth.HashSecret(token))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if found.ID() != session.ID() || found.TokenHash() != auth.HashSecret(second) {
+				t.Errorf("Expected session %s with the current hash, got %s", session.ID(), found.ID())
+			}
+		}
+
+		if _, err := repo.FindByTokenHash(ctx, auth.HashSecret("unknown")); !errors.Is(err, domain.ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Rotates only from the current token", func(t *testing.T) {
+		// Duas requisições leram a sessão com o mesmo token vigente
+		winner, _ := repo.FindByTokenHash(ctx, auth.HashSecret(second))
+		loser, _ := repo.FindByTokenHash(ctx, auth.HashSecret(second))
+		third, _ := winner.Refresh(second, domain.SessionPolicy{}, domain.SessionMetadata{}, now.Add(2*time.Minute))
+		_, _ = loser.Refresh(second, domain.SessionPolicy{}, domain.SessionMetadata{}, now.Add(2*time.Minute))
+
+		if err := repo.Rotate(ctx, winner, auth.HashSecret(second)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := repo.Rotate(ctx, loser, auth.HashSecret(second)); !errors.Is(err, domain.ErrRefreshTokenReused) {
+			t.Fatalf("Expected ErrRefreshTokenReused, got %v", err)
+		}
+
+		found, err := repo.FindByTokenHash(ctx, auth.HashSecret(third))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if found.TokenHash() != auth.HashSecret(third) {
+			t.Errorf("Expected the winning rotation to be kept")
+		}
+	})
+
+	t.Run("Lists only sessions not revoked", func(t *testing.T) {
+		other, _, _ := domain.NewSession("user-1", domain.SessionMetadata{}, now)
+		other.Revoke(domain.RevokeReasonLogout, now)
+		if err := repo.Save(ctx, other); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		sessions, err := repo.FindByUser(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(sessions) != 1 || sessions[0].ID() != session.ID() {
+			t.Errorf("Expected only session %s, got %d sessions", session.ID(), len(sessions))
+		}
+	})
+}
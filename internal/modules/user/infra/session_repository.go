@@ -0,0 +1,158 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionModel struct {
+	ID           string `gorm:"primaryKey"`
+	UserID       string `gorm:"size:36;index"`
+	UserAgent    string
+	IP           string
+	TokenHash    string
+	CreatedAt    int64
+	LastUsedAt   int64
+	RevokedAt    *int64
+	RevokeReason string
+}
+
+func (SessionModel) TableName() string {
+	return "sessions"
+}
+
+// RefreshTokenModel guarda o hash de cada refresh token já emitido para a sessão (a família)
+type RefreshTokenModel struct {
+	TokenHash string `gorm:"primaryKey;size:64"`
+	SessionID string `gorm:"size:36;index"`
+	CreatedAt int64
+}
+
+func (RefreshTokenModel) TableName() string {
+	return "refresh_tokens"
+}
+
+type GormSessionRepository struct {
+	db *gorm.DB
+	tx transaction.Manager
+}
+
+func NewGormSessionRepository(db *gorm.DB, tx transaction.Manager) *GormSessionRepository {
+	return &GormSessionRepository{db: db, tx: tx}
+}
+
+func (r *GormSessionRepository) Save(ctx context.Context, session *domain.Session) error {
+	metadata := session.Metadata()
+	model := SessionModel{
+		ID:           session.ID(),
+		UserID:       session.UserID(),
+		UserAgent:    metadata.UserAgent,
+		IP:           metadata.IP,
+		TokenHash:    session.TokenHash(),
+		CreatedAt:    session.CreatedAt().Unix(),
+		LastUsedAt:   session.LastUsedAt().Unix(),
+		RevokedAt:    toUnix(session.RevokedAt()),
+		RevokeReason: session.RevokeReason(),
+	}
+	token := RefreshTokenModel{
+		TokenHash: session.TokenHash(),
+		SessionID: session.ID(),
+		CreatedAt: session.LastUsedAt().Unix(),
+	}
+
+	return r.tx.Do(ctx, func(ctx context.Context) error {
+		conn := transaction.DB(ctx, r.db)
+		if err := conn.Save(&model).Error; err != nil {
+			return err
+		}
+		return conn.Clauses(clause.OnConflict{DoNothing: true}).Create(&token).Error
+	})
+}
+
+func (r *GormSessionRepository) Rotate(ctx context.Context, session *domain.Session, previousHash string) error {
+	metadata := session.Metadata()
+	token := RefreshTokenModel{
+		TokenHash: session.TokenHash(),
+		SessionID: session.ID(),
+		CreatedAt: session.LastUsedAt().Unix(),
+	}
+
+	return r.tx.Do(ctx, func(ctx context.Context) error {
+		conn := transaction.DB(ctx, r.db)
+		result := conn.Model(&SessionModel{}).
+			Where("id = ? AND token_hash = ? AND revoked_at IS NULL", session.ID(), previousHash).
+			Updates(map[string]any{
+				"token_hash":   session.TokenHash(),
+				"user_agent":   metadata.UserAgent,
+				"ip":           metadata.IP,
+				"last_used_at": session.LastUsedAt().Unix(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrRefreshTokenReused
+		}
+		return conn.Create(&token).Error
+	})
+}
+
+func (r *GormSessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	var model SessionModel
+	if err := transaction.DB(ctx, r.db).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return toDomainSession(model), nil
+}
+
+func (r *GormSessionRepository) FindByTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	var token RefreshTokenModel
+	if err := transaction.DB(ctx, r.db).First(&token, "token_hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return r.FindByID(ctx, token.SessionID)
+}
+
+func (r *GormSessionRepository) FindByUser(ctx context.Context, userID string) ([]*domain.Session, error) {
+	var models []SessionModel
+	err := transaction.DB(ctx, r.db).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("last_used_at DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*domain.Session, len(models))
+	for i, model := range models {
+		sessions[i] = toDomainSession(model)
+	}
+	return sessions, nil
+}
+
+func toDomainSession(model SessionModel) *domain.Session {
+	return domain.ReconstructSession(
+		model.ID,
+		model.UserID,
+		domain.SessionMetadata{UserAgent: model.UserAgent, IP: model.IP},
+		model.TokenHash,
+		time.Unix(model.CreatedAt, 0),
+		time.Unix(model.LastUsedAt, 0),
+		fromUnix(model.RevokedAt),
+		model.RevokeReason,
+	)
+}
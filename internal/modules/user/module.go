@@ -1,39 +1,241 @@
 package user
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/gin-gonic/gin"
+	rbacDomain "github.com/vynazevedo/go-modular-monolith/internal/modules/rbac/domain"
 	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/app"
 	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/domain"
 	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/http"
 	"github.com/vynazevedo/go-modular-monolith/internal/modules/user/infra"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/events"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/mail"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/middleware"
 	"github.com/vynazevedo/go-modular-monolith/internal/shared/module"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/pagination"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/password"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/signedtoken"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
 	"gorm.io/gorm"
 )
 
 type Module struct {
 	service  *app.UserService
+	emails   *app.EmailService
 	handlers *http.UserHandlers
+	purger   *app.Purger
 }
 
-func NewModule(db *gorm.DB) *Module {
+// Deps agrupa o que o módulo user recebe do registro: recursos compartilhados, contratos
+// de outros módulos e a configuração já convertida para os tipos da camada app
+type Deps struct {
+	DB           *gorm.DB
+	Transactions transaction.Manager
+	Cursors      *pagination.CursorCodec
+	Hasher       domain.PasswordHasher
+	Throttle     *app.LoginThrottle
+	Auth         app.AuthConfig
+	Purge        app.PurgeConfig
+	Verification app.VerificationConfig
+	// Links assina os links enviados por e-mail (confirmação de cadastro e de troca)
+	Links      *signedtoken.Codec
+	Mail       mail.Sender
+	Keys       auth.APIKeyResolver
+	Tokens     auth.TokenVerifier
+	Issuer     auth.TokenIssuer
+	Authz      auth.Authorizer
+	RateLimits ratelimit.Limits
+	// Idempotent é o middleware de Idempotency-Key aplicado às rotas que modificam estado
+	Idempotent gin.HandlerFunc
+}
 
-	repo := infra.NewGormUserRepository(db)
-	service := app.NewUserService(repo)
-	handlers := http.NewUserHandlers(service)
+func NewModule(deps Deps) (*Module, error) {
+	repo := infra.NewGormUserRepository(deps.DB, deps.Transactions)
+	service := app.NewUserService(repo, deps.Transactions, deps.Cursors, deps.Hasher, deps.Verification)
+	sessions := infra.NewGormSessionRepository(deps.DB, deps.Transactions)
+	authService, err := app.NewAuthService(repo, sessions, deps.Transactions, deps.Hasher, deps.Issuer, deps.Authz, deps.Throttle, deps.Auth)
+	if err != nil {
+		return nil, err
+	}
+	emails := app.NewEmailService(repo, deps.Transactions, deps.Links, deps.Mail, deps.RateLimits.Store, deps.Authz, deps.Verification)
+	handlers := http.NewUserHandlers(service, authService, emails, deps.Keys, deps.Tokens, deps.Authz, deps.RateLimits, deps.Idempotent)
 
 	return &Module{
 		service:  service,
+		emails:   emails,
 		handlers: handlers,
+		purger:   app.NewPurger(repo, deps.Purge),
+	}, nil
+}
+
+// Definition descreve o módulo para o registro de módulos
+func Definition() module.Definition {
+	return module.Definition{
+		Name:     "user",
+		Provides: []string{domain.UserQueryServiceContract},
+		Requires: []string{auth.APIKeyResolverContract, auth.TokenVerifierContract, auth.TokenIssuerContract, rbacDomain.AuthorizerContract},
+		Setup: func(deps module.Dependencies) (module.Module, error) {
+			keys, err := module.Resolve[auth.APIKeyResolver](deps.Contracts, auth.APIKeyResolverContract)
+			if err != nil {
+				return nil, err
+			}
+			tokens, err := module.Resolve[auth.TokenVerifier](deps.Contracts, auth.TokenVerifierContract)
+			if err != nil {
+				return nil, err
+			}
+			issuer, err := module.Resolve[auth.TokenIssuer](deps.Contracts, auth.TokenIssuerContract)
+			if err != nil {
+				return nil, err
+			}
+			authz, err := module.Resolve[auth.Authorizer](deps.Contracts, rbacDomain.AuthorizerContract)
+			if err != nil {
+				return nil, err
+			}
+
+			authCfg := deps.Config.Auth
+			hasher, err := password.NewHasher(password.Config{
+				Algorithm:         authCfg.PasswordAlgorithm,
+				Argon2Memory:      authCfg.Argon2Memory,
+				Argon2Iterations:  authCfg.Argon2Iterations,
+				Argon2Parallelism: authCfg.Argon2Parallelism,
+				BcryptCost:        authCfg.BcryptCost,
+			})
+			if err != nil {
+				return nil, err
+			}
+			throttle := app.NewLoginThrottle(authCfg.LoginAttemptsPerIP, authCfg.LoginAttemptsWindow)
+
+			deps.Events.Types().Register(
+				domain.UserCreated{},
+				domain.UserUpdated{},
+				domain.UserActivated{},
+				domain.UserSuspended{},
+				domain.UserLocked{},
+				domain.UserDeleted{},
+				domain.UserRestored{},
+				domain.UserEmailVerified{},
+				domain.UserEmailChangeRequested{},
+				domain.UserEmailChangeNotice{},
+				domain.UserEmailChanged{},
+				domain.UserEmailChangeCancelled{},
+				domain.UserPasswordChanged{},
+			)
+			cursors, err := pagination.NewCursorCodec(deps.Config.Pagination.CursorSecret)
+			if err != nil {
+				return nil, err
+			}
+			usersCfg := deps.Config.Users
+			links, err := newLinkCodec(usersCfg.VerificationSecret, usersCfg.RequireEmailVerification)
+			if err != nil {
+				return nil, err
+			}
+			m, err := NewModule(Deps{
+				DB:           deps.DB,
+				Transactions: deps.Transactions,
+				Cursors:      cursors,
+				Hasher:       hasher,
+				Throttle:     throttle,
+				Auth: app.AuthConfig{
+					Lockout:        domain.LockoutPolicy{MaxFailedAttempts: authCfg.MaxFailedLogins, Duration: authCfg.LockoutDuration},
+					Sessions:       domain.SessionPolicy{IdleTimeout: deps.Config.Sessions.IdleTimeout, AbsoluteTimeout: deps.Config.Sessions.AbsoluteTimeout},
+					AccessTokenTTL: authCfg.AccessTokenTTL,
+					DefaultScopes:  authCfg.DefaultScopes,
+				},
+				Purge: app.PurgeConfig{
+					Retention: usersCfg.DeletedRetention,
+					Interval:  usersCfg.PurgeInterval,
+				},
+				Verification: app.VerificationConfig{
+					Required:       usersCfg.RequireEmailVerification,
+					TokenTTL:       usersCfg.VerificationTokenTTL,
+					ResendInterval: usersCfg.VerificationResendInterval,
+					URL:            usersCfg.VerificationURL,
+					ChangeURL:      usersCfg.EmailChangeURL,
+				},
+				Links:      links,
+				Mail:       deps.Mail,
+				Keys:       keys,
+				Tokens:     tokens,
+				Issuer:     issuer,
+				Authz:      authz,
+				RateLimits: deps.RateLimits,
+				Idempotent: middleware.Idempotency(deps.Idempotency, deps.Config.Idempotency.TTL, deps.Config.Idempotency.Lease),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			// Os e-mails saem só depois do commit (cadastro e troca de e-mail), pela outbox. Síncronos e
+			// sem retry no barramento: a falha volta para a outbox, que reagenda com backoff sem segurar
+			// o dispatcher e, no limite, marca dead. Cada evento envia um único e-mail, então a
+			// reentrega não duplica os demais.
+			events.Subscribe(deps.Events, m.sendVerification, events.WithName("user.send_verification_email"))
+			events.Subscribe(deps.Events, m.emails.SendEmailChangeConfirmation, events.WithName("user.send_email_change_confirmation"))
+			events.Subscribe(deps.Events, m.emails.SendEmailChangeNotice, events.WithName("user.send_email_change_notice"))
+			return m, nil
+		},
+	}
+}
+
+// newLinkCodec cria o codec dos links enviados por e-mail. Com a confirmação obrigatória o
+// segredo também é: com chave aleatória, um restart invalidaria os links e prenderia os
+// usuários em pending_verification.
+func newLinkCodec(secret string, required bool) (*signedtoken.Codec, error) {
+	links, err := signedtoken.NewCodec(secret)
+	switch {
+	case err == nil:
+		return links, nil
+	case !errors.Is(err, signedtoken.ErrMissingSecret):
+		return nil, fmt.Errorf("USER_VERIFICATION_SECRET: %w", err)
+	case required:
+		return nil, fmt.Errorf("USER_VERIFICATION_SECRET is required when email verification is required: %w", err)
 	}
+
+	logger.Warn("Verification secret not configured, using a random key; email links will not survive restarts")
+	return signedtoken.NewEphemeralCodec(), nil
+}
+
+// sendVerification envia o link de confirmação aos usuários criados pendentes
+func (m *Module) sendVerification(ctx context.Context, event domain.UserCreated) error {
+	if event.Status != domain.StatusPendingVerification.String() {
+		return nil
+	}
+	return m.emails.SendVerification(ctx, event.UserID)
 }
 
 func (m *Module) RegisterRoutes(router *gin.RouterGroup) {
 	m.handlers.RegisterRoutes(router.Group("/users"))
+	m.handlers.RegisterAuthRoutes(router.Group("/auth"))
+}
+
+// Start inicia a remoção definitiva dos usuários excluídos após a retenção
+func (m *Module) Start(ctx context.Context) error {
+	return m.purger.Start(ctx)
+}
+
+func (m *Module) Stop(ctx context.Context) error {
+	return m.purger.Stop(ctx)
 }
 
 func (m *Module) QueryService() domain.UserQueryService {
 	return m.service
 }
 
+func (m *Module) Exports() map[string]any {
+	return map[string]any{
+		domain.UserQueryServiceContract: m.QueryService(),
+	}
+}
 
-var _ module.Module = (*Module)(nil)
+var (
+	_ module.Module   = (*Module)(nil)
+	_ module.Exporter = (*Module)(nil)
+	_ module.Starter  = (*Module)(nil)
+	_ module.Stopper  = (*Module)(nil)
+)
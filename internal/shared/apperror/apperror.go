@@ -0,0 +1,153 @@
+// Package apperror define os erros tipados compartilhados pelos módulos.
+// Cada erro tem uma categoria (Kind), usada pela camada HTTP para escolher o
+// status, e um código estável que os clientes podem tratar programaticamente.
+package apperror
+
+import (
+	"errors"
+	"strings"
+)
+
+type Kind string
+
+const (
+	KindInternal     Kind = "internal"
+	KindBadRequest   Kind = "bad_request"
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindRateLimited  Kind = "rate_limited"
+	// KindPreconditionFailed indica que uma pré-condição da requisição (ex.: If-Match) não vale mais
+	KindPreconditionFailed Kind = "precondition_failed"
+	// KindUnsupportedMediaType indica um corpo em formato que o endpoint não aceita
+	KindUnsupportedMediaType Kind = "unsupported_media_type"
+	// KindPayloadTooLarge indica um corpo de requisição acima do limite aceito
+	KindPayloadTooLarge Kind = "payload_too_large"
+)
+
+// FieldError descreve a violação de um campo específico da entrada
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error é um erro de aplicação com categoria, código estável e detalhes opcionais
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+
+	cause error
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func BadRequest(code, message string) *Error {
+	return New(KindBadRequest, code, message)
+}
+
+func Validation(code, message string, fields ...FieldError) *Error {
+	return New(KindValidation, code, message).WithFields(fields...)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func RateLimited(code, message string) *Error {
+	return New(KindRateLimited, code, message)
+}
+
+func PreconditionFailed(code, message string) *Error {
+	return New(KindPreconditionFailed, code, message)
+}
+
+func UnsupportedMediaType(code, message string) *Error {
+	return New(KindUnsupportedMediaType, code, message)
+}
+
+func PayloadTooLarge(code, message string) *Error {
+	return New(KindPayloadTooLarge, code, message)
+}
+
+func (e *Error) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+
+	details := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		details[i] = field.Field + ": " + field.Message
+	}
+	return e.Message + " (" + strings.Join(details, "; ") + ")"
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is considera iguais erros com a mesma categoria e código, permitindo comparar
+// com sentinelas via errors.Is mesmo quando o erro foi enriquecido com WithFields/Wrap
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind && e.Code == t.Code
+}
+
+// WithFields retorna uma cópia do erro com os detalhes de campo adicionados
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	clone := *e
+	clone.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return &clone
+}
+
+// WithMessage retorna uma cópia do erro com outra mensagem, mantendo o código
+func (e *Error) WithMessage(message string) *Error {
+	clone := *e
+	clone.Message = message
+	return &clone
+}
+
+// Wrap retorna uma cópia do erro que carrega a causa original
+func (e *Error) Wrap(cause error) *Error {
+	clone := *e
+	clone.cause = cause
+	return &clone
+}
+
+// As extrai o *Error presente na cadeia de err
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// KindOf retorna a categoria de err, ou KindInternal para erros não tipados
+func KindOf(err error) Kind {
+	if appErr, ok := As(err); ok {
+		return appErr.Kind
+	}
+	return KindInternal
+}
@@ -0,0 +1,136 @@
+// Package auth define a identidade autenticada da requisição (Principal) e os
+// contratos que os módulos de credenciais oferecem à camada HTTP.
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+// Nomes dos contratos no registro de módulos
+const (
+	APIKeyResolverContract = "auth.APIKeyResolver"
+	TokenVerifierContract  = "auth.TokenVerifier"
+	TokenIssuerContract    = "auth.TokenIssuer"
+)
+
+// Tipos de principal
+const (
+	KindAPIKey = "api_key"
+	// KindUser é um usuário local, autenticado por um token emitido pelo próprio serviço
+	KindUser = "user"
+	// KindExternal é o titular de um token de outro emissor (IdP); o subject não é um id
+	// de usuário local e não recebe os direitos do próprio usuário nem os papéis do rbac
+	KindExternal = "external"
+)
+
+var (
+	ErrInvalidAPIKey     = apperror.Unauthorized("INVALID_API_KEY", "API Key inválido")
+	ErrInvalidToken      = apperror.Unauthorized("INVALID_TOKEN", "bearer token is invalid")
+	ErrTokenExpired      = apperror.Unauthorized("TOKEN_EXPIRED", "bearer token has expired")
+	ErrUnauthenticated   = apperror.Unauthorized("UNAUTHENTICATED", "authentication is required")
+	ErrInsufficientScope = apperror.Forbidden("INSUFFICIENT_SCOPE", "the credential lacks the required scope")
+	ErrPermissionDenied  = apperror.Forbidden("PERMISSION_DENIED", "the principal lacks the required permission")
+)
+
+// AdminAction é a ação que concede todas as demais sobre o mesmo recurso ("users:admin" ⊃ "users:write")
+const AdminAction = "admin"
+
+// Principal é quem está fazendo a requisição
+type Principal struct {
+	// Subject identifica a credencial (id da API key, id do usuário)
+	Subject string
+	Kind    string
+	Name    string
+	// Scopes são as permissões concedidas à credencial, no formato "<recurso>:<ação>"
+	Scopes []string
+	// SessionID é a sessão de login que originou o token (vazio para API keys e tokens externos)
+	SessionID string
+}
+
+// HasScope informa se o principal possui scope, diretamente ou via "<recurso>:admin"
+func (p *Principal) HasScope(scope string) bool {
+	return Grants(p.Scopes, scope)
+}
+
+// Grants informa se a lista granted (escopos ou permissões) concede permission,
+// diretamente ou via "<recurso>:admin"
+func Grants(granted []string, permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, item := range granted {
+		if item == permission || item == resource+":"+AdminAction {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingScopes retorna, na ordem pedida, os escopos de scopes que o principal não possui
+func (p *Principal) MissingScopes(scopes ...string) []string {
+	var missing []string
+	for _, scope := range scopes {
+		if !p.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// APIKeyResolver valida uma API key em texto puro e devolve o principal correspondente.
+// Chaves desconhecidas, expiradas ou revogadas retornam ErrInvalidAPIKey.
+type APIKeyResolver interface {
+	ResolveAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// TokenVerifier valida um bearer token (JWT) e devolve o principal das suas claims.
+// Tokens expirados retornam ErrTokenExpired; os demais problemas, ErrInvalidToken.
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (*Principal, error)
+}
+
+// Authorizer decide se o principal pode executar uma ação sobre um recurso, expressa como
+// a permissão "<recurso>:<ação>" (ex.: "users:write"). Implementado pelo módulo rbac.
+type Authorizer interface {
+	Can(ctx context.Context, principal *Principal, permission string) (bool, error)
+}
+
+// TokenIssuer emite access tokens para um principal, aceitos pelo TokenVerifier do serviço
+type TokenIssuer interface {
+	IssueToken(ctx context.Context, principal *Principal, ttl time.Duration) (token string, expiresAt time.Time, err error)
+}
+
+type ctxKey struct{}
+
+const ginKey = "auth.principal"
+
+// WithPrincipal retorna uma cópia de ctx carregando o principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, principal)
+}
+
+// FromContext retorna o principal autenticado presente em ctx
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(ctxKey{}).(*Principal)
+	return principal, ok
+}
+
+// SetPrincipal guarda o principal no gin.Context e no context.Context da requisição,
+// para que handlers e casos de uso enxerguem a mesma identidade
+func SetPrincipal(c *gin.Context, principal *Principal) {
+	c.Set(ginKey, principal)
+	c.Request = c.Request.WithContext(WithPrincipal(c.Request.Context(), principal))
+}
+
+// PrincipalFrom retorna o principal autenticado da requisição
+func PrincipalFrom(c *gin.Context) (*Principal, bool) {
+	value, ok := c.Get(ginKey)
+	if !ok {
+		return nil, false
+	}
+	principal, ok := value.(*Principal)
+	return principal, ok
+}
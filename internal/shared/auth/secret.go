@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// HashSecret gera o hash armazenado das credenciais aleatórias emitidas pela aplicação
+// (segredo de API key, refresh token). Usa SHA-256: esses valores têm 256 bits
+// aleatórios, então um hash lento como o de senhas não agrega segurança.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// SecretMatches confere, em tempo constante, se o segredo apresentado corresponde ao hash armazenado
+func SecretMatches(secret, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashSecret(secret)), []byte(hash)) == 1
+}
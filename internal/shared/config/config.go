@@ -5,21 +5,41 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logger   logger.Config
-	CORS     CORSConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Logger      logger.Config
+	CORS        CORSConfig
+	Events      EventsConfig
+	Outbox      OutboxConfig
+	Pagination  PaginationConfig
+	APIKeys     APIKeysConfig
+	JWT         JWTConfig
+	Auth        AuthConfig
+	Sessions    SessionsConfig
+	RateLimit   RateLimitConfig
+	Idempotency IdempotencyConfig
+	Users       UsersConfig
+	Mail        MailConfig
 }
 
 type ServerConfig struct {
-	Port string
-	Mode string
+	Port              string
+	Mode              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	// TrustedProxies são os proxies (IPs ou CIDRs) autorizados a informar o IP do
+	// cliente em X-Forwarded-For; vazio = nenhum, o IP é o da conexão
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -38,6 +58,114 @@ type CORSConfig struct {
 	MaxAge         int
 }
 
+type EventsConfig struct {
+	Workers   int
+	QueueSize int
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Lease        time.Duration
+}
+
+type PaginationConfig struct {
+	CursorSecret string
+}
+
+type APIKeysConfig struct {
+	// BootstrapKey é registrada na inicialização para permitir criar as demais chaves
+	BootstrapKey string
+}
+
+// JWTConfig configura a validação de bearer tokens. Sem JWT_HMAC_SECRET, JWT_JWKS_FILE
+// nem JWT_JWKS_URL, a autenticação por bearer token fica desligada.
+type JWTConfig struct {
+	Issuer              string
+	Audience            []string
+	Algorithms          []string
+	ClockSkew           time.Duration
+	HMACSecret          string
+	JWKSFile            string
+	JWKSURL             string
+	JWKSRefreshInterval time.Duration
+	SigningKeyFile      string
+	SigningKeyID        string
+}
+
+// AuthConfig configura o login com senha
+type AuthConfig struct {
+	PasswordAlgorithm string
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	BcryptCost        int
+	// MaxFailedLogins senhas erradas seguidas bloqueiam a conta por LockoutDuration
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+	// LoginAttemptsPerIP limita as falhas de login por IP a cada LoginAttemptsWindow
+	LoginAttemptsPerIP  int
+	LoginAttemptsWindow time.Duration
+	AccessTokenTTL      time.Duration
+	// DefaultScopes são concedidos aos tokens emitidos no login
+	DefaultScopes []string
+}
+
+// SessionsConfig define a expiração das sessões de login: por inatividade (sem refresh)
+// e absoluta (desde o login). Zero desliga o limite correspondente.
+type SessionsConfig struct {
+	IdleTimeout     time.Duration
+	AbsoluteTimeout time.Duration
+}
+
+// RateLimitConfig define os limites de requisições. Requests <= 0 desliga o limite.
+type RateLimitConfig struct {
+	PerIP  RateLimitRule
+	PerKey RateLimitRule
+	Auth   RateLimitRule
+}
+
+type RateLimitRule struct {
+	Requests int
+	Window   time.Duration
+}
+
+// IdempotencyConfig define por quanto tempo a resposta de uma Idempotency-Key é guardada
+// e por quanto tempo uma requisição em andamento segura a chave (Lease)
+type IdempotencyConfig struct {
+	TTL   time.Duration
+	Lease time.Duration
+}
+
+// UsersConfig define a remoção definitiva dos usuários excluídos: o job roda a cada
+// PurgeInterval e apaga os excluídos há mais de DeletedRetention. Zero desliga o job.
+type UsersConfig struct {
+	DeletedRetention time.Duration
+	PurgeInterval    time.Duration
+	// RequireEmailVerification cria os usuários pendentes até confirmarem o e-mail;
+	// false (padrão) mantém o cadastro já ativo, sem exigir VerificationSecret
+	RequireEmailVerification bool
+	// VerificationSecret assina os links de confirmação, válidos por VerificationTokenTTL
+	VerificationSecret   string
+	VerificationTokenTTL time.Duration
+	// VerificationResendInterval é o intervalo mínimo entre dois envios para o mesmo e-mail
+	VerificationResendInterval time.Duration
+	// VerificationURL é a página que recebe o token (?token=...) e o envia à API
+	VerificationURL string
+	// EmailChangeURL é a página que recebe o token da troca de e-mail e o envia à API
+	EmailChangeURL string
+}
+
+// MailConfig configura o envio de e-mails; os drivers disponíveis são de desenvolvimento
+type MailConfig struct {
+	Driver string
+	From   string
+	Dir    string
+}
+
 func LoadConfig() (*Config, error) {
 	viper.SetConfigFile(".env")
 	viper.AddConfigPath(".")
@@ -45,6 +173,11 @@ func LoadConfig() (*Config, error) {
 
 	viper.SetDefault("PORT", "8080")
 	viper.SetDefault("GIN_MODE", "debug")
+	viper.SetDefault("SERVER_READ_TIMEOUT", "15s")
+	viper.SetDefault("SERVER_READ_HEADER_TIMEOUT", "5s")
+	viper.SetDefault("SERVER_WRITE_TIMEOUT", "30s")
+	viper.SetDefault("SERVER_IDLE_TIMEOUT", "60s")
+	viper.SetDefault("SERVER_SHUTDOWN_TIMEOUT", "20s")
 	viper.SetDefault("DB_HOST", "localhost")
 	viper.SetDefault("DB_PORT", "3306")
 	viper.SetDefault("DB_USER", "root")
@@ -55,9 +188,52 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("LOG_FORMAT", "text")
 	viper.SetDefault("SERVICE_NAME", "go-modular-monolith")
 	viper.SetDefault("CORS_ALLOWED_ORIGINS", "http://localhost:3000,http://localhost:5173")
-	viper.SetDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS")
-	viper.SetDefault("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization,X-API-Key")
+	viper.SetDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
+	viper.SetDefault("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization,X-API-Key,Idempotency-Key,If-Match,If-None-Match")
 	viper.SetDefault("CORS_MAX_AGE", 86400)
+	viper.SetDefault("EVENTS_WORKERS", 4)
+	viper.SetDefault("EVENTS_QUEUE_SIZE", 1024)
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", "1s")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	viper.SetDefault("OUTBOX_BASE_BACKOFF", "2s")
+	viper.SetDefault("OUTBOX_MAX_BACKOFF", "10m")
+	viper.SetDefault("OUTBOX_LEASE", "5m")
+	viper.SetDefault("JWT_ALGORITHMS", "RS256,ES256")
+	viper.SetDefault("JWT_CLOCK_SKEW", "30s")
+	viper.SetDefault("JWT_JWKS_REFRESH_INTERVAL", "15m")
+	viper.SetDefault("JWT_SIGNING_KEY_ID", "local")
+	viper.SetDefault("AUTH_PASSWORD_ALGORITHM", "argon2id")
+	viper.SetDefault("AUTH_ARGON2_MEMORY", 64*1024)
+	viper.SetDefault("AUTH_ARGON2_ITERATIONS", 3)
+	viper.SetDefault("AUTH_ARGON2_PARALLELISM", 2)
+	viper.SetDefault("AUTH_BCRYPT_COST", 12)
+	viper.SetDefault("AUTH_MAX_FAILED_LOGINS", 5)
+	viper.SetDefault("AUTH_LOCKOUT_DURATION", "15m")
+	viper.SetDefault("AUTH_LOGIN_ATTEMPTS_PER_IP", 20)
+	viper.SetDefault("AUTH_LOGIN_ATTEMPTS_WINDOW", "15m")
+	viper.SetDefault("AUTH_ACCESS_TOKEN_TTL", "15m")
+	viper.SetDefault("AUTH_DEFAULT_SCOPES", "")
+	viper.SetDefault("SESSION_IDLE_TIMEOUT", "168h")
+	viper.SetDefault("SESSION_ABSOLUTE_TIMEOUT", "720h")
+	viper.SetDefault("RATE_LIMIT_IP_REQUESTS", 300)
+	viper.SetDefault("RATE_LIMIT_IP_WINDOW", "1m")
+	viper.SetDefault("RATE_LIMIT_KEY_REQUESTS", 600)
+	viper.SetDefault("RATE_LIMIT_KEY_WINDOW", "1m")
+	viper.SetDefault("RATE_LIMIT_AUTH_REQUESTS", 10)
+	viper.SetDefault("RATE_LIMIT_AUTH_WINDOW", "1m")
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("IDEMPOTENCY_LEASE", "1m")
+	viper.SetDefault("USER_DELETED_RETENTION", "720h")
+	viper.SetDefault("USER_PURGE_INTERVAL", "1h")
+	viper.SetDefault("USER_REQUIRE_EMAIL_VERIFICATION", false)
+	viper.SetDefault("USER_VERIFICATION_TOKEN_TTL", "24h")
+	viper.SetDefault("USER_VERIFICATION_RESEND_INTERVAL", "1m")
+	viper.SetDefault("USER_VERIFICATION_URL", "http://localhost:3000/verify-email")
+	viper.SetDefault("USER_EMAIL_CHANGE_URL", "http://localhost:3000/confirm-email-change")
+	viper.SetDefault("MAIL_DRIVER", "stdout")
+	viper.SetDefault("MAIL_FROM", "no-reply@localhost")
+	viper.SetDefault("MAIL_DIR", "tmp/mail")
 
 	if err := viper.ReadInConfig(); err != nil {
 		var configFileNotFoundError viper.ConfigFileNotFoundError
@@ -69,8 +245,14 @@ func LoadConfig() (*Config, error) {
 
 	config := &Config{
 		Server: ServerConfig{
-			Port: viper.GetString("PORT"),
-			Mode: viper.GetString("GIN_MODE"),
+			Port:              viper.GetString("PORT"),
+			Mode:              viper.GetString("GIN_MODE"),
+			ReadTimeout:       viper.GetDuration("SERVER_READ_TIMEOUT"),
+			ReadHeaderTimeout: viper.GetDuration("SERVER_READ_HEADER_TIMEOUT"),
+			WriteTimeout:      viper.GetDuration("SERVER_WRITE_TIMEOUT"),
+			IdleTimeout:       viper.GetDuration("SERVER_IDLE_TIMEOUT"),
+			ShutdownTimeout:   viper.GetDuration("SERVER_SHUTDOWN_TIMEOUT"),
+			TrustedProxies:    splitList(viper.GetString("TRUSTED_PROXIES")),
 		},
 		Database: DatabaseConfig{
 			Host:     viper.GetString("DB_HOST"),
@@ -91,7 +273,90 @@ func LoadConfig() (*Config, error) {
 			AllowedHeaders: strings.Split(viper.GetString("CORS_ALLOWED_HEADERS"), ","),
 			MaxAge:         viper.GetInt("CORS_MAX_AGE"),
 		},
+		Events: EventsConfig{
+			Workers:   viper.GetInt("EVENTS_WORKERS"),
+			QueueSize: viper.GetInt("EVENTS_QUEUE_SIZE"),
+		},
+		Outbox: OutboxConfig{
+			PollInterval: viper.GetDuration("OUTBOX_POLL_INTERVAL"),
+			BatchSize:    viper.GetInt("OUTBOX_BATCH_SIZE"),
+			MaxAttempts:  viper.GetInt("OUTBOX_MAX_ATTEMPTS"),
+			BaseBackoff:  viper.GetDuration("OUTBOX_BASE_BACKOFF"),
+			MaxBackoff:   viper.GetDuration("OUTBOX_MAX_BACKOFF"),
+			Lease:        viper.GetDuration("OUTBOX_LEASE"),
+		},
+		Pagination: PaginationConfig{
+			CursorSecret: viper.GetString("PAGINATION_CURSOR_SECRET"),
+		},
+		APIKeys: APIKeysConfig{
+			BootstrapKey: viper.GetString("APIKEY_BOOTSTRAP_KEY"),
+		},
+		JWT: JWTConfig{
+			Issuer:              viper.GetString("JWT_ISSUER"),
+			Audience:            splitList(viper.GetString("JWT_AUDIENCE")),
+			Algorithms:          splitList(viper.GetString("JWT_ALGORITHMS")),
+			ClockSkew:           viper.GetDuration("JWT_CLOCK_SKEW"),
+			HMACSecret:          viper.GetString("JWT_HMAC_SECRET"),
+			JWKSFile:            viper.GetString("JWT_JWKS_FILE"),
+			JWKSURL:             viper.GetString("JWT_JWKS_URL"),
+			JWKSRefreshInterval: viper.GetDuration("JWT_JWKS_REFRESH_INTERVAL"),
+			SigningKeyFile:      viper.GetString("JWT_SIGNING_KEY_FILE"),
+			SigningKeyID:        viper.GetString("JWT_SIGNING_KEY_ID"),
+		},
+		Auth: AuthConfig{
+			PasswordAlgorithm:   viper.GetString("AUTH_PASSWORD_ALGORITHM"),
+			Argon2Memory:        viper.GetUint32("AUTH_ARGON2_MEMORY"),
+			Argon2Iterations:    viper.GetUint32("AUTH_ARGON2_ITERATIONS"),
+			Argon2Parallelism:   uint8(viper.GetUint("AUTH_ARGON2_PARALLELISM")),
+			BcryptCost:          viper.GetInt("AUTH_BCRYPT_COST"),
+			MaxFailedLogins:     viper.GetInt("AUTH_MAX_FAILED_LOGINS"),
+			LockoutDuration:     viper.GetDuration("AUTH_LOCKOUT_DURATION"),
+			LoginAttemptsPerIP:  viper.GetInt("AUTH_LOGIN_ATTEMPTS_PER_IP"),
+			LoginAttemptsWindow: viper.GetDuration("AUTH_LOGIN_ATTEMPTS_WINDOW"),
+			AccessTokenTTL:      viper.GetDuration("AUTH_ACCESS_TOKEN_TTL"),
+			DefaultScopes:       splitList(viper.GetString("AUTH_DEFAULT_SCOPES")),
+		},
+		Sessions: SessionsConfig{
+			IdleTimeout:     viper.GetDuration("SESSION_IDLE_TIMEOUT"),
+			AbsoluteTimeout: viper.GetDuration("SESSION_ABSOLUTE_TIMEOUT"),
+		},
+		RateLimit: RateLimitConfig{
+			PerIP:  RateLimitRule{Requests: viper.GetInt("RATE_LIMIT_IP_REQUESTS"), Window: viper.GetDuration("RATE_LIMIT_IP_WINDOW")},
+			PerKey: RateLimitRule{Requests: viper.GetInt("RATE_LIMIT_KEY_REQUESTS"), Window: viper.GetDuration("RATE_LIMIT_KEY_WINDOW")},
+			Auth:   RateLimitRule{Requests: viper.GetInt("RATE_LIMIT_AUTH_REQUESTS"), Window: viper.GetDuration("RATE_LIMIT_AUTH_WINDOW")},
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:   viper.GetDuration("IDEMPOTENCY_TTL"),
+			Lease: viper.GetDuration("IDEMPOTENCY_LEASE"),
+		},
+		Users: UsersConfig{
+			DeletedRetention: viper.GetDuration("USER_DELETED_RETENTION"),
+			PurgeInterval:    viper.GetDuration("USER_PURGE_INTERVAL"),
+
+			RequireEmailVerification:   viper.GetBool("USER_REQUIRE_EMAIL_VERIFICATION"),
+			VerificationSecret:         viper.GetString("USER_VERIFICATION_SECRET"),
+			VerificationTokenTTL:       viper.GetDuration("USER_VERIFICATION_TOKEN_TTL"),
+			VerificationResendInterval: viper.GetDuration("USER_VERIFICATION_RESEND_INTERVAL"),
+			VerificationURL:            viper.GetString("USER_VERIFICATION_URL"),
+			EmailChangeURL:             viper.GetString("USER_EMAIL_CHANGE_URL"),
+		},
+		Mail: MailConfig{
+			Driver: viper.GetString("MAIL_DRIVER"),
+			From:   viper.GetString("MAIL_FROM"),
+			Dir:    viper.GetString("MAIL_DIR"),
+		},
 	}
 
 	return config, nil
 }
+
+// splitList separa valores por vírgula, descartando espaços e itens vazios
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -19,7 +19,8 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 		cfg.Database.Name,
 	)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	// TranslateError converte erros do driver (ex.: chave duplicada 1062) em gorm.ErrDuplicatedKey
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, err
 	}
@@ -27,6 +28,16 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
+// Close fecha o pool de conexões subjacente ao GORM
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("erro ao obter conexão do banco: %w", err)
+	}
+
+	return sqlDB.Close()
+}
+
 func ConnectWithEnv() (*gorm.DB, error) {
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -0,0 +1,62 @@
+// Package etag traduz a versão de um recurso em entity tags e interpreta os headers
+// condicionais If-Match e If-None-Match (RFC 9110, seção 13.1).
+package etag
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+var ErrPreconditionFailed = apperror.PreconditionFailed("PRECONDITION_FAILED", "resource does not match If-Match")
+
+// Format devolve a entity tag forte da versão (ex.: "3")
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ExpectedVersion interpreta If-Match. Header ausente ou "*" não impõem versão (nil).
+// Aceita uma única tag forte gerada por Format; tags fracas nunca casam com If-Match
+// e listas não são suportadas, então ambas resultam em ErrPreconditionFailed.
+func ExpectedVersion(header string) (*int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	version, ok := parse(header)
+	if !ok {
+		return nil, ErrPreconditionFailed
+	}
+	return &version, nil
+}
+
+// NoneMatch informa se If-None-Match falha para a tag atual, ou seja, se o cliente já
+// tem a representação. A comparação é fraca: W/"3" casa com "3".
+func NoneMatch(header, current string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(current, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+func parse(tag string) (int64, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,57 @@
+package etag
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	t.Run("Missing header or wildcard impose no version", func(t *testing.T) {
+		for _, header := range []string{"", "*", " * "} {
+			version, err := ExpectedVersion(header)
+			if err != nil || version != nil {
+				t.Errorf("Expected no version for %q, got %v, %v", header, version, err)
+			}
+		}
+	})
+
+	t.Run("Parses a strong tag", func(t *testing.T) {
+		version, err := ExpectedVersion(Format(7))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if version == nil || *version != 7 {
+			t.Errorf("Expected version 7, got %v", version)
+		}
+	})
+
+	t.Run("Rejects weak, malformed and listed tags", func(t *testing.T) {
+		for _, header := range []string{`W/"7"`, `7`, `"abc"`, `"0"`, `"1", "2"`} {
+			if _, err := ExpectedVersion(header); !errors.Is(err, ErrPreconditionFailed) {
+				t.Errorf("Expected ErrPreconditionFailed for %q, got %v", header, err)
+			}
+		}
+	})
+}
+
+func TestNoneMatch(t *testing.T) {
+	current := Format(3)
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`"3"`, true},
+		{`W/"3"`, true},
+		{`"1", "3"`, true},
+		{`"2"`, false},
+		{"*", true},
+	}
+
+	for _, tt := range tests {
+		if got := NoneMatch(tt.header, current); got != tt.want {
+			t.Errorf("Expected NoneMatch(%q) = %v, got %v", tt.header, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Package events implementa um barramento de eventos de domínio em processo,
+// usado para a comunicação entre módulos sem acoplamento direto.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+var ErrBusClosed = errors.New("event bus closed")
+
+// Event é um fato de domínio identificado por um nome estável (ex.: "user.created")
+type Event interface {
+	EventName() string
+}
+
+// Handler processa um evento recebido pelo barramento
+type Handler func(ctx context.Context, event Event) error
+
+// Publisher é o contrato usado pelos casos de uso para publicar eventos
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+type Config struct {
+	Workers   int
+	QueueSize int
+}
+
+type subscription struct {
+	name     string
+	handler  Handler
+	async    bool
+	attempts int
+	backoff  time.Duration
+}
+
+// SubscribeOption customiza o comportamento de um assinante
+type SubscribeOption func(*subscription)
+
+// Async faz o assinante ser executado pelos workers do barramento, fora da requisição
+func Async() SubscribeOption {
+	return func(s *subscription) {
+		s.async = true
+	}
+}
+
+// WithRetry define quantas tentativas o assinante recebe, com backoff exponencial entre elas
+func WithRetry(attempts int, backoff time.Duration) SubscribeOption {
+	return func(s *subscription) {
+		s.attempts = max(attempts, 1)
+		s.backoff = backoff
+	}
+}
+
+// WithName identifica o assinante nos logs
+func WithName(name string) SubscribeOption {
+	return func(s *subscription) {
+		s.name = name
+	}
+}
+
+type asyncDelivery struct {
+	ctx   context.Context
+	sub   *subscription
+	event Event
+}
+
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]*subscription
+	closed        bool
+	types         *Registry
+
+	queue chan asyncDelivery
+	// enqueuing conta os Publish prestes a enfileirar; Close só fecha a fila depois deles
+	enqueuing sync.WaitGroup
+	workers   sync.WaitGroup
+}
+
+// NewBus cria o barramento e inicia os workers dos assinantes assíncronos
+func NewBus(cfg Config) *Bus {
+	b := &Bus{
+		subscriptions: make(map[string][]*subscription),
+		types:         NewRegistry(),
+		queue:         make(chan asyncDelivery, max(cfg.QueueSize, 1)),
+	}
+
+	for range max(cfg.Workers, 1) {
+		b.workers.Add(1)
+		go b.work()
+	}
+
+	return b
+}
+
+// Types retorna o registro de tipos usado para reconstruir eventos serializados
+func (b *Bus) Types() *Registry {
+	return b.types
+}
+
+// Subscribe registra um handler para o evento com o nome informado
+func (b *Bus) Subscribe(eventName string, handler Handler, opts ...SubscribeOption) {
+	sub := &subscription{
+		name:     fmt.Sprintf("%s#%d", eventName, b.count(eventName)+1),
+		handler:  handler,
+		attempts: 1,
+	}
+	for _, opt := range opts {
+		opt(sub)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[eventName] = append(b.subscriptions[eventName], sub)
+}
+
+// Subscribe registra um handler tipado; o nome do evento vem do próprio tipo T
+func Subscribe[T Event](b *Bus, handler func(ctx context.Context, event T) error, opts ...SubscribeOption) {
+	var zero T
+	b.Subscribe(zero.EventName(), func(ctx context.Context, event Event) error {
+		typed, ok := event.(T)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T for %s", event, zero.EventName())
+		}
+		return handler(ctx, typed)
+	}, opts...)
+}
+
+// Publish entrega os eventos aos assinantes. Assinantes síncronos rodam em ordem
+// de registro e a falha de um não impede os demais; os erros são agregados no retorno.
+// Assinantes assíncronos são enfileirados e seus erros apenas registrados em log.
+// Nenhum lock fica preso durante a entrega: handlers podem publicar e assinar.
+func (b *Bus) Publish(ctx context.Context, events ...Event) error {
+	targets, err := b.subscribersOf(events)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, event := range events {
+		for _, sub := range targets[i] {
+			if !sub.async {
+				if err := deliver(ctx, sub, event); err != nil {
+					errs = append(errs, err)
+				}
+				continue
+			}
+
+			if err := b.enqueue(ctx, asyncDelivery{ctx: context.WithoutCancel(ctx), sub: sub, event: event}); err != nil {
+				errs = append(errs, fmt.Errorf("enqueue %s for %s: %w", event.EventName(), sub.name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// subscribersOf copia, sob o lock, os assinantes de cada evento
+func (b *Bus) subscribersOf(events []Event) ([][]*subscription, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, ErrBusClosed
+	}
+
+	targets := make([][]*subscription, len(events))
+	for i, event := range events {
+		targets[i] = append([]*subscription(nil), b.subscriptions[event.EventName()]...)
+	}
+	return targets, nil
+}
+
+// enqueue entrega ao worker; espera apenas enquanto a fila estiver cheia
+func (b *Bus) enqueue(ctx context.Context, delivery asyncDelivery) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBusClosed
+	}
+	b.enqueuing.Add(1)
+	b.mu.RUnlock()
+	defer b.enqueuing.Done()
+
+	select {
+	case b.queue <- delivery:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close deixa de aceitar eventos e aguarda os assinantes assíncronos pendentes até o prazo de ctx
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	alreadyClosed := b.closed
+	b.closed = true
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		if !alreadyClosed {
+			// Os workers seguem drenando a fila, então quem está enfileirando termina
+			b.enqueuing.Wait()
+			close(b.queue)
+		}
+		b.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("event bus close: %w", ctx.Err())
+	}
+}
+
+func (b *Bus) count(eventName string) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscriptions[eventName])
+}
+
+func (b *Bus) work() {
+	defer b.workers.Done()
+	for delivery := range b.queue {
+		_ = deliver(delivery.ctx, delivery.sub, delivery.event)
+	}
+}
+
+// deliver executa o handler com retry, isolando panics e registrando a falha final
+func deliver(ctx context.Context, sub *subscription, event Event) error {
+	var err error
+	backoff := sub.backoff
+
+retry:
+	for attempt := 1; attempt <= sub.attempts; attempt++ {
+		if err = call(ctx, sub.handler, event); err == nil {
+			return nil
+		}
+
+		if attempt == sub.attempts {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			err = errors.Join(err, ctx.Err())
+			break retry
+		}
+	}
+
+	logger.WithContext(ctx).WithFields(logrus.Fields{
+		"event":      event.EventName(),
+		"subscriber": sub.name,
+	}).Errorf("Event handler failed: %v", err)
+
+	return fmt.Errorf("%s handling %s: %w", sub.name, event.EventName(), err)
+}
+
+func call(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
@@ -0,0 +1,212 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	ID string
+}
+
+func (testEvent) EventName() string {
+	return "test.happened"
+}
+
+type otherEvent struct{}
+
+func (otherEvent) EventName() string {
+	return "test.other"
+}
+
+func newTestBus(t *testing.T) *Bus {
+	t.Helper()
+	bus := NewBus(Config{Workers: 2, QueueSize: 16})
+	t.Cleanup(func() {
+		_ = bus.Close(context.Background())
+	})
+	return bus
+}
+
+func TestPublishSync(t *testing.T) {
+	t.Run("Delivers typed events to matching subscribers in order", func(t *testing.T) {
+		bus := newTestBus(t)
+
+		var received []string
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			received = append(received, "first:"+event.ID)
+			return nil
+		})
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			received = append(received, "second:"+event.ID)
+			return nil
+		})
+		Subscribe(bus, func(ctx context.Context, event otherEvent) error {
+			received = append(received, "other")
+			return nil
+		})
+
+		if err := bus.Publish(context.Background(), testEvent{ID: "1"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(received) != 2 || received[0] != "first:1" || received[1] != "second:1" {
+			t.Errorf("Unexpected deliveries: %v", received)
+		}
+	})
+
+	t.Run("Failing subscriber does not affect the others", func(t *testing.T) {
+		bus := newTestBus(t)
+		handlerErr := errors.New("boom")
+
+		var delivered bool
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			return handlerErr
+		})
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			panic("unexpected")
+		})
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			delivered = true
+			return nil
+		})
+
+		err := bus.Publish(context.Background(), testEvent{ID: "1"})
+		if !errors.Is(err, handlerErr) {
+			t.Errorf("Expected handler error, got %v", err)
+		}
+
+		if !delivered {
+			t.Error("Expected healthy subscriber to receive the event")
+		}
+	})
+
+	t.Run("Retries failing subscriber", func(t *testing.T) {
+		bus := newTestBus(t)
+
+		attempts := 0
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("transient")
+			}
+			return nil
+		}, WithRetry(3, time.Millisecond))
+
+		if err := bus.Publish(context.Background(), testEvent{ID: "1"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+	})
+}
+
+func TestPublishReentrant(t *testing.T) {
+	t.Run("Handlers can publish and subscribe", func(t *testing.T) {
+		bus := newTestBus(t)
+
+		var others atomic.Int32
+		Subscribe(bus, func(ctx context.Context, event otherEvent) error {
+			others.Add(1)
+			return nil
+		})
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			Subscribe(bus, func(ctx context.Context, event otherEvent) error { return nil })
+			return bus.Publish(ctx, otherEvent{})
+		})
+
+		done := make(chan error, 1)
+		go func() { done <- bus.Publish(context.Background(), testEvent{}) }()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected Publish to return, it deadlocked")
+		}
+		if others.Load() != 1 {
+			t.Errorf("Expected the nested event to be delivered once, got %d", others.Load())
+		}
+	})
+
+	t.Run("Close does not wait for slow sync handlers", func(t *testing.T) {
+		bus := NewBus(Config{Workers: 1, QueueSize: 1})
+
+		release := make(chan struct{})
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			<-release
+			return nil
+		})
+		go func() { _ = bus.Publish(context.Background(), testEvent{}) }()
+		time.Sleep(10 * time.Millisecond)
+		defer close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := bus.Close(ctx); err != nil {
+			t.Errorf("Expected Close to return while a sync handler runs, got %v", err)
+		}
+	})
+}
+
+func TestPublishAsync(t *testing.T) {
+	t.Run("Async subscribers run outside the publisher", func(t *testing.T) {
+		bus := newTestBus(t)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		var received atomic.Value
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			defer wg.Done()
+			received.Store(event.ID)
+			return errors.New("async errors are only logged")
+		}, Async())
+
+		if err := bus.Publish(context.Background(), testEvent{ID: "42"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		wg.Wait()
+		if received.Load() != "42" {
+			t.Errorf("Expected event 42, got %v", received.Load())
+		}
+	})
+
+	t.Run("Close drains pending deliveries", func(t *testing.T) {
+		bus := NewBus(Config{Workers: 1, QueueSize: 16})
+
+		var count atomic.Int32
+		Subscribe(bus, func(ctx context.Context, event testEvent) error {
+			time.Sleep(time.Millisecond)
+			count.Add(1)
+			return nil
+		}, Async())
+
+		for range 5 {
+			if err := bus.Publish(context.Background(), testEvent{}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		if err := bus.Close(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if count.Load() != 5 {
+			t.Errorf("Expected 5 deliveries, got %d", count.Load())
+		}
+
+		if err := bus.Publish(context.Background(), testEvent{}); !errors.Is(err, ErrBusClosed) {
+			t.Errorf("Expected ErrBusClosed, got %v", err)
+		}
+	})
+}
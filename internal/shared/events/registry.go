@@ -0,0 +1,52 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Registry mapeia nomes de eventos para seus tipos concretos, permitindo
+// reconstruir eventos serializados (ex.: lidos do outbox).
+type Registry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string]reflect.Type)}
+}
+
+// Register associa o nome de cada protótipo ao seu tipo
+func (r *Registry) Register(prototypes ...Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, prototype := range prototypes {
+		r.types[prototype.EventName()] = reflect.TypeOf(prototype)
+	}
+}
+
+// Decode reconstrói o evento a partir do nome e do payload JSON
+func (r *Registry) Decode(name string, payload []byte) (Event, error) {
+	r.mu.RLock()
+	typ, ok := r.types[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", name)
+	}
+
+	if typ.Kind() == reflect.Pointer {
+		value := reflect.New(typ.Elem())
+		if err := json.Unmarshal(payload, value.Interface()); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", name, err)
+		}
+		return value.Interface().(Event), nil
+	}
+
+	value := reflect.New(typ)
+	if err := json.Unmarshal(payload, value.Interface()); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", name, err)
+	}
+	return value.Elem().Interface().(Event), nil
+}
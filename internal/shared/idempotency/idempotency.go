@@ -0,0 +1,189 @@
+// Package idempotency guarda a primeira resposta de cada Idempotency-Key para que
+// retentativas de uma requisição recebam a mesma resposta em vez de repetir o efeito.
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Record é uma chave reservada. StatusCode zero indica requisição ainda em andamento.
+type Record struct {
+	// ID é o hash da chave junto com o escopo (principal, método e rota)
+	ID string
+	// RequestHash identifica o corpo da requisição original
+	RequestHash string
+	StatusCode  int
+	Headers     map[string]string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	// LockedUntil é o prazo da reserva em andamento: se a requisição original não
+	// terminar até lá (ex.: a instância caiu), uma retentativa assume a chave
+	LockedUntil time.Time
+}
+
+// Completed informa se a resposta original já foi gravada
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Store persiste as chaves. Implementações precisam ser seguras para uso concorrente.
+type Store interface {
+	// Reserve grava record como em andamento. Se a chave já existe e não expirou,
+	// nada é gravado e o registro existente é devolvido, exceto quando ainda está em
+	// andamento com LockedUntil vencido: nesse caso a reserva é assumida por record.
+	Reserve(ctx context.Context, record *Record) (existing *Record, err error)
+	// Complete grava a resposta da requisição reservada por record, se a reserva ainda é
+	// dela: uma reserva assumida por uma retentativa não é sobrescrita
+	Complete(ctx context.Context, record *Record) error
+	// Release apaga a reserva feita por record, liberando a chave para uma nova tentativa.
+	// Uma reserva já assumida por uma retentativa é mantida.
+	Release(ctx context.Context, record *Record) error
+}
+
+type KeyModel struct {
+	ID          string `gorm:"primaryKey;size:64"`
+	RequestHash string `gorm:"size:64"`
+	StatusCode  int
+	// Headers guarda em JSON os headers da resposta repetidos no replay
+	Headers     string
+	Body        []byte
+	CreatedAt   int64
+	ExpiresAt   int64 `gorm:"index"`
+	LockedUntil int64
+}
+
+func (KeyModel) TableName() string {
+	return "idempotency_keys"
+}
+
+// GormStore guarda as chaves na tabela idempotency_keys. As chaves expiradas são
+// ignoradas na leitura e apagadas em lote a cada purgeInterval.
+type GormStore struct {
+	db  *gorm.DB
+	now func() time.Time
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+// ownedReservation filtra a reserva em andamento feita por um record. Quem assume uma
+// reserva abandonada grava um LockedUntil posterior ao vencido, então o prazo identifica o dono.
+const ownedReservation = "id = ? AND status_code = 0 AND locked_until = ?"
+
+// purgeInterval limita a frequência da limpeza das chaves expiradas
+const purgeInterval = time.Minute
+
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db, now: time.Now}
+}
+
+func (s *GormStore) Reserve(ctx context.Context, record *Record) (*Record, error) {
+	now := s.now()
+	s.purge(ctx, now)
+
+	model := KeyModel{
+		ID:          record.ID,
+		RequestHash: record.RequestHash,
+		CreatedAt:   record.CreatedAt.Unix(),
+		ExpiresAt:   record.ExpiresAt.Unix(),
+		LockedUntil: record.LockedUntil.Unix(),
+	}
+
+	// Uma chave expirada ou uma reserva abandonada é substituída: apaga e tenta de novo uma única vez
+	for range 2 {
+		result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil, nil
+		}
+
+		var existing KeyModel
+		err := s.db.WithContext(ctx).First(&existing, "id = ?", record.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		abandoned := existing.StatusCode == 0 && existing.LockedUntil <= now.Unix()
+		if existing.ExpiresAt > now.Unix() && !abandoned {
+			return toRecord(existing)
+		}
+
+		err = s.db.WithContext(ctx).Delete(&KeyModel{},
+			"id = ? AND (expires_at <= ? OR (status_code = 0 AND locked_until <= ?))", record.ID, now.Unix(), now.Unix()).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errors.New("idempotency key reservation conflicted twice")
+}
+
+func (s *GormStore) Complete(ctx context.Context, record *Record) error {
+	headers, err := json.Marshal(record.Headers)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).
+		Model(&KeyModel{}).
+		Where(ownedReservation, record.ID, record.LockedUntil.Unix()).
+		Updates(map[string]any{
+			"status_code": record.StatusCode,
+			"headers":     string(headers),
+			"body":        record.Body,
+		}).Error
+}
+
+func (s *GormStore) Release(ctx context.Context, record *Record) error {
+	return s.db.WithContext(ctx).Delete(&KeyModel{}, ownedReservation, record.ID, record.LockedUntil.Unix()).Error
+}
+
+// purge apaga as chaves expiradas, no máximo uma vez por purgeInterval. Falhas não
+// impedem a requisição: as chaves expiradas já são ignoradas na leitura.
+func (s *GormStore) purge(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	due := now.Sub(s.lastPurge) >= purgeInterval
+	if due {
+		s.lastPurge = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	_ = s.db.WithContext(ctx).Delete(&KeyModel{}, "expires_at <= ?", now.Unix()).Error
+}
+
+func toRecord(model KeyModel) (*Record, error) {
+	var headers map[string]string
+	if model.Headers != "" {
+		if err := json.Unmarshal([]byte(model.Headers), &headers); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Record{
+		ID:          model.ID,
+		RequestHash: model.RequestHash,
+		StatusCode:  model.StatusCode,
+		Headers:     headers,
+		Body:        model.Body,
+		CreatedAt:   time.Unix(model.CreatedAt, 0),
+		ExpiresAt:   time.Unix(model.ExpiresAt, 0),
+		LockedUntil: time.Unix(model.LockedUntil, 0),
+	}, nil
+}
+
+var _ Store = (*GormStore)(nil)
@@ -0,0 +1,134 @@
+package idempotency
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func newTestStore(t *testing.T) *GormStore {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "idempotency.db")), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&KeyModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	return NewGormStore(db)
+}
+
+func TestGormStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("Second reservation returns the stored response", func(t *testing.T) {
+		store := newTestStore(t)
+		record := &Record{ID: "key", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour), LockedUntil: now.Add(time.Minute)}
+
+		if existing, err := store.Reserve(ctx, record); err != nil || existing != nil {
+			t.Fatalf("Expected a new reservation, got %+v, %v", existing, err)
+		}
+		if existing, _ := store.Reserve(ctx, record); existing == nil || existing.Completed() {
+			t.Fatalf("Expected the in-progress reservation, got %+v", existing)
+		}
+
+		record.StatusCode = 201
+		record.Headers = map[string]string{"Content-Type": "application/json"}
+		record.Body = []byte(`{"id":"1"}`)
+		if err := store.Complete(ctx, record); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		existing, err := store.Reserve(ctx, record)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if existing.StatusCode != 201 || string(existing.Body) != `{"id":"1"}` || existing.Headers["Content-Type"] != "application/json" {
+			t.Errorf("Unexpected stored response: %+v", existing)
+		}
+	})
+
+	t.Run("Expired key is replaced", func(t *testing.T) {
+		store := newTestStore(t)
+		_, _ = store.Reserve(ctx, &Record{ID: "key", RequestHash: "old", CreatedAt: now, ExpiresAt: now.Add(time.Minute)})
+
+		store.now = func() time.Time { return now.Add(time.Minute) }
+		existing, err := store.Reserve(ctx, &Record{ID: "key", RequestHash: "new", CreatedAt: now, ExpiresAt: now.Add(2 * time.Minute)})
+		if err != nil || existing != nil {
+			t.Errorf("Expected the expired key to be replaced, got %+v, %v", existing, err)
+		}
+	})
+
+	t.Run("Abandoned reservation is taken over after its lease", func(t *testing.T) {
+		store := newTestStore(t)
+		record := &Record{ID: "key", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour), LockedUntil: now.Add(time.Minute)}
+		_, _ = store.Reserve(ctx, record)
+
+		store.now = func() time.Time { return now.Add(30 * time.Second) }
+		if existing, _ := store.Reserve(ctx, record); existing == nil {
+			t.Fatalf("Expected the reservation to hold during its lease")
+		}
+
+		store.now = func() time.Time { return now.Add(time.Minute) }
+		retry := &Record{ID: "key", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour), LockedUntil: now.Add(2 * time.Minute)}
+		if existing, err := store.Reserve(ctx, retry); err != nil || existing != nil {
+			t.Errorf("Expected the abandoned reservation to be taken over, got %+v, %v", existing, err)
+		}
+	})
+
+	t.Run("Original request does not touch a reservation taken over by a retry", func(t *testing.T) {
+		store := newTestStore(t)
+		original := &Record{ID: "key", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour), LockedUntil: now.Add(time.Minute)}
+		_, _ = store.Reserve(ctx, original)
+
+		store.now = func() time.Time { return now.Add(time.Minute) }
+		retry := &Record{ID: "key", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour), LockedUntil: now.Add(2 * time.Minute)}
+		if existing, err := store.Reserve(ctx, retry); err != nil || existing != nil {
+			t.Fatalf("Expected the retry to take over, got %+v, %v", existing, err)
+		}
+
+		original.StatusCode = 500
+		if err := store.Complete(ctx, original); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := store.Release(ctx, original); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		existing, _ := store.Reserve(ctx, retry)
+		if existing == nil || existing.Completed() || !existing.LockedUntil.Equal(time.Unix(retry.LockedUntil.Unix(), 0)) {
+			t.Fatalf("Expected the retry's reservation to remain, got %+v", existing)
+		}
+
+		retry.StatusCode = 201
+		if err := store.Complete(ctx, retry); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if existing, _ := store.Reserve(ctx, retry); existing == nil || existing.StatusCode != 201 {
+			t.Errorf("Expected the retry's response, got %+v", existing)
+		}
+	})
+
+	t.Run("Completed response is kept after the lease", func(t *testing.T) {
+		store := newTestStore(t)
+		record := &Record{ID: "key", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour), LockedUntil: now.Add(time.Minute)}
+		_, _ = store.Reserve(ctx, record)
+		record.StatusCode = 201
+		_ = store.Complete(ctx, record)
+
+		store.now = func() time.Time { return now.Add(2 * time.Minute) }
+		if existing, _ := store.Reserve(ctx, record); existing == nil || existing.StatusCode != 201 {
+			t.Errorf("Expected the stored response, got %+v", existing)
+		}
+	})
+}
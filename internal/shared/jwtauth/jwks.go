@@ -0,0 +1,327 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+// maxJWKSSize limita o corpo aceito de um JWKS remoto
+const maxJWKSSize = 1 << 20
+
+var (
+	errKeyNotFound     = errors.New("signing key not found in JWKS")
+	errJWKSUnavailable = errors.New("jwks unavailable")
+)
+
+// jwk é uma chave no formato da RFC 7517; só os campos usados na verificação são lidos
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	// Simétrica
+	K string `json:"k"`
+}
+
+type verificationKey struct {
+	alg string
+	key any
+}
+
+// JWKS mantém em cache as chaves públicas de um arquivo local ou URL. O conjunto é
+// recarregado a cada refreshInterval e, ao aparecer um kid desconhecido (rotação),
+// no máximo uma vez por minRefreshInterval. Se a recarga falhar, as chaves atuais continuam valendo.
+// A origem é consultada sem o lock: as chaves em cache continuam sendo servidas durante
+// a recarga, e requisições simultâneas compartilham uma única consulta.
+type JWKS struct {
+	source             string
+	fetch              func(ctx context.Context) ([]byte, error)
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+	now                func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]verificationKey
+	fetchedAt time.Time
+	// lastErr guarda a falha da primeira carga para não consultar a origem a cada requisição
+	lastErr error
+	// refreshing é fechado quando a recarga em andamento termina; nil sem recarga
+	refreshing chan struct{}
+}
+
+// NewJWKSFile lê as chaves de um arquivo JWKS local
+func NewJWKSFile(path string, refreshInterval time.Duration) *JWKS {
+	return newJWKS(path, refreshInterval, func(ctx context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	})
+}
+
+// NewJWKSURL busca as chaves de um endpoint JWKS (ex.: .well-known/jwks.json do provedor)
+func NewJWKSURL(url string, refreshInterval time.Duration, client *http.Client) *JWKS {
+	return newJWKS(url, refreshInterval, func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	})
+}
+
+func newJWKS(source string, refreshInterval time.Duration, fetch func(ctx context.Context) ([]byte, error)) *JWKS {
+	minRefresh := time.Minute
+	if refreshInterval < minRefresh {
+		minRefresh = refreshInterval
+	}
+
+	return &JWKS{
+		source:             source,
+		fetch:              fetch,
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: minRefresh,
+		now:                time.Now,
+	}
+}
+
+// Key retorna a chave identificada por kid. Com kid vazio, a chave só é escolhida
+// se o conjunto tiver exatamente uma chave.
+func (s *JWKS) Key(ctx context.Context, kid string) (string, any, error) {
+	s.mu.Lock()
+	now := s.now()
+	if s.keys == nil && s.lastErr != nil && now.Sub(s.fetchedAt) < s.minRefreshInterval {
+		err := s.lastErr
+		s.mu.Unlock()
+		return "", nil, err
+	}
+
+	loaded := s.keys != nil
+	stale := !loaded || now.Sub(s.fetchedAt) >= s.refreshInterval
+	key, ok := s.lookup(kid)
+	rotated := !ok && !stale && now.Sub(s.fetchedAt) >= s.minRefreshInterval
+	s.mu.Unlock()
+
+	switch {
+	case !loaded, rotated:
+		// Sem chaves ou kid novo (o provedor pode ter rotacionado): espera a recarga
+		if err := s.wait(ctx, s.refresh(ctx, now)); err != nil {
+			return "", nil, err
+		}
+
+		s.mu.Lock()
+		if s.keys == nil {
+			err := s.lastErr
+			s.mu.Unlock()
+			return "", nil, err
+		}
+		key, ok = s.lookup(kid)
+		s.mu.Unlock()
+
+	case stale:
+		// Conjunto vencido: recarrega em background e responde com o cache
+		s.refresh(ctx, now)
+	}
+
+	if !ok {
+		return "", nil, errKeyNotFound
+	}
+
+	return key.alg, key.key, nil
+}
+
+func (s *JWKS) wait(ctx context.Context, done <-chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", errJWKSUnavailable, ctx.Err())
+	}
+}
+
+func (s *JWKS) lookup(kid string) (verificationKey, bool) {
+	if kid == "" {
+		if len(s.keys) != 1 {
+			return verificationKey{}, false
+		}
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// refresh inicia a recarga do conjunto, ou reaproveita a que está em andamento, e
+// retorna um canal fechado ao fim dela. A consulta não herda o cancelamento da
+// requisição que a disparou, pois outras podem estar esperando por ela.
+func (s *JWKS) refresh(ctx context.Context, now time.Time) <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.refreshing != nil {
+		return s.refreshing
+	}
+
+	done := make(chan struct{})
+	s.refreshing = done
+
+	go func() {
+		defer close(done)
+
+		keys, err := s.load(context.WithoutCancel(ctx))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.refreshing = nil
+		s.store(keys, err, now)
+	}()
+
+	return done
+}
+
+// store grava o resultado da recarga; em caso de falha mantém as chaves anteriores e
+// adia a próxima tentativa para não sobrecarregar a origem
+func (s *JWKS) store(keys map[string]verificationKey, err error, now time.Time) {
+	if err != nil {
+		logger.WithField("jwks_source", s.source).WithError(err).Warn("Failed to load JWKS")
+		if s.keys == nil {
+			s.lastErr = fmt.Errorf("%w: %w", errJWKSUnavailable, err)
+			s.fetchedAt = now
+		} else {
+			s.fetchedAt = now.Add(s.minRefreshInterval - s.refreshInterval)
+		}
+		return
+	}
+
+	s.keys = keys
+	s.lastErr = nil
+	s.fetchedAt = now
+	logger.WithField("jwks_source", s.source).Debugf("JWKS loaded with %d keys", len(keys))
+}
+
+func (s *JWKS) load(ctx context.Context) (map[string]verificationKey, error) {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]verificationKey, len(set.Keys))
+	for _, raw := range set.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+
+		key, err := raw.publicKey()
+		if err != nil {
+			// Uma chave inválida não deve derrubar as demais
+			logger.WithField("kid", raw.Kid).WithError(err).Warn("Ignoring invalid JWK")
+			continue
+		}
+		keys[raw.Kid] = verificationKey{alg: raw.Alg, key: key}
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		curve, err := curveOf(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, errX := decodeBigInt(k.X)
+		y, errY := decodeBigInt(k.Y)
+		if errX != nil || errY != nil {
+			return nil, errors.New("invalid coordinates")
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if _, err := key.ECDH(); err != nil {
+			return nil, errors.New("point is not on the curve")
+		}
+		return key, nil
+
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil || len(secret) == 0 {
+			return nil, errors.New("invalid symmetric key")
+		}
+		return secret, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func curveOf(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", name)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
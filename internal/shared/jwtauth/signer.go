@@ -0,0 +1,138 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+var errSigningDisabled = errors.New("token signing is not configured (set JWT_SIGNING_KEY_FILE or JWT_HMAC_SECRET)")
+
+// Signer implementa auth.TokenIssuer. Usa a chave de SigningKeyFile (RS*/ES*, com kid)
+// ou, na falta dela, HS256 com o HMACSecret. Os tokens emitidos usam o mesmo issuer e
+// audience que o Verifier exige, então são aceitos pelo próprio serviço.
+type Signer struct {
+	cfg    Config
+	method jwt.SigningMethod
+	key    any
+	kid    string
+	now    func() time.Time
+}
+
+// NewSigner monta o emissor. Sem chave configurada, IssueToken sempre falha.
+func NewSigner(cfg Config) (*Signer, error) {
+	s := &Signer{cfg: cfg, now: time.Now}
+
+	switch {
+	case cfg.SigningKeyFile != "":
+		alg, key, err := loadSigningKey(cfg.SigningKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("jwt signing key: %w", err)
+		}
+		s.method, s.key, s.kid = jwt.GetSigningMethod(alg), key, cfg.SigningKeyID
+	case cfg.HMACSecret != "":
+		s.method, s.key = jwt.SigningMethodHS256, []byte(cfg.HMACSecret)
+	default:
+		return s, nil
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("jwt config: %w", err)
+	}
+	if !slices.Contains(cfg.Algorithms, s.method.Alg()) {
+		return nil, fmt.Errorf("jwt config: signing algorithm %s is not in the accepted algorithms", s.method.Alg())
+	}
+
+	return s, nil
+}
+
+// Enabled informa se o serviço pode emitir tokens
+func (s *Signer) Enabled() bool {
+	return s.method != nil
+}
+
+func (s *Signer) IssueToken(ctx context.Context, principal *auth.Principal, ttl time.Duration) (string, time.Time, error) {
+	if s.method == nil {
+		return "", time.Time{}, errSigningDisabled
+	}
+
+	now := s.now()
+	expiresAt := now.Add(ttl)
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    s.cfg.Issuer,
+			Subject:   principal.Subject,
+			Audience:  s.cfg.Audience,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Name:      principal.Name,
+		Scope:     strings.Join(principal.Scopes, " "),
+		SessionID: principal.SessionID,
+	}
+
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.kid != "" {
+		token.Header["kid"] = s.kid
+	}
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// loadSigningKey lê uma chave privada PEM (PKCS#8, PKCS#1 ou SEC 1) e deduz o algoritmo
+func loadSigningKey(path string) (string, crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", nil, errors.New("no PEM block found")
+	}
+
+	var key any
+	if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			if key, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
+				return "", nil, errors.New("unsupported private key format")
+			}
+		}
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return "RS256", key, nil
+	case *ecdsa.PrivateKey:
+		switch key.Curve.Params().Name {
+		case "P-256":
+			return "ES256", key, nil
+		case "P-384":
+			return "ES384", key, nil
+		case "P-521":
+			return "ES512", key, nil
+		}
+	}
+	return "", nil, errors.New("signing key must be RSA or ECDSA (P-256, P-384, P-521)")
+}
+
+var _ auth.TokenIssuer = (*Signer)(nil)
@@ -0,0 +1,81 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+func writeECKey(t *testing.T) string {
+	t.Helper()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return path
+}
+
+func TestSigner(t *testing.T) {
+	ctx := context.Background()
+	principal := &auth.Principal{Subject: "user-1", Kind: auth.KindUser, Name: "Ana", Scopes: []string{"users:read"}, SessionID: "session-1"}
+
+	configs := map[string]Config{
+		"HS256 secret":    {Algorithms: []string{"HS256"}, HMACSecret: testSecret},
+		"ES256 local key": {Algorithms: []string{"RS256", "ES256"}, SigningKeyFile: writeECKey(t), SigningKeyID: "local"},
+	}
+
+	for name, cfg := range configs {
+		t.Run(name+" tokens are accepted by the verifier", func(t *testing.T) {
+			cfg.Issuer = testIssuer
+			cfg.Audience = []string{testAudience}
+
+			signer, err := NewSigner(cfg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			verifier, _ := NewVerifier(cfg)
+
+			token, expiresAt, err := signer.IssueToken(ctx, principal, time.Minute)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if time.Until(expiresAt) > time.Minute {
+				t.Errorf("Expected expiry within the ttl, got %v", expiresAt)
+			}
+
+			got, err := verifier.VerifyToken(ctx, token)
+			if err != nil {
+				t.Fatalf("Expected issued token to verify, got %v", err)
+			}
+			if got.Subject != principal.Subject || got.Name != "Ana" || !got.HasScope("users:read") || got.SessionID != "session-1" || got.Kind != auth.KindUser {
+				t.Errorf("Unexpected principal: %+v", got)
+			}
+		})
+	}
+
+	t.Run("Signing algorithm must be accepted", func(t *testing.T) {
+		_, err := NewSigner(Config{Issuer: testIssuer, Audience: []string{testAudience}, Algorithms: []string{"RS256"}, SigningKeyFile: writeECKey(t)})
+		if err == nil {
+			t.Error("Expected error when ES256 is not in the accepted algorithms")
+		}
+	})
+
+	t.Run("Disabled signer", func(t *testing.T) {
+		signer, _ := NewSigner(Config{})
+		if _, _, err := signer.IssueToken(ctx, principal, time.Minute); err == nil {
+			t.Error("Expected error without signing key")
+		}
+	})
+}
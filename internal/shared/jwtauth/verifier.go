@@ -0,0 +1,265 @@
+// Package jwtauth valida bearer tokens JWT (RS256, ES256, HS256 e variantes) e
+// converte suas claims no mesmo auth.Principal usado pelas API keys.
+package jwtauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+const (
+	// MinHMACSecretLength é o tamanho mínimo do segredo HS*, igual ao tamanho do hash do HS256
+	MinHMACSecretLength = 32
+
+	defaultRefreshInterval = 15 * time.Minute
+	jwksFetchTimeout       = 10 * time.Second
+)
+
+// SupportedAlgorithms são os algoritmos aceitos em JWT_ALGORITHMS
+var SupportedAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "HS256", "HS384", "HS512"}
+
+var (
+	errKeyAlgMismatch = errors.New("token algorithm does not match the key algorithm")
+	errNoKeySource    = errors.New("no key configured for the token algorithm")
+)
+
+type Config struct {
+	// Issuer é comparado com a claim iss
+	Issuer string
+	// Audience lista os valores aceitos na claim aud; basta um coincidir
+	Audience []string
+	// Algorithms restringe os algoritmos aceitos no header alg
+	Algorithms []string
+	// ClockSkew é a tolerância aplicada a exp, nbf e iat
+	ClockSkew time.Duration
+	// HMACSecret valida tokens HS*
+	HMACSecret string
+	// JWKSFile e JWKSURL são as origens das chaves RS*/ES* (use apenas uma)
+	JWKSFile            string
+	JWKSURL             string
+	JWKSRefreshInterval time.Duration
+	// SigningKeyFile é a chave privada PEM usada para emitir tokens; sua parte pública
+	// é aceita na verificação com o kid SigningKeyID
+	SigningKeyFile string
+	SigningKeyID   string
+}
+
+// Enabled informa se alguma origem de chaves foi configurada
+func (c Config) Enabled() bool {
+	return c.HMACSecret != "" || c.JWKSFile != "" || c.JWKSURL != "" || c.SigningKeyFile != ""
+}
+
+func (c Config) validate() error {
+	if c.JWKSFile != "" && c.JWKSURL != "" {
+		return errors.New("configure either a JWKS file or a JWKS URL, not both")
+	}
+	if c.Issuer == "" || len(c.Audience) == 0 {
+		return errors.New("issuer and audience are required")
+	}
+	if len(c.Algorithms) == 0 {
+		return errors.New("at least one algorithm is required")
+	}
+	if c.HMACSecret != "" && len(c.HMACSecret) < MinHMACSecretLength {
+		return fmt.Errorf("hmac secret must have at least %d bytes", MinHMACSecretLength)
+	}
+
+	hasJWKS := c.JWKSFile != "" || c.JWKSURL != ""
+	for _, alg := range c.Algorithms {
+		if !slices.Contains(SupportedAlgorithms, alg) {
+			return fmt.Errorf("unsupported algorithm %q", alg)
+		}
+		if !isHMAC(alg) && !hasJWKS && c.SigningKeyFile == "" {
+			return fmt.Errorf("algorithm %s requires a JWKS file or URL or a signing key", alg)
+		}
+		if isHMAC(alg) && c.HMACSecret == "" && !hasJWKS {
+			return fmt.Errorf("algorithm %s requires an hmac secret or a JWKS with symmetric keys", alg)
+		}
+	}
+	return nil
+}
+
+// Claims são as claims lidas do token. O escopo vem de "scope" (string separada
+// por espaço, RFC 8693) ou "scp" (lista), conforme o provedor. "sid" identifica a
+// sessão nos tokens emitidos pelo próprio serviço.
+type Claims struct {
+	jwt.RegisteredClaims
+	Name      string   `json:"name,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Scope     string   `json:"scope,omitempty"`
+	Scp       []string `json:"scp,omitempty"`
+	SessionID string   `json:"sid,omitempty"`
+}
+
+// Scopes une as claims scope e scp sem repetições
+func (c *Claims) Scopes() []string {
+	scopes := append(strings.Fields(c.Scope), c.Scp...)
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
+
+// Verifier implementa auth.TokenVerifier
+type Verifier struct {
+	cfg     Config
+	secret  []byte
+	local   map[string]verificationKey
+	jwks    *JWKS
+	enabled bool
+	now     func() time.Time
+}
+
+// NewVerifier valida a configuração e monta o verificador. Sem nenhuma origem de
+// chaves configurada, o verificador recusa todos os bearer tokens.
+func NewVerifier(cfg Config) (*Verifier, error) {
+	v := &Verifier{cfg: cfg, enabled: cfg.Enabled(), now: time.Now}
+	if !v.enabled {
+		return v, nil
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("jwt config: %w", err)
+	}
+
+	refresh := cfg.JWKSRefreshInterval
+	if refresh <= 0 {
+		refresh = defaultRefreshInterval
+	}
+
+	if cfg.HMACSecret != "" {
+		v.secret = []byte(cfg.HMACSecret)
+	}
+	if cfg.SigningKeyFile != "" {
+		alg, key, err := loadSigningKey(cfg.SigningKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("jwt signing key: %w", err)
+		}
+		v.local = map[string]verificationKey{cfg.SigningKeyID: {alg: alg, key: key.Public()}}
+	}
+	switch {
+	case cfg.JWKSFile != "":
+		v.jwks = NewJWKSFile(cfg.JWKSFile, refresh)
+	case cfg.JWKSURL != "":
+		v.jwks = NewJWKSURL(cfg.JWKSURL, refresh, &http.Client{Timeout: jwksFetchTimeout})
+	}
+
+	return v, nil
+}
+
+// Enabled informa se bearer tokens são aceitos
+func (v *Verifier) Enabled() bool {
+	return v.enabled
+}
+
+// VerifyToken confere assinatura, alg, iss, aud, exp e nbf e devolve o principal
+// do token. Falhas ao obter as chaves não são culpa do cliente e são propagadas.
+func (v *Verifier) VerifyToken(ctx context.Context, token string) (*auth.Principal, error) {
+	if !v.enabled {
+		logger.Debug("Bearer token received but JWT authentication is not configured")
+		return nil, auth.ErrInvalidToken
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(v.cfg.Algorithms),
+		jwt.WithIssuer(v.cfg.Issuer),
+		jwt.WithLeeway(v.cfg.ClockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(v.now),
+	)
+
+	var claims Claims
+	var local bool
+	_, err := parser.ParseWithClaims(token, &claims, v.keyFunc(ctx, &local))
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, auth.ErrTokenExpired
+	case errors.Is(err, errJWKSUnavailable):
+		return nil, err
+	case err != nil:
+		logger.Warnf("Invalid bearer token: %v", err)
+		return nil, auth.ErrInvalidToken
+	}
+
+	if !v.acceptsAudience(claims.Audience) {
+		logger.WithField("aud", claims.Audience).Warn("Bearer token issued for another audience")
+		return nil, auth.ErrInvalidToken
+	}
+	if claims.Subject == "" {
+		logger.Warn("Bearer token without subject")
+		return nil, auth.ErrInvalidToken
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = claims.Email
+	}
+
+	// Só tokens do próprio serviço (chave local e sessão de login) são de usuários locais;
+	// um IdP externo pode emitir qualquer sub, inclusive o id de um usuário daqui
+	principal := &auth.Principal{
+		Subject: claims.Subject,
+		Kind:    auth.KindExternal,
+		Name:    name,
+		Scopes:  claims.Scopes(),
+	}
+	if local && claims.SessionID != "" {
+		principal.Kind = auth.KindUser
+		principal.SessionID = claims.SessionID
+	}
+	return principal, nil
+}
+
+// keyFunc escolhe a chave do token e marca em local se ela é uma das usadas pelo Signer
+func (v *Verifier) keyFunc(ctx context.Context, local *bool) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		alg := token.Method.Alg()
+		if isHMAC(alg) && v.secret != nil {
+			// O Signer usa o segredo HMAC apenas quando não há chave de assinatura
+			*local = v.cfg.SigningKeyFile == ""
+			return v.secret, nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.local[kid]; ok {
+			if key.alg != alg {
+				return nil, errKeyAlgMismatch
+			}
+			*local = true
+			return key.key, nil
+		}
+		if v.jwks == nil {
+			return nil, errNoKeySource
+		}
+
+		keyAlg, key, err := v.jwks.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if keyAlg != "" && keyAlg != alg {
+			return nil, errKeyAlgMismatch
+		}
+		return key, nil
+	}
+}
+
+func (v *Verifier) acceptsAudience(audience jwt.ClaimStrings) bool {
+	for _, aud := range audience {
+		if slices.Contains(v.cfg.Audience, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHMAC(alg string) bool {
+	return strings.HasPrefix(alg, "HS")
+}
+
+var _ auth.TokenVerifier = (*Verifier)(nil)
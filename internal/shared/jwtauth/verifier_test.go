@@ -0,0 +1,374 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "modular-monolith"
+)
+
+var testSecret = strings.Repeat("s", MinHMACSecretLength)
+
+func claimsAt(now time.Time) Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    testIssuer,
+			Audience:  jwt.ClaimStrings{testAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Name:  "Ana",
+		Scope: "users:read users:write",
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "alg": "RS256", "use": "sig",
+		"n": encode(key.N.Bytes()),
+		"e": encode(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "EC", "kid": kid, "alg": "ES256", "crv": "P-256",
+		"x": encode(key.X.FillBytes(make([]byte, 32))),
+		"y": encode(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]string) {
+	t.Helper()
+
+	data, _ := json.Marshal(map[string]any{"keys": keys})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write JWKS: %v", err)
+	}
+}
+
+func newTestVerifier(t *testing.T, cfg Config, now time.Time) *Verifier {
+	t.Helper()
+
+	cfg.Issuer = testIssuer
+	cfg.Audience = []string{testAudience}
+	verifier, err := NewVerifier(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected config error: %v", err)
+	}
+
+	verifier.now = func() time.Time { return now }
+	if verifier.jwks != nil {
+		verifier.jwks.now = verifier.now
+	}
+	return verifier
+}
+
+func TestVerifyTokenHMAC(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	verifier := newTestVerifier(t, Config{Algorithms: []string{"HS256"}, HMACSecret: testSecret, ClockSkew: 30 * time.Second}, now)
+
+	t.Run("Valid token maps claims to a principal", func(t *testing.T) {
+		claims := claimsAt(now)
+		claims.SessionID = "session-1"
+		principal, err := verifier.VerifyToken(ctx, sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if principal.Subject != "user-1" || principal.Kind != auth.KindUser || principal.Name != "Ana" || principal.SessionID != "session-1" {
+			t.Errorf("Unexpected principal: %+v", principal)
+		}
+		if !principal.HasScope("users:write") {
+			t.Errorf("Expected scopes from the scope claim, got %v", principal.Scopes)
+		}
+	})
+
+	t.Run("Token without a session is external", func(t *testing.T) {
+		principal, err := verifier.VerifyToken(ctx, sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claimsAt(now)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if principal.Kind != auth.KindExternal {
+			t.Errorf("Expected kind %s, got %s", auth.KindExternal, principal.Kind)
+		}
+	})
+
+	t.Run("Clock skew is tolerated", func(t *testing.T) {
+		claims := claimsAt(now)
+		claims.NotBefore = jwt.NewNumericDate(now.Add(20 * time.Second))
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(-20 * time.Second))
+
+		if _, err := verifier.VerifyToken(ctx, sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims)); err != nil {
+			t.Errorf("Expected token within clock skew to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		claims := claimsAt(now.Add(-2 * time.Hour))
+
+		_, err := verifier.VerifyToken(ctx, sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+		if !errors.Is(err, auth.ErrTokenExpired) {
+			t.Errorf("Expected ErrTokenExpired, got %v", err)
+		}
+	})
+
+	t.Run("Rejected tokens", func(t *testing.T) {
+		future := claimsAt(now)
+		future.NotBefore = jwt.NewNumericDate(now.Add(time.Hour))
+
+		otherIssuer := claimsAt(now)
+		otherIssuer.Issuer = "https://evil.example.com"
+
+		otherAudience := claimsAt(now)
+		otherAudience.Audience = jwt.ClaimStrings{"another-service"}
+
+		noSubject := claimsAt(now)
+		noSubject.Subject = ""
+
+		noExpiry := claimsAt(now)
+		noExpiry.ExpiresAt = nil
+
+		unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claimsAt(now)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+		tokens := map[string]string{
+			"not yet valid":  sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), future),
+			"other issuer":   sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), otherIssuer),
+			"other audience": sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), otherAudience),
+			"no subject":     sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), noSubject),
+			"no expiry":      sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), noExpiry),
+			"wrong secret":   sign(t, jwt.SigningMethodHS256, "", []byte(testSecret+"x"), claimsAt(now)),
+			"disallowed alg": sign(t, jwt.SigningMethodHS512, "", []byte(testSecret), claimsAt(now)),
+			"alg none":       unsigned,
+			"garbage":        "not-a-jwt",
+		}
+		for name, token := range tokens {
+			if _, err := verifier.VerifyToken(ctx, token); !errors.Is(err, auth.ErrInvalidToken) {
+				t.Errorf("Expected ErrInvalidToken for %s, got %v", name, err)
+			}
+		}
+	})
+}
+
+func TestVerifyTokenJWKS(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	writeJWKS(t, path, rsaJWK("rsa-1", rsaKey), ecJWK("ec-1", ecKey))
+
+	verifier := newTestVerifier(t, Config{
+		Algorithms:          []string{"RS256", "ES256"},
+		JWKSFile:            path,
+		JWKSRefreshInterval: time.Hour,
+	}, now)
+
+	t.Run("RS256 and ES256 keys are selected by kid", func(t *testing.T) {
+		if _, err := verifier.VerifyToken(ctx, sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claimsAt(now))); err != nil {
+			t.Errorf("Expected RS256 token to be valid, got %v", err)
+		}
+		if _, err := verifier.VerifyToken(ctx, sign(t, jwt.SigningMethodES256, "ec-1", ecKey, claimsAt(now))); err != nil {
+			t.Errorf("Expected ES256 token to be valid, got %v", err)
+		}
+	})
+
+	t.Run("JWKS tokens are external even with a session", func(t *testing.T) {
+		claims := claimsAt(now)
+		claims.SessionID = "session-1"
+		principal, err := verifier.VerifyToken(ctx, sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if principal.Kind != auth.KindExternal || principal.SessionID != "" {
+			t.Errorf("Expected an external principal without session, got %+v", principal)
+		}
+	})
+
+	t.Run("Key of another algorithm is rejected", func(t *testing.T) {
+		_, err := verifier.VerifyToken(ctx, sign(t, jwt.SigningMethodES256, "rsa-1", ecKey, claimsAt(now)))
+		if !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("Rotated key is picked up", func(t *testing.T) {
+		rotated, _ := rsa.GenerateKey(rand.Reader, 2048)
+		writeJWKS(t, path, rsaJWK("rsa-2", rotated))
+		token := sign(t, jwt.SigningMethodRS256, "rsa-2", rotated, claimsAt(now))
+
+		if _, err := verifier.VerifyToken(ctx, token); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("Expected unknown kid to be rejected before the minimum refresh interval, got %v", err)
+		}
+
+		verifier.now = func() time.Time { return now.Add(2 * time.Minute) }
+		verifier.jwks.now = verifier.now
+		if _, err := verifier.VerifyToken(ctx, token); err != nil {
+			t.Errorf("Expected rotated key to be loaded, got %v", err)
+		}
+	})
+
+	t.Run("Unavailable JWKS is not reported as an invalid token", func(t *testing.T) {
+		missing := newTestVerifier(t, Config{
+			Algorithms: []string{"RS256"},
+			JWKSFile:   filepath.Join(t.TempDir(), "missing.json"),
+		}, now)
+
+		_, err := missing.VerifyToken(ctx, sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claimsAt(now)))
+		if err == nil || errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("Expected infrastructure error, got %v", err)
+		}
+	})
+}
+
+func TestJWKSRefresh(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	data, _ := json.Marshal(map[string]any{"keys": []map[string]string{rsaJWK("rsa-1", rsaKey)}})
+	now := time.Now()
+
+	// newSource cria um JWKS cuja origem trava a partir da consulta blockFrom até release ser fechado
+	newSource := func(blockFrom int32) (*JWKS, chan struct{}, *atomic.Int32) {
+		release := make(chan struct{})
+		fetches := &atomic.Int32{}
+		jwks := newJWKS("test", time.Hour, func(ctx context.Context) ([]byte, error) {
+			if fetches.Add(1) >= blockFrom {
+				<-release
+			}
+			return data, nil
+		})
+		jwks.now = func() time.Time { return now }
+		return jwks, release, fetches
+	}
+
+	t.Run("Concurrent first loads share one fetch", func(t *testing.T) {
+		jwks, release, fetches := newSource(1)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, err := jwks.Key(context.Background(), "rsa-1")
+				errs <- err
+			}()
+		}
+		close(release)
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Errorf("Expected key to be found, got %v", err)
+			}
+		}
+		if fetches.Load() != 1 {
+			t.Errorf("Expected 1 fetch, got %d", fetches.Load())
+		}
+	})
+
+	t.Run("Cached keys are served while the set reloads", func(t *testing.T) {
+		jwks, release, _ := newSource(2)
+		defer close(release)
+
+		if _, _, err := jwks.Key(context.Background(), "rsa-1"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		jwks.now = func() time.Time { return now.Add(2 * time.Hour) }
+		result := make(chan error, 1)
+		go func() {
+			_, _, err := jwks.Key(context.Background(), "rsa-1")
+			result <- err
+		}()
+
+		select {
+		case err := <-result:
+			if err != nil {
+				t.Errorf("Expected cached key, got %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Expected cached key while the refresh is blocked")
+		}
+	})
+
+	t.Run("Waiting caller gives up with its context", func(t *testing.T) {
+		jwks, release, _ := newSource(1)
+		defer close(release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, _, err := jwks.Key(ctx, "rsa-1"); !errors.Is(err, errJWKSUnavailable) || !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected canceled JWKS error, got %v", err)
+		}
+	})
+}
+
+func TestNewVerifier(t *testing.T) {
+	t.Run("Disabled verifier rejects every token", func(t *testing.T) {
+		verifier, err := NewVerifier(Config{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := verifier.VerifyToken(context.Background(), "anything"); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	invalid := map[string]Config{
+		"missing audience":  {Issuer: testIssuer, Algorithms: []string{"HS256"}, HMACSecret: testSecret},
+		"short secret":      {Issuer: testIssuer, Audience: []string{testAudience}, Algorithms: []string{"HS256"}, HMACSecret: "short"},
+		"rsa without jwks":  {Issuer: testIssuer, Audience: []string{testAudience}, Algorithms: []string{"RS256"}, HMACSecret: testSecret},
+		"unknown algorithm": {Issuer: testIssuer, Audience: []string{testAudience}, Algorithms: []string{"none"}, HMACSecret: testSecret},
+		"file and url jwks": {Issuer: testIssuer, Audience: []string{testAudience}, Algorithms: []string{"RS256"}, JWKSFile: "a", JWKSURL: "b"},
+	}
+	for name, cfg := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewVerifier(cfg); err == nil {
+				t.Error("Expected config error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package mail define o envio de e-mails transacionais pelos módulos. Os casos de uso
+// dependem apenas de Sender; os envios de desenvolvimento escrevem a mensagem na saída
+// padrão ou em arquivos, e um provedor real (SMTP, API) basta implementar Sender.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DriverStdout = "stdout"
+	DriverFile   = "file"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender entrega mensagens; implementações precisam ser seguras para uso concorrente
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type Config struct {
+	// Driver escolhe o envio: "stdout" (padrão) ou "file"
+	Driver string
+	// From é o remetente de todas as mensagens
+	From string
+	// Dir é o diretório das mensagens do driver "file"
+	Dir string
+	// Debug libera o driver "stdout", que escreve os links de confirmação no log
+	Debug bool
+}
+
+// NewSender cria o Sender do driver configurado
+func NewSender(cfg Config) (Sender, error) {
+	switch cfg.Driver {
+	case "", DriverStdout:
+		if !cfg.Debug {
+			return nil, fmt.Errorf("mail driver %s is only allowed in debug mode", DriverStdout)
+		}
+		return NewWriterSender(os.Stdout, cfg.From), nil
+	case DriverFile:
+		if cfg.Dir == "" {
+			return nil, fmt.Errorf("mail directory is required for the %s driver", DriverFile)
+		}
+		if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create mail directory: %w", err)
+		}
+		return NewFileSender(cfg.Dir, cfg.From), nil
+	}
+	return nil, fmt.Errorf("unsupported mail driver %q", cfg.Driver)
+}
+
+// WriterSender escreve cada mensagem em w; útil em desenvolvimento
+type WriterSender struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+func NewWriterSender(w io.Writer, from string) *WriterSender {
+	return &WriterSender{w: w, from: from}
+}
+
+func (s *WriterSender) Send(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := io.WriteString(s.w, format(s.from, msg, time.Now())+"\n")
+	return err
+}
+
+// FileSender grava cada mensagem em um arquivo .eml do diretório, para inspeção local
+type FileSender struct {
+	dir  string
+	from string
+}
+
+func NewFileSender(dir, from string) *FileSender {
+	return &FileSender{dir: dir, from: from}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%d-%s.eml", now.UnixNano(), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	return os.WriteFile(filepath.Join(s.dir, name), []byte(format(s.from, msg, now)), 0o640)
+}
+
+// format monta a mensagem no formato RFC 5322, sem codificação de cabeçalhos
+func format(from string, msg Message, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+	return b.String()
+}
+
+var (
+	_ Sender = (*WriterSender)(nil)
+	_ Sender = (*FileSender)(nil)
+)
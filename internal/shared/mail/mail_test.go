@@ -0,0 +1,56 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriterSender(t *testing.T) {
+	var out bytes.Buffer
+	sender := NewWriterSender(&out, "no-reply@example.com")
+
+	if err := sender.Send(context.Background(), Message{To: "ana@example.com", Subject: "Hello", Body: "Body"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"From: no-reply@example.com", "To: ana@example.com", "Subject: Hello", "\r\n\r\nBody"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in %q", want, out.String())
+		}
+	}
+}
+
+func TestFileSender(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := NewSender(Config{Driver: DriverFile, Dir: dir, From: "no-reply@example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := sender.Send(context.Background(), Message{To: "ana@example.com", Subject: "Hello", Body: "Body"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || !strings.HasSuffix(entries[0].Name(), "-ana_example.com.eml") {
+		t.Fatalf("Expected one message file, got %v", entries)
+	}
+}
+
+func TestNewSender(t *testing.T) {
+	if _, err := NewSender(Config{Driver: "smtp"}); err == nil {
+		t.Error("Expected error for unsupported driver")
+	}
+	if _, err := NewSender(Config{Driver: DriverFile}); err == nil {
+		t.Error("Expected error for file driver without directory")
+	}
+	if _, err := NewSender(Config{Driver: DriverStdout}); err == nil {
+		t.Error("Expected error for stdout driver outside debug mode")
+	}
+	if _, err := NewSender(Config{Driver: DriverStdout, Debug: true}); err != nil {
+		t.Errorf("Expected stdout driver in debug mode, got %v", err)
+	}
+}
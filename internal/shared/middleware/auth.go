@@ -2,64 +2,148 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/problem"
 	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
 )
 
 const (
-	HeaderAPIKey   = "X-API-Key"
-	RequiredAPIKey = "api-key-exemplo"
+	HeaderAPIKey        = "X-API-Key"
+	HeaderAuthorization = "Authorization"
+
+	bearerScheme = "Bearer"
 )
 
-// ValidateAPIKey middleware que valida o header X-API-Key
-func ValidateAPIKey() gin.HandlerFunc {
+var ErrMissingCredentials = apperror.Unauthorized("MISSING_CREDENTIALS", "a bearer token or an API key is required")
+
+// Authenticate middleware que aceita um bearer token (header Authorization) ou uma
+// API key (X-API-Key) e guarda o principal resultante no contexto. Se os dois forem
+// enviados, vale o bearer token.
+func Authenticate(keys auth.APIKeyResolver, tokens auth.TokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader(HeaderAPIKey)
-
-		if apiKey == "" {
-			logger.Warn("API Key missing in request")
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "API Key é obrigatório",
-				"code":  "MISSING_API_KEY",
-			})
-			c.Abort()
+		principal, err := authenticate(c, keys, tokens)
+		if err != nil {
+			abortUnauthorized(c, err)
 			return
 		}
+		if principal == nil {
+			logger.Warn("Credentials missing in request")
+			abortUnauthorized(c, ErrMissingCredentials)
+			return
+		}
+
+		auth.SetPrincipal(c, principal)
+		c.Next()
+	}
+}
 
-		if apiKey != RequiredAPIKey {
-			logger.WithField("provided_key", apiKey).Warn("Invalid API Key provided")
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "API Key inválido",
-				"code":  "INVALID_API_KEY",
-			})
-			c.Abort()
+// OptionalAuthenticate middleware que valida as credenciais apenas se fornecidas
+func OptionalAuthenticate(keys auth.APIKeyResolver, tokens auth.TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := authenticate(c, keys, tokens)
+		if err != nil {
+			abortUnauthorized(c, err)
 			return
 		}
 
-		logger.Debug("API Key validated successfully")
+		if principal != nil {
+			auth.SetPrincipal(c, principal)
+		}
 		c.Next()
 	}
 }
 
-// OptionalAPIKey middleware que valida API Key apenas se fornecido
-func OptionalAPIKey() gin.HandlerFunc {
+// authenticate resolve a credencial presente na requisição; sem nenhuma, retorna (nil, nil)
+func authenticate(c *gin.Context, keys auth.APIKeyResolver, tokens auth.TokenVerifier) (*auth.Principal, error) {
+	if header := c.GetHeader(HeaderAuthorization); header != "" {
+		scheme, token, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, bearerScheme) || strings.TrimSpace(token) == "" {
+			return nil, auth.ErrInvalidToken.WithMessage("authorization header must use the Bearer scheme")
+		}
+
+		principal, err := tokens.VerifyToken(c.Request.Context(), strings.TrimSpace(token))
+		if err != nil {
+			return nil, err
+		}
+		logger.WithField("subject", principal.Subject).Debug("Bearer token validated successfully")
+		return principal, nil
+	}
+
+	if apiKey := c.GetHeader(HeaderAPIKey); apiKey != "" {
+		principal, err := keys.ResolveAPIKey(c.Request.Context(), apiKey)
+		if err != nil {
+			return nil, err
+		}
+		logger.WithField("api_key_id", principal.Subject).Debug("API Key validated successfully")
+		return principal, nil
+	}
+
+	return nil, nil
+}
+
+// abortUnauthorized anuncia o esquema Bearer nas respostas 401 (RFC 6750)
+func abortUnauthorized(c *gin.Context, err error) {
+	if problem.StatusOf(err) == http.StatusUnauthorized {
+		c.Header("WWW-Authenticate", bearerScheme)
+	}
+	problem.Abort(c, err)
+}
+
+// RequireScopes middleware que exige todos os escopos informados do principal já autenticado.
+// Deve vir depois de Authenticate; a resposta 403 lista os escopos que faltam.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			problem.Abort(c, auth.ErrUnauthenticated)
+			return
+		}
+
+		missing := principal.MissingScopes(scopes...)
+		if len(missing) > 0 {
+			logger.WithField("subject", principal.Subject).
+				WithField("missing_scopes", missing).
+				Warn("Request denied: insufficient scope")
+			problem.Abort(c, auth.ErrInsufficientScope.WithMessage("missing required scope: "+strings.Join(missing, ", ")))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission middleware que consulta o authorizer para cada permissão exigida do
+// principal já autenticado. Diferente de RequireScopes, considera também os papéis do usuário.
+func RequirePermission(authorizer auth.Authorizer, permissions ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader(HeaderAPIKey)
-
-		if apiKey != "" && apiKey != RequiredAPIKey {
-			logger.WithField("provided_key", apiKey).Warn("Invalid optional API Key provided")
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "API Key fornecido é inválido",
-				"code":  "INVALID_API_KEY",
-			})
-			c.Abort()
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			problem.Abort(c, auth.ErrUnauthenticated)
 			return
 		}
 
-		if apiKey == RequiredAPIKey {
-			logger.Debug("Optional API Key validated successfully")
-			c.Set("authenticated", true)
+		var missing []string
+		for _, permission := range permissions {
+			allowed, err := authorizer.Can(c.Request.Context(), principal, permission)
+			if err != nil {
+				problem.Abort(c, err)
+				return
+			}
+			if !allowed {
+				missing = append(missing, permission)
+			}
+		}
+
+		if len(missing) > 0 {
+			logger.WithField("subject", principal.Subject).
+				WithField("missing_permissions", missing).
+				Warn("Request denied: permission denied")
+			problem.Abort(c, auth.ErrPermissionDenied.WithMessage("missing required permission: "+strings.Join(missing, ", ")))
+			return
 		}
 
 		c.Next()
@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/problem"
+)
+
+func serveWithScopes(principal *auth.Principal, scopes ...string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		if principal != nil {
+			auth.SetPrincipal(c, principal)
+		}
+		c.Next()
+	}, RequireScopes(scopes...), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	return recorder
+}
+
+func TestRequireScopes(t *testing.T) {
+	readOnly := &auth.Principal{Subject: "key-1", Kind: auth.KindAPIKey, Scopes: []string{"users:read"}}
+	admin := &auth.Principal{Subject: "key-2", Kind: auth.KindAPIKey, Scopes: []string{"users:admin"}}
+
+	t.Run("Granted scope passes", func(t *testing.T) {
+		if recorder := serveWithScopes(readOnly, "users:read"); recorder.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Admin scope grants the whole resource", func(t *testing.T) {
+		if recorder := serveWithScopes(admin, "users:read", "users:write"); recorder.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", recorder.Code)
+		}
+		if recorder := serveWithScopes(admin, "api_keys:admin"); recorder.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for another resource, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Missing scope is named in the 403", func(t *testing.T) {
+		recorder := serveWithScopes(readOnly, "users:read", "users:write")
+		if recorder.Code != http.StatusForbidden {
+			t.Fatalf("Expected status 403, got %d", recorder.Code)
+		}
+
+		var body problem.Problem
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Unexpected body: %v", err)
+		}
+		if body.Code != "INSUFFICIENT_SCOPE" || body.Detail != "missing required scope: users:write" {
+			t.Errorf("Unexpected problem: %+v", body)
+		}
+	})
+
+	t.Run("Unauthenticated request", func(t *testing.T) {
+		if recorder := serveWithScopes(nil, "users:read"); recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", recorder.Code)
+		}
+	})
+}
+
+type fakeCredentials struct{}
+
+func (fakeCredentials) ResolveAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	if key != "valid-key" {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	return &auth.Principal{Subject: "key-1", Kind: auth.KindAPIKey}, nil
+}
+
+func (fakeCredentials) VerifyToken(ctx context.Context, token string) (*auth.Principal, error) {
+	if token != "valid-token" {
+		return nil, auth.ErrInvalidToken
+	}
+	return &auth.Principal{Subject: "user-1", Kind: auth.KindUser}, nil
+}
+
+func serveAuthenticated(headers map[string]string) (*httptest.ResponseRecorder, string) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	var kind string
+	router.GET("/", Authenticate(fakeCredentials{}, fakeCredentials{}), func(c *gin.Context) {
+		principal, _ := auth.PrincipalFrom(c)
+		kind = principal.Kind
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder, kind
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Run("Accepts either credential, bearer first", func(t *testing.T) {
+		if _, kind := serveAuthenticated(map[string]string{HeaderAPIKey: "valid-key"}); kind != auth.KindAPIKey {
+			t.Errorf("Expected API key principal, got %q", kind)
+		}
+		if _, kind := serveAuthenticated(map[string]string{HeaderAuthorization: "bearer valid-token", HeaderAPIKey: "valid-key"}); kind != auth.KindUser {
+			t.Errorf("Expected token principal, got %q", kind)
+		}
+	})
+
+	t.Run("Rejections announce the Bearer scheme", func(t *testing.T) {
+		cases := map[string]map[string]string{
+			"no credentials": {},
+			"basic scheme":   {HeaderAuthorization: "Basic dXNlcjpwYXNz"},
+			"invalid token":  {HeaderAuthorization: "Bearer forged"},
+		}
+		for name, headers := range cases {
+			recorder, _ := serveAuthenticated(headers)
+			if recorder.Code != http.StatusUnauthorized || recorder.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("Expected 401 with WWW-Authenticate for %s, got %d %q", name, recorder.Code, recorder.Header().Get("WWW-Authenticate"))
+			}
+		}
+	})
+}
+
+// MockAuthorizer concede as permissões listadas por subject
+type MockAuthorizer struct {
+	Permissions map[string][]string
+	Err         error
+}
+
+func (m MockAuthorizer) Can(ctx context.Context, principal *auth.Principal, permission string) (bool, error) {
+	return auth.Grants(m.Permissions[principal.Subject], permission), m.Err
+}
+
+func TestRequirePermission(t *testing.T) {
+	authorizer := MockAuthorizer{Permissions: map[string][]string{"user-1": {"users:admin"}}}
+
+	serve := func(authorizer auth.Authorizer, principal *auth.Principal, permissions ...string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET("/", func(c *gin.Context) {
+			if principal != nil {
+				auth.SetPrincipal(c, principal)
+			}
+			c.Next()
+		}, RequirePermission(authorizer, permissions...), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder
+	}
+
+	t.Run("Granted permission passes", func(t *testing.T) {
+		if recorder := serve(authorizer, &auth.Principal{Subject: "user-1"}, "users:write"); recorder.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Missing permission is named in the 403", func(t *testing.T) {
+		recorder := serve(authorizer, &auth.Principal{Subject: "user-2"}, "users:write")
+		if recorder.Code != http.StatusForbidden {
+			t.Fatalf("Expected status 403, got %d", recorder.Code)
+		}
+
+		var body problem.Problem
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Unexpected body: %v", err)
+		}
+		if body.Code != "PERMISSION_DENIED" || body.Detail != "missing required permission: users:write" {
+			t.Errorf("Unexpected problem: %+v", body)
+		}
+	})
+
+	t.Run("Authorizer failure", func(t *testing.T) {
+		failing := MockAuthorizer{Err: errors.New("database down")}
+		if recorder := serve(failing, &auth.Principal{Subject: "user-1"}, "users:write"); recorder.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Unauthenticated request", func(t *testing.T) {
+		if recorder := serve(authorizer, nil, "users:write"); recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", recorder.Code)
+		}
+	})
+}
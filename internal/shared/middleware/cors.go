@@ -6,6 +6,7 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/vynazevedo/go-modular-monolith/internal/shared/config"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/etag"
 )
 
 func CORS(cfg *config.Config) gin.HandlerFunc {
@@ -13,7 +14,7 @@ func CORS(cfg *config.Config) gin.HandlerFunc {
 		AllowOrigins:     cfg.CORS.AllowedOrigins,
 		AllowMethods:     cfg.CORS.AllowedMethods,
 		AllowHeaders:     cfg.CORS.AllowedHeaders,
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", HeaderRetryAfter, HeaderRateLimitLimit, HeaderRateLimitRemaining, HeaderRateLimitReset, HeaderRateLimitPolicy, HeaderIdempotencyReplayed, etag.HeaderETag},
 		AllowCredentials: true,
 		MaxAge:           time.Duration(cfg.CORS.MaxAge) * time.Second,
 	}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/idempotency"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/problem"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotency-Replayed"
+
+	// maxIdempotencyKeyLength segue o limite usual dos provedores (UUIDs cabem com folga)
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize limita o corpo lido em memória para calcular o hash da requisição
+	maxIdempotentBodySize = 1 << 20
+)
+
+var (
+	ErrInvalidIdempotencyKey    = apperror.BadRequest("INVALID_IDEMPOTENCY_KEY", "the Idempotency-Key header must have 1 to 255 characters")
+	ErrIdempotencyKeyReused     = apperror.Validation("IDEMPOTENCY_KEY_REUSED", "the Idempotency-Key was already used with a different request body")
+	ErrIdempotencyKeyInProgress = apperror.Conflict("IDEMPOTENCY_KEY_IN_PROGRESS", "a request with this Idempotency-Key is still being processed")
+	ErrRequestBodyTooLarge      = apperror.PayloadTooLarge("REQUEST_BODY_TOO_LARGE", "the request body must be at most 1 MiB")
+)
+
+// replayedHeaders são os headers da resposta original repetidos no replay
+var replayedHeaders = []string{"Content-Type", "Location", "ETag", "Cache-Control"}
+
+// Idempotency middleware que, nas requisições que modificam estado e trazem o header
+// Idempotency-Key, grava a primeira resposta por chave, principal e rota e a repete nas
+// retentativas. A mesma chave com outro corpo recebe 422 IDEMPOTENCY_KEY_REUSED e, enquanto
+// a primeira requisição não termina, 409 IDEMPOTENCY_KEY_IN_PROGRESS. Falhas que não dizem
+// respeito à requisição (ver storable) não são gravadas, para que a retentativa execute de
+// novo. A reserva em andamento vale por lease: se a requisição original não terminar
+// nesse prazo, uma retentativa assume a chave. Precisa vir depois da autenticação.
+func Idempotency(store idempotency.Store, ttl, lease time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := c.Request.Header[HeaderIdempotencyKey]
+		if store == nil || !ok || !modifiesState(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key[0]) == 0 || len(key[0]) > maxIdempotencyKeyLength {
+			problem.Abort(c, ErrInvalidIdempotencyKey)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				err = ErrRequestBodyTooLarge
+			}
+			problem.Abort(c, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := &idempotency.Record{
+			ID:          recordID(c, key[0]),
+			RequestHash: hashOf(body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+			LockedUntil: now.Add(lease),
+		}
+
+		ctx := c.Request.Context()
+		existing, err := store.Reserve(ctx, record)
+		if err != nil {
+			problem.Abort(c, err)
+			return
+		}
+		if existing != nil {
+			replay(c, existing, record.RequestHash)
+			return
+		}
+
+		// Um panic no handler libera a chave antes de chegar ao gin.Recovery
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				_ = store.Release(context.WithoutCancel(ctx), record)
+				panic(recovered)
+			}
+		}()
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// A requisição já terminou; a gravação não deve ser cancelada junto com ela
+		ctx = context.WithoutCancel(ctx)
+		if !storable(writer.Status()) {
+			if err := store.Release(ctx, record); err != nil {
+				logger.Warnf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+
+		record.StatusCode = writer.Status()
+		record.Body = writer.body.Bytes()
+		record.Headers = make(map[string]string)
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				record.Headers[name] = value
+			}
+		}
+		if err := store.Complete(ctx, record); err != nil {
+			// Sem a resposta gravada, a chave ficaria em andamento até o fim do lease
+			logger.Warnf("Failed to store idempotent response: %v", err)
+			if err := store.Release(ctx, record); err != nil {
+				logger.Warnf("Failed to release idempotency key: %v", err)
+			}
+		}
+	}
+}
+
+func replay(c *gin.Context, existing *idempotency.Record, requestHash string) {
+	switch {
+	case existing.RequestHash != requestHash:
+		problem.Abort(c, ErrIdempotencyKeyReused)
+	case !existing.Completed():
+		c.Header(HeaderRetryAfter, "1")
+		problem.Abort(c, ErrIdempotencyKeyInProgress)
+	default:
+		for name, value := range existing.Headers {
+			c.Header(name, value)
+		}
+		c.Header(HeaderIdempotencyReplayed, "true")
+		c.Status(existing.StatusCode)
+		_, _ = c.Writer.Write(existing.Body)
+		c.Abort()
+	}
+}
+
+// recordID combina a chave com o principal, o método e o caminho: a mesma chave enviada
+// por outro cliente ou para outro recurso é independente
+func recordID(c *gin.Context, key string) string {
+	subject := "anonymous"
+	if principal, ok := auth.PrincipalFrom(c); ok {
+		subject = principal.Kind + ":" + principal.Subject
+	}
+	return hashOf([]byte(subject + "\n" + c.Request.Method + " " + c.Request.URL.Path + "\n" + key))
+}
+
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// storable informa se a resposta é gravada: erros internos e recusas anteriores à execução
+// (permissão, limite de requisições) liberam a chave para uma nova tentativa
+func storable(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+func modifiesState(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// capturingWriter copia o corpo da resposta enquanto o escreve
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/idempotency"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func newIdempotentRouter(t *testing.T, status int) (*gin.Engine, *int) {
+	t.Helper()
+	return newIdempotentRouterWith(t, newIdempotencyStore(t), status)
+}
+
+func newIdempotencyStore(t *testing.T) *idempotency.GormStore {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "idempotency.db")), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&idempotency.KeyModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	return idempotency.NewGormStore(db)
+}
+
+func newIdempotentRouterWith(t *testing.T, store idempotency.Store, status int) (*gin.Engine, *int) {
+	t.Helper()
+
+	calls := 0
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/users", func(c *gin.Context) {
+		if subject := c.GetHeader(HeaderAPIKey); subject != "" {
+			auth.SetPrincipal(c, &auth.Principal{Subject: subject, Kind: auth.KindAPIKey})
+		}
+		c.Next()
+	}, Idempotency(store, time.Hour, time.Minute), func(c *gin.Context) {
+		calls++
+		c.Header("Location", "/users/1")
+		c.JSON(status, gin.H{"call": calls})
+	})
+	return router, &calls
+}
+
+// failingCompleteStore simula uma falha ao gravar a resposta
+type failingCompleteStore struct {
+	idempotency.Store
+}
+
+func (failingCompleteStore) Complete(ctx context.Context, record *idempotency.Record) error {
+	return errors.New("database unavailable")
+}
+
+func post(router *gin.Engine, key, body string, headers ...string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	if key != "" {
+		request.Header.Set(HeaderIdempotencyKey, key)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		request.Header.Set(headers[i], headers[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("Retry replays the first response", func(t *testing.T) {
+		router, calls := newIdempotentRouter(t, http.StatusCreated)
+
+		first := post(router, "key-1", `{"name":"Ana"}`)
+		retry := post(router, "key-1", `{"name":"Ana"}`)
+
+		if *calls != 1 {
+			t.Fatalf("Expected the handler to run once, got %d", *calls)
+		}
+		if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+			t.Errorf("Expected the same response, got %d %s", retry.Code, retry.Body.String())
+		}
+		if retry.Header().Get("Location") != "/users/1" || retry.Header().Get(HeaderIdempotencyReplayed) != "true" {
+			t.Errorf("Unexpected replay headers: %v", retry.Header())
+		}
+	})
+
+	t.Run("Same key with another body is rejected", func(t *testing.T) {
+		router, _ := newIdempotentRouter(t, http.StatusCreated)
+		post(router, "key-1", `{"name":"Ana"}`)
+
+		if recorder := post(router, "key-1", `{"name":"Bia"}`); recorder.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status 422, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Keys are scoped by principal", func(t *testing.T) {
+		router, calls := newIdempotentRouter(t, http.StatusCreated)
+		post(router, "key-1", `{}`, HeaderAPIKey, "client-a")
+		post(router, "key-1", `{}`, HeaderAPIKey, "client-b")
+
+		if *calls != 2 {
+			t.Errorf("Expected each principal to run the handler, got %d", *calls)
+		}
+	})
+
+	t.Run("Server errors are not stored", func(t *testing.T) {
+		router, calls := newIdempotentRouter(t, http.StatusInternalServerError)
+		post(router, "key-1", `{}`)
+		post(router, "key-1", `{}`)
+
+		if *calls != 2 {
+			t.Errorf("Expected the retry to run again, got %d calls", *calls)
+		}
+	})
+
+	t.Run("Requests without the header are not tracked", func(t *testing.T) {
+		router, calls := newIdempotentRouter(t, http.StatusCreated)
+		post(router, "", `{}`)
+		post(router, "", `{}`)
+
+		if *calls != 2 {
+			t.Errorf("Expected 2 calls, got %d", *calls)
+		}
+	})
+
+	t.Run("Failed store releases the key", func(t *testing.T) {
+		router, calls := newIdempotentRouterWith(t, failingCompleteStore{newIdempotencyStore(t)}, http.StatusCreated)
+		post(router, "key-1", `{}`)
+
+		if recorder := post(router, "key-1", `{}`); recorder.Code != http.StatusCreated || *calls != 2 {
+			t.Errorf("Expected the retry to run again instead of waiting for the lease, got %d after %d calls", recorder.Code, *calls)
+		}
+	})
+
+	t.Run("Oversized body", func(t *testing.T) {
+		router, calls := newIdempotentRouter(t, http.StatusCreated)
+
+		recorder := post(router, "key-1", `{"name":"`+strings.Repeat("a", maxIdempotentBodySize)+`"}`)
+		if recorder.Code != http.StatusRequestEntityTooLarge || *calls != 0 {
+			t.Errorf("Expected status 413 without running the handler, got %d after %d calls", recorder.Code, *calls)
+		}
+	})
+
+	t.Run("Oversized key", func(t *testing.T) {
+		router, _ := newIdempotentRouter(t, http.StatusCreated)
+
+		if recorder := post(router, strings.Repeat("k", 256), `{}`); recorder.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", recorder.Code)
+		}
+	})
+}
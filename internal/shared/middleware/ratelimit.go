@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/problem"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+// Headers de limite (draft IETF "RateLimit header fields for HTTP")
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+var ErrRateLimited = apperror.RateLimited("RATE_LIMITED", "too many requests, try again later")
+
+// RateKey extrai a chave que identifica o cliente na política
+type RateKey func(c *gin.Context) string
+
+// ByClientIP identifica o cliente pelo IP. Só os proxies de TRUSTED_PROXIES podem
+// informar o IP original em X-Forwarded-For (gin.Engine.SetTrustedProxies).
+func ByClientIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByPrincipal identifica o cliente pela credencial autenticada (API key ou usuário) e,
+// sem ela, pelo IP. Precisa vir depois do middleware de autenticação.
+func ByPrincipal(c *gin.Context) string {
+	if principal, ok := auth.PrincipalFrom(c); ok {
+		return principal.Kind + ":" + principal.Subject
+	}
+	return ByClientIP(c)
+}
+
+// RateLimit middleware que consome um token do bucket do cliente e responde
+// 429 RATE_LIMITED com Retry-After quando ele está vazio. Falhas do store não
+// bloqueiam a requisição: o limite é uma proteção, não uma regra de negócio.
+func RateLimit(store ratelimit.Store, policy ratelimit.Policy, key RateKey) gin.HandlerFunc {
+	if !policy.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	policyHeader := fmt.Sprintf("%d;w=%d", policy.Capacity(), ceilSeconds(policy.Window))
+
+	return func(c *gin.Context) {
+		clientKey := key(c)
+		result, err := store.Take(c.Request.Context(), clientKey, policy)
+		if err != nil {
+			logger.Warnf("Rate limit store failed for policy %s: %v", policy.Name, err)
+			c.Next()
+			return
+		}
+
+		c.Header(HeaderRateLimitPolicy, policyHeader)
+		c.Header(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+		c.Header(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Header(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Header(HeaderRetryAfter, strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+			logger.WithField("policy", policy.Name).
+				WithField("client", clientKey).
+				Warn("Request denied: rate limit exceeded")
+			problem.Abort(c, ErrRateLimited)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
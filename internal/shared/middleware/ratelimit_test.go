@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/auth"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, policy ratelimit.Policy) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func newRateLimitedRouter(store ratelimit.Store, policy ratelimit.Policy, trustedProxies []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	_ = router.SetTrustedProxies(trustedProxies)
+	router.GET("/", func(c *gin.Context) {
+		if key := c.GetHeader(HeaderAPIKey); key != "" {
+			auth.SetPrincipal(c, &auth.Principal{Subject: key, Kind: auth.KindAPIKey})
+		}
+		c.Next()
+	}, RateLimit(store, policy, ByPrincipal), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func serveFrom(router *gin.Engine, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.RemoteAddr = remoteAddr
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRateLimit(t *testing.T) {
+	policy := ratelimit.Policy{Name: "test", Requests: 1, Window: time.Minute}
+
+	t.Run("Exhausted bucket returns 429 with headers", func(t *testing.T) {
+		router := newRateLimitedRouter(ratelimit.NewMemoryStore(), policy, nil)
+
+		first := serveFrom(router, "10.0.0.1:1234", nil)
+		if first.Code != http.StatusNoContent || first.Header().Get(HeaderRateLimitRemaining) != "0" {
+			t.Fatalf("Unexpected first response: %d %v", first.Code, first.Header())
+		}
+
+		second := serveFrom(router, "10.0.0.1:1234", nil)
+		if second.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected status 429, got %d", second.Code)
+		}
+		if second.Header().Get(HeaderRetryAfter) != "60" || second.Header().Get(HeaderRateLimitLimit) != "1" || second.Header().Get(HeaderRateLimitPolicy) != "1;w=60" {
+			t.Errorf("Unexpected headers: %v", second.Header())
+		}
+	})
+
+	t.Run("Principals and addresses are limited separately", func(t *testing.T) {
+		router := newRateLimitedRouter(ratelimit.NewMemoryStore(), policy, nil)
+		serveFrom(router, "10.0.0.1:1234", nil)
+
+		if recorder := serveFrom(router, "10.0.0.1:1234", map[string]string{HeaderAPIKey: "key-1"}); recorder.Code != http.StatusNoContent {
+			t.Errorf("Expected the API key to have its own bucket, got %d", recorder.Code)
+		}
+		if recorder := serveFrom(router, "10.0.0.2:1234", nil); recorder.Code != http.StatusNoContent {
+			t.Errorf("Expected another address to have its own bucket, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("X-Forwarded-For is only honoured from trusted proxies", func(t *testing.T) {
+		forwarded := func(ip string) map[string]string { return map[string]string{"X-Forwarded-For": ip} }
+
+		untrusted := newRateLimitedRouter(ratelimit.NewMemoryStore(), policy, nil)
+		serveFrom(untrusted, "10.0.0.1:1234", forwarded("203.0.113.1"))
+		if recorder := serveFrom(untrusted, "10.0.0.1:1234", forwarded("203.0.113.2")); recorder.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected spoofed header to be ignored, got %d", recorder.Code)
+		}
+
+		trusted := newRateLimitedRouter(ratelimit.NewMemoryStore(), policy, []string{"10.0.0.0/8"})
+		serveFrom(trusted, "10.0.0.1:1234", forwarded("203.0.113.1"))
+		if recorder := serveFrom(trusted, "10.0.0.1:1234", forwarded("203.0.113.2")); recorder.Code != http.StatusNoContent {
+			t.Errorf("Expected clients behind the proxy to be limited separately, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Store failures do not block requests", func(t *testing.T) {
+		router := newRateLimitedRouter(failingStore{}, policy, nil)
+
+		if recorder := serveFrom(router, "10.0.0.1:1234", nil); recorder.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", recorder.Code)
+		}
+	})
+}
@@ -2,27 +2,105 @@
 package module
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/config"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/events"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/idempotency"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/mail"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/ratelimit"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/transaction"
 	"gorm.io/gorm"
 )
 
+// DefaultStopTimeout é o prazo usado para encerrar os módulos quando o chamador não define um
+const DefaultStopTimeout = 10 * time.Second
+
 type Module interface {
 	RegisterRoutes(router *gin.RouterGroup)
 }
 
+// Dependencies agrupa os recursos compartilhados entregues aos módulos na inicialização.
+// Contracts contém apenas os contratos declarados em Definition.Requires.
+type Dependencies struct {
+	DB           *gorm.DB
+	Config       *config.Config
+	Events       *events.Bus
+	Transactions transaction.Manager
+	RateLimits   ratelimit.Limits
+	// Idempotency guarda as respostas das requisições com Idempotency-Key
+	Idempotency idempotency.Store
+	// Mail envia os e-mails transacionais (confirmação de cadastro, avisos)
+	Mail      mail.Sender
+	Contracts *Contracts
+}
+
+// Initializer é implementado por módulos que precisam preparar recursos antes de receber requisições
+type Initializer interface {
+	Init(ctx context.Context, deps Dependencies) error
+}
+
+// Starter é implementado por módulos que executam trabalhos em background (workers, caches, etc.)
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper é implementado por módulos que precisam liberar recursos no encerramento.
+// O contexto recebido carrega o prazo máximo para o encerramento. Stop também é chamado
+// em módulos não iniciados quando a construção de outro módulo falha.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Exporter é implementado por módulos que fornecem contratos públicos para outros módulos
+type Exporter interface {
+	Exports() map[string]any
+}
+
 func RegisterModules(router *gin.RouterGroup, modules ...Module) {
 	for _, module := range modules {
 		module.RegisterRoutes(router)
 	}
 }
 
-type Setup func(db *gorm.DB) Module
+// StartModules chama Start nos módulos que implementam Starter, na ordem de registro.
+// Se algum módulo falhar, os módulos já iniciados são parados em ordem reversa.
+func StartModules(ctx context.Context, modules ...Module) error {
+	for i, module := range modules {
+		starter, ok := module.(Starter)
+		if !ok {
+			continue
+		}
+
+		if err := starter.Start(ctx); err != nil {
+			startErr := fmt.Errorf("start module %T: %w", module, err)
+
+			stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultStopTimeout)
+			defer cancel()
+
+			return errors.Join(startErr, StopModules(stopCtx, modules[:i]...))
+		}
+	}
+	return nil
+}
+
+// StopModules chama Stop nos módulos que implementam Stopper, em ordem reversa de registro.
+// Todos os módulos são parados mesmo que algum falhe; os erros são agregados.
+func StopModules(ctx context.Context, modules ...Module) error {
+	var errs []error
+	for i := len(modules) - 1; i >= 0; i-- {
+		stopper, ok := modules[i].(Stopper)
+		if !ok {
+			continue
+		}
 
-// SetupAllModules inicializa todas as dependências do módulo e retorna uma lista de módulos.
-func SetupAllModules(db *gorm.DB, moduleSetups ...Setup) []Module {
-	modules := make([]Module, len(moduleSetups))
-	for i, setup := range moduleSetups {
-		modules[i] = setup(db)
+		if err := stopper.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop module %T: %w", modules[i], err))
+		}
 	}
-	return modules
+	return errors.Join(errs...)
 }
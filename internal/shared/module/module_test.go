@@ -0,0 +1,107 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type lifecycleModule struct {
+	name     string
+	calls    *[]string
+	initErr  error
+	startErr error
+	stopErr  error
+}
+
+func (m *lifecycleModule) RegisterRoutes(router *gin.RouterGroup) {}
+
+func (m *lifecycleModule) Init(ctx context.Context, deps Dependencies) error {
+	*m.calls = append(*m.calls, "init:"+m.name)
+	return m.initErr
+}
+
+func (m *lifecycleModule) Start(ctx context.Context) error {
+	*m.calls = append(*m.calls, "start:"+m.name)
+	return m.startErr
+}
+
+func (m *lifecycleModule) Stop(ctx context.Context) error {
+	*m.calls = append(*m.calls, "stop:"+m.name)
+	return m.stopErr
+}
+
+type plainModule struct{}
+
+func (plainModule) RegisterRoutes(router *gin.RouterGroup) {}
+
+func assertCalls(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Expected calls %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected calls %v, got %v", want, got)
+		}
+	}
+}
+
+func TestStartModules(t *testing.T) {
+	t.Run("Start modules in registration order", func(t *testing.T) {
+		var calls []string
+		a := &lifecycleModule{name: "a", calls: &calls}
+		b := &lifecycleModule{name: "b", calls: &calls}
+
+		if err := StartModules(context.Background(), a, plainModule{}, b); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assertCalls(t, calls, "start:a", "start:b")
+	})
+
+	t.Run("Start error stops already started modules", func(t *testing.T) {
+		var calls []string
+		startErr := errors.New("start failed")
+		a := &lifecycleModule{name: "a", calls: &calls}
+		b := &lifecycleModule{name: "b", calls: &calls}
+		c := &lifecycleModule{name: "c", calls: &calls, startErr: startErr}
+		d := &lifecycleModule{name: "d", calls: &calls}
+
+		err := StartModules(context.Background(), a, b, c, d)
+		if !errors.Is(err, startErr) {
+			t.Errorf("Expected start error, got %v", err)
+		}
+		assertCalls(t, calls, "start:a", "start:b", "start:c", "stop:b", "stop:a")
+	})
+}
+
+func TestStopModules(t *testing.T) {
+	t.Run("Stop modules in reverse order", func(t *testing.T) {
+		var calls []string
+		a := &lifecycleModule{name: "a", calls: &calls}
+		b := &lifecycleModule{name: "b", calls: &calls}
+		c := &lifecycleModule{name: "c", calls: &calls}
+
+		if err := StopModules(context.Background(), a, plainModule{}, b, c); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assertCalls(t, calls, "stop:c", "stop:b", "stop:a")
+	})
+
+	t.Run("Stop errors are aggregated", func(t *testing.T) {
+		var calls []string
+		errA := errors.New("stop a failed")
+		errC := errors.New("stop c failed")
+		a := &lifecycleModule{name: "a", calls: &calls, stopErr: errA}
+		b := &lifecycleModule{name: "b", calls: &calls}
+		c := &lifecycleModule{name: "c", calls: &calls, stopErr: errC}
+
+		err := StopModules(context.Background(), a, b, c)
+		if !errors.Is(err, errA) || !errors.Is(err, errC) {
+			t.Errorf("Expected both stop errors, got %v", err)
+		}
+		assertCalls(t, calls, "stop:c", "stop:b", "stop:a")
+	})
+}
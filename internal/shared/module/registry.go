@@ -0,0 +1,238 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrMissingProvider   = errors.New("missing contract provider")
+	ErrDuplicateProvider = errors.New("contract provided by more than one module")
+	ErrDependencyCycle   = errors.New("module dependency cycle")
+)
+
+// Setup constrói o módulo a partir das dependências já resolvidas
+type Setup func(deps Dependencies) (Module, error)
+
+// Definition descreve um módulo: os contratos que fornece, os que consome e como construí-lo
+type Definition struct {
+	Name     string
+	Provides []string
+	Requires []string
+	Setup    Setup
+}
+
+// Contracts guarda as implementações dos contratos públicos, indexadas por nome
+type Contracts struct {
+	values map[string]any
+}
+
+func newContracts() *Contracts {
+	return &Contracts{values: make(map[string]any)}
+}
+
+// Get retorna a implementação registrada para o contrato
+func (c *Contracts) Get(name string) (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+	value, ok := c.values[name]
+	return value, ok
+}
+
+// Resolve busca o contrato e converte para o tipo esperado pelo consumidor
+func Resolve[T any](contracts *Contracts, name string) (T, error) {
+	var zero T
+
+	value, ok := contracts.Get(name)
+	if !ok {
+		return zero, fmt.Errorf("contract %q: %w", name, ErrMissingProvider)
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("contract %q is %T, not %T", name, value, zero)
+	}
+
+	return typed, nil
+}
+
+// Registry ordena a construção dos módulos pelas dependências declaradas entre eles
+type Registry struct {
+	definitions []Definition
+	supplied    map[string]any
+}
+
+// NewRegistry cria um registro vazio
+func NewRegistry() *Registry {
+	return &Registry{supplied: make(map[string]any)}
+}
+
+// Register adiciona definições de módulos ao registro
+func (r *Registry) Register(definitions ...Definition) {
+	r.definitions = append(r.definitions, definitions...)
+}
+
+// Supply disponibiliza um contrato fornecido fora dos módulos (ex.: pelo main)
+func (r *Registry) Supply(name string, value any) {
+	r.supplied[name] = value
+}
+
+// Build valida o grafo de dependências, constrói os módulos em ordem topológica
+// e injeta em cada um os contratos que ele declarou em Requires.
+// Os módulos são retornados na ordem de construção, que é a ordem usada em Start.
+// Se um módulo falha, os já construídos são parados em ordem reversa, como em StartModules.
+func (r *Registry) Build(ctx context.Context, deps Dependencies) ([]Module, error) {
+	ordered, err := r.sort()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := newContracts()
+	for name, value := range r.supplied {
+		resolved.values[name] = value
+	}
+
+	modules := make([]Module, 0, len(ordered))
+	for _, def := range ordered {
+		moduleDeps := deps
+		moduleDeps.Contracts = newContracts()
+		for _, name := range def.Requires {
+			moduleDeps.Contracts.values[name] = resolved.values[name]
+		}
+
+		module, err := def.Setup(moduleDeps)
+		if err != nil {
+			return nil, rollback(ctx, fmt.Errorf("setup module %q: %w", def.Name, err), modules)
+		}
+
+		if initializer, ok := module.(Initializer); ok {
+			if err := initializer.Init(ctx, moduleDeps); err != nil {
+				return nil, rollback(ctx, fmt.Errorf("init module %q: %w", def.Name, err), modules)
+			}
+		}
+
+		modules = append(modules, module)
+		if err := collectExports(def, module, resolved); err != nil {
+			return nil, rollback(ctx, err, modules)
+		}
+	}
+
+	return modules, nil
+}
+
+// rollback para os módulos já inicializados quando a construção falha
+func rollback(ctx context.Context, buildErr error, modules []Module) error {
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultStopTimeout)
+	defer cancel()
+
+	return errors.Join(buildErr, StopModules(stopCtx, modules...))
+}
+
+func collectExports(def Definition, module Module, resolved *Contracts) error {
+	var exports map[string]any
+	if exporter, ok := module.(Exporter); ok {
+		exports = exporter.Exports()
+	}
+
+	for _, name := range def.Provides {
+		value, ok := exports[name]
+		if !ok || value == nil {
+			return fmt.Errorf("module %q declares %q but does not export it", def.Name, name)
+		}
+		resolved.values[name] = value
+	}
+
+	for name := range exports {
+		if !slices.Contains(def.Provides, name) {
+			return fmt.Errorf("module %q exports undeclared contract %q", def.Name, name)
+		}
+	}
+
+	return nil
+}
+
+// sort aplica uma ordenação topológica estável: entre módulos independentes
+// prevalece a ordem de registro.
+func (r *Registry) sort() ([]Definition, error) {
+	providers := make(map[string]int)
+	for i, def := range r.definitions {
+		for _, name := range def.Provides {
+			if _, ok := r.supplied[name]; ok {
+				return nil, fmt.Errorf("%w: %q (module %q and supplied)", ErrDuplicateProvider, name, def.Name)
+			}
+			if other, ok := providers[name]; ok {
+				return nil, fmt.Errorf("%w: %q (modules %q and %q)", ErrDuplicateProvider, name, r.definitions[other].Name, def.Name)
+			}
+			providers[name] = i
+		}
+	}
+
+	dependents := make([][]int, len(r.definitions))
+	pending := make([]int, len(r.definitions))
+	for i, def := range r.definitions {
+		for _, name := range def.Requires {
+			if _, ok := r.supplied[name]; ok {
+				continue
+			}
+			provider, ok := providers[name]
+			if !ok {
+				return nil, fmt.Errorf("module %q requires %q: %w", def.Name, name, ErrMissingProvider)
+			}
+			if provider == i {
+				return nil, fmt.Errorf("%w: %s -> %s", ErrDependencyCycle, def.Name, def.Name)
+			}
+			dependents[provider] = append(dependents[provider], i)
+			pending[i]++
+		}
+	}
+
+	ordered := make([]Definition, 0, len(r.definitions))
+	done := make([]bool, len(r.definitions))
+	for len(ordered) < len(r.definitions) {
+		next := -1
+		for i := range r.definitions {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, r.describeCycle(done, providers))
+		}
+
+		done[next] = true
+		ordered = append(ordered, r.definitions[next])
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+
+	return ordered, nil
+}
+
+// describeCycle percorre os módulos restantes seguindo suas dependências até repetir um deles
+func (r *Registry) describeCycle(done []bool, providers map[string]int) string {
+	start := slices.Index(done, false)
+	visited := make(map[int]int)
+	var path []string
+
+	for current := start; ; {
+		if at, ok := visited[current]; ok {
+			return strings.Join(append(path[at:], r.definitions[current].Name), " -> ")
+		}
+		visited[current] = len(path)
+		path = append(path, r.definitions[current].Name)
+
+		for _, name := range r.definitions[current].Requires {
+			if provider, ok := providers[name]; ok && !done[provider] {
+				current = provider
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type contractModule struct {
+	name    string
+	calls   *[]string
+	exports map[string]any
+}
+
+func (m *contractModule) RegisterRoutes(router *gin.RouterGroup) {}
+
+func (m *contractModule) Init(ctx context.Context, deps Dependencies) error {
+	*m.calls = append(*m.calls, "init:"+m.name)
+	return nil
+}
+
+func (m *contractModule) Exports() map[string]any {
+	return m.exports
+}
+
+func definition(name string, calls *[]string, provides, requires []string) Definition {
+	return Definition{
+		Name:     name,
+		Provides: provides,
+		Requires: requires,
+		Setup: func(deps Dependencies) (Module, error) {
+			exports := make(map[string]any)
+			for _, contract := range provides {
+				exports[contract] = name + ":" + contract
+			}
+			return &contractModule{name: name, calls: calls, exports: exports}, nil
+		},
+	}
+}
+
+func TestRegistryBuild(t *testing.T) {
+	t.Run("Builds providers before consumers", func(t *testing.T) {
+		var calls []string
+		registry := NewRegistry()
+		registry.Register(
+			definition("billing", &calls, nil, []string{"user.Query", "catalog.Query"}),
+			definition("catalog", &calls, []string{"catalog.Query"}, []string{"user.Query"}),
+			definition("user", &calls, []string{"user.Query"}, nil),
+		)
+
+		modules, err := registry.Build(context.Background(), Dependencies{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(modules) != 3 {
+			t.Fatalf("Expected 3 modules, got %d", len(modules))
+		}
+		assertCalls(t, calls, "init:user", "init:catalog", "init:billing")
+	})
+
+	t.Run("Keeps registration order for independent modules", func(t *testing.T) {
+		var calls []string
+		registry := NewRegistry()
+		registry.Register(
+			definition("a", &calls, nil, nil),
+			definition("b", &calls, nil, nil),
+			definition("c", &calls, nil, nil),
+		)
+
+		if _, err := registry.Build(context.Background(), Dependencies{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assertCalls(t, calls, "init:a", "init:b", "init:c")
+	})
+
+	t.Run("Injects only required contracts", func(t *testing.T) {
+		var calls []string
+		var injected *Contracts
+
+		consumer := definition("consumer", &calls, nil, []string{"user.Query"})
+		consumer.Setup = func(deps Dependencies) (Module, error) {
+			injected = deps.Contracts
+			return &contractModule{name: "consumer", calls: &calls}, nil
+		}
+
+		registry := NewRegistry()
+		registry.Register(
+			consumer,
+			definition("user", &calls, []string{"user.Query", "user.Admin"}, nil),
+		)
+
+		if _, err := registry.Build(context.Background(), Dependencies{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		value, err := Resolve[string](injected, "user.Query")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if value != "user:user.Query" {
+			t.Errorf("Expected user:user.Query, got %s", value)
+		}
+
+		if _, err := Resolve[string](injected, "user.Admin"); !errors.Is(err, ErrMissingProvider) {
+			t.Errorf("Expected undeclared contract to be hidden, got %v", err)
+		}
+
+		if _, err := Resolve[int](injected, "user.Query"); err == nil {
+			t.Error("Expected type mismatch error, got nil")
+		}
+	})
+
+	t.Run("Supplied contracts satisfy requirements", func(t *testing.T) {
+		var calls []string
+		registry := NewRegistry()
+		registry.Supply("clock", "system")
+		registry.Register(definition("user", &calls, nil, []string{"clock"}))
+
+		if _, err := registry.Build(context.Background(), Dependencies{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Missing provider", func(t *testing.T) {
+		var calls []string
+		registry := NewRegistry()
+		registry.Register(definition("billing", &calls, nil, []string{"user.Query"}))
+
+		_, err := registry.Build(context.Background(), Dependencies{})
+		if !errors.Is(err, ErrMissingProvider) {
+			t.Errorf("Expected missing provider error, got %v", err)
+		}
+		if len(calls) != 0 {
+			t.Errorf("Expected no module to be built, got %v", calls)
+		}
+	})
+
+	t.Run("Duplicate provider", func(t *testing.T) {
+		var calls []string
+		registry := NewRegistry()
+		registry.Register(
+			definition("a", &calls, []string{"user.Query"}, nil),
+			definition("b", &calls, []string{"user.Query"}, nil),
+		)
+
+		_, err := registry.Build(context.Background(), Dependencies{})
+		if !errors.Is(err, ErrDuplicateProvider) {
+			t.Errorf("Expected duplicate provider error, got %v", err)
+		}
+	})
+
+	t.Run("Dependency cycle", func(t *testing.T) {
+		var calls []string
+		registry := NewRegistry()
+		registry.Register(
+			definition("standalone", &calls, nil, nil),
+			definition("a", &calls, []string{"a.Query"}, []string{"c.Query"}),
+			definition("b", &calls, []string{"b.Query"}, []string{"a.Query"}),
+			definition("c", &calls, []string{"c.Query"}, []string{"b.Query"}),
+		)
+
+		_, err := registry.Build(context.Background(), Dependencies{})
+		if !errors.Is(err, ErrDependencyCycle) {
+			t.Fatalf("Expected dependency cycle error, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "a -> c -> b -> a") {
+			t.Errorf("Expected cycle path in error, got %v", err)
+		}
+		if len(calls) != 0 {
+			t.Errorf("Expected no module to be built, got %v", calls)
+		}
+	})
+
+	t.Run("Init error aborts build", func(t *testing.T) {
+		var calls []string
+		initErr := errors.New("init failed")
+
+		registry := NewRegistry()
+		registry.Register(
+			Definition{Name: "a", Setup: func(deps Dependencies) (Module, error) {
+				return &lifecycleModule{name: "a", calls: &calls, initErr: initErr}, nil
+			}},
+			definition("b", &calls, nil, nil),
+		)
+
+		_, err := registry.Build(context.Background(), Dependencies{})
+		if !errors.Is(err, initErr) {
+			t.Errorf("Expected init error, got %v", err)
+		}
+		assertCalls(t, calls, "init:a")
+	})
+
+	t.Run("Failure stops already initialized modules", func(t *testing.T) {
+		var calls []string
+		setupErr := errors.New("setup failed")
+
+		registry := NewRegistry()
+		registry.Register(
+			Definition{Name: "a", Setup: func(deps Dependencies) (Module, error) {
+				return &lifecycleModule{name: "a", calls: &calls}, nil
+			}},
+			Definition{Name: "b", Setup: func(deps Dependencies) (Module, error) {
+				return &lifecycleModule{name: "b", calls: &calls}, nil
+			}},
+			Definition{Name: "c", Setup: func(deps Dependencies) (Module, error) {
+				return nil, setupErr
+			}},
+		)
+
+		_, err := registry.Build(context.Background(), Dependencies{})
+		if !errors.Is(err, setupErr) {
+			t.Errorf("Expected setup error, got %v", err)
+		}
+		assertCalls(t, calls, "init:a", "init:b", "stop:b", "stop:a")
+	})
+
+	t.Run("Declared contract not exported", func(t *testing.T) {
+		var calls []string
+		broken := definition("user", &calls, []string{"user.Query"}, nil)
+		broken.Setup = func(deps Dependencies) (Module, error) {
+			return &contractModule{name: "user", calls: &calls}, nil
+		}
+
+		registry := NewRegistry()
+		registry.Register(broken)
+
+		if _, err := registry.Build(context.Background(), Dependencies{}); err == nil {
+			t.Error("Expected error for missing export, got nil")
+		}
+	})
+}
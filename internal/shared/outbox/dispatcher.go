@@ -0,0 +1,290 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/events"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Sink é o destino das mensagens do outbox (barramento local, broker externo, etc.).
+// A entrega é at-least-once: o destino deve tolerar mensagens repetidas.
+type Sink interface {
+	Deliver(ctx context.Context, msg Message) error
+}
+
+// permanentError marca falhas que não se resolvem com novas tentativas
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent sinaliza que a mensagem é inválida e deve ir direto para dead letter
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// BusSink entrega as mensagens ao barramento de eventos em processo
+type BusSink struct {
+	bus *events.Bus
+}
+
+func NewBusSink(bus *events.Bus) *BusSink {
+	return &BusSink{bus: bus}
+}
+
+func (s *BusSink) Deliver(ctx context.Context, msg Message) error {
+	event, err := s.bus.Types().Decode(msg.EventName, msg.Payload)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	return s.bus.Publish(ctx, event)
+}
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// Lease é o prazo da reserva de um lote; deve superar o tempo de entrega de uma mensagem
+	Lease time.Duration
+}
+
+// Dispatcher lê periodicamente as mensagens pendentes e as entrega ao Sink.
+// Mensagens que falham são reagendadas com backoff exponencial e, ao atingir
+// MaxAttempts, marcadas como dead letter.
+type Dispatcher struct {
+	db   *gorm.DB
+	sink Sink
+	cfg  Config
+	now  func() time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+}
+
+func NewDispatcher(db *gorm.DB, sink Sink, cfg Config) (*Dispatcher, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Dispatcher{
+		db:   db,
+		sink: sink,
+		cfg:  cfg,
+		now:  time.Now,
+	}, nil
+}
+
+// validate evita configurações que travariam o loop (ex.: ticker com intervalo zero)
+func (c Config) validate() error {
+	var errs []error
+	if c.PollInterval <= 0 {
+		errs = append(errs, fmt.Errorf("poll interval must be positive, got %s", c.PollInterval))
+	}
+	if c.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("batch size must be positive, got %d", c.BatchSize))
+	}
+	if c.MaxAttempts <= 0 {
+		errs = append(errs, fmt.Errorf("max attempts must be positive, got %d", c.MaxAttempts))
+	}
+	if c.Lease <= 0 {
+		errs = append(errs, fmt.Errorf("lease must be positive, got %s", c.Lease))
+	}
+	if c.BaseBackoff < 0 || c.MaxBackoff < c.BaseBackoff {
+		errs = append(errs, fmt.Errorf("backoff must satisfy 0 <= base (%s) <= max (%s)", c.BaseBackoff, c.MaxBackoff))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid outbox config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Start inicia o loop de polling em background
+func (d *Dispatcher) Start(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cancel != nil {
+		return errors.New("outbox dispatcher already started")
+	}
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go d.run(runCtx)
+
+	logger.Infof("Outbox dispatcher started (poll interval %s)", d.cfg.PollInterval)
+	return nil
+}
+
+// Stop interrompe o polling e aguarda o lote em andamento até o prazo de ctx
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cancel == nil {
+		return nil
+	}
+
+	d.cancel()
+	d.cancel = nil
+
+	select {
+	case <-d.done:
+		logger.Info("Outbox dispatcher stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("outbox dispatcher stop: %w", ctx.Err())
+	}
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			processed, err := d.DispatchPending(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Errorf("Outbox dispatch failed: %v", err)
+			}
+			if err != nil || processed < d.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending processa um lote de mensagens prontas para entrega e retorna quantas foram processadas.
+// O lote é reservado numa transação curta (FOR UPDATE SKIP LOCKED, seguro para várias
+// instâncias) e entregue fora dela: nenhuma conexão ou lock fica preso durante a entrega.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	claimed, err := d.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range claimed {
+		if err := d.dispatch(ctx, &claimed[i]); err != nil {
+			return i, err
+		}
+	}
+
+	return len(claimed), nil
+}
+
+// claim reserva o lote adiando next_attempt_at por Lease; se a instância cair antes de
+// gravar o resultado, a mensagem volta a ficar pronta quando o lease vence. O incremento
+// de attempts identifica a reserva.
+func (d *Dispatcher) claim(ctx context.Context) ([]MessageModel, error) {
+	var models []MessageModel
+	now := d.now()
+
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Where("status = ? AND next_attempt_at <= ?", StatusPending, now.Unix()).
+			Order("id").
+			Limit(d.cfg.BatchSize).
+			Find(&models)
+		if result.Error != nil || len(models) == 0 {
+			return result.Error
+		}
+
+		ids := make([]uint64, len(models))
+		for i := range models {
+			ids[i] = models[i].ID
+			models[i].Attempts++
+		}
+
+		return tx.Model(&MessageModel{}).Where("id IN ?", ids).Updates(map[string]any{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(d.cfg.Lease).Unix(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models, nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, model *MessageModel) error {
+	msg := Message{
+		ID:         model.ID,
+		EventName:  model.EventName,
+		Payload:    []byte(model.Payload),
+		Attempts:   model.Attempts - 1,
+		OccurredAt: time.Unix(model.OccurredAt, 0),
+	}
+
+	deliverErr := d.sink.Deliver(ctx, msg)
+	now := d.now()
+
+	updates := map[string]any{}
+
+	var permanent *permanentError
+	switch {
+	case deliverErr == nil:
+		updates["status"] = StatusDelivered
+		updates["delivered_at"] = now.Unix()
+		updates["last_error"] = ""
+
+	case errors.As(deliverErr, &permanent) || model.Attempts >= d.cfg.MaxAttempts:
+		updates["status"] = StatusDead
+		updates["last_error"] = deliverErr.Error()
+
+		logger.WithFields(logrus.Fields{
+			"outbox_id": model.ID,
+			"event":     model.EventName,
+			"attempts":  model.Attempts,
+		}).Errorf("Outbox message moved to dead letter: %v", deliverErr)
+
+	default:
+		updates["last_error"] = deliverErr.Error()
+		updates["next_attempt_at"] = now.Add(d.backoff(model.Attempts)).Unix()
+	}
+
+	// O resultado é gravado mesmo no encerramento, e só se a reserva ainda for desta
+	// instância: após o lease outra pode ter assumido a mensagem
+	return d.db.WithContext(context.WithoutCancel(ctx)).
+		Model(&MessageModel{}).
+		Where("id = ? AND attempts = ? AND status = ?", model.ID, model.Attempts, StatusPending).
+		Updates(updates).Error
+}
+
+// backoff calcula o atraso exponencial limitado por MaxBackoff
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.cfg.MaxBackoff)
+}
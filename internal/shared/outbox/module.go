@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/module"
+)
+
+// Definition registra o dispatcher no ciclo de vida dos módulos (Starter/Stopper).
+// Deve ser a última definição: inicia depois que os módulos assinaram seus eventos
+// e para antes deles, sem entregar mensagens a handlers já encerrados.
+func Definition() module.Definition {
+	return module.Definition{
+		Name: "outbox",
+		Setup: func(deps module.Dependencies) (module.Module, error) {
+			cfg := deps.Config.Outbox
+			dispatcher, err := NewDispatcher(deps.DB, NewBusSink(deps.Events), Config{
+				PollInterval: cfg.PollInterval,
+				BatchSize:    cfg.BatchSize,
+				MaxAttempts:  cfg.MaxAttempts,
+				BaseBackoff:  cfg.BaseBackoff,
+				MaxBackoff:   cfg.MaxBackoff,
+				Lease:        cfg.Lease,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return dispatcherModule{dispatcher}, nil
+		},
+	}
+}
+
+// dispatcherModule expõe Start/Stop do Dispatcher como módulo; não registra rotas
+type dispatcherModule struct {
+	*Dispatcher
+}
+
+func (dispatcherModule) RegisterRoutes(*gin.RouterGroup) {}
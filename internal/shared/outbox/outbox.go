@@ -0,0 +1,88 @@
+// Package outbox implementa o padrão transactional outbox: eventos de domínio são
+// gravados na mesma transação da mudança de estado e entregues depois por um dispatcher.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/events"
+	"gorm.io/gorm"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusDelivered Status = "delivered"
+	StatusDead      Status = "dead"
+)
+
+type MessageModel struct {
+	ID            uint64 `gorm:"primaryKey;autoIncrement"`
+	EventName     string `gorm:"size:255;not null"`
+	Payload       string `gorm:"type:text;not null"`
+	Status        string `gorm:"size:20;not null;index:idx_outbox_dispatch,priority:1"`
+	Attempts      int    `gorm:"not null"`
+	LastError     string `gorm:"type:text"`
+	OccurredAt    int64  `gorm:"not null"`
+	NextAttemptAt int64  `gorm:"not null;index:idx_outbox_dispatch,priority:2"`
+	CreatedAt     int64  `gorm:"not null"`
+	DeliveredAt   *int64
+}
+
+func (MessageModel) TableName() string {
+	return "outbox_messages"
+}
+
+// Message é a representação de um evento armazenado no outbox.
+// O ID é crescente e pode ser usado pelos consumidores para deduplicação.
+type Message struct {
+	ID         uint64
+	EventName  string
+	Payload    []byte
+	Attempts   int
+	OccurredAt time.Time
+}
+
+// Append grava os eventos no outbox usando a transação recebida.
+// Deve ser chamado com a mesma transação que persiste o agregado.
+func Append(ctx context.Context, tx *gorm.DB, evts ...events.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	models := make([]MessageModel, len(evts))
+	for i, event := range evts {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", event.EventName(), err)
+		}
+
+		models[i] = MessageModel{
+			EventName:     event.EventName(),
+			Payload:       string(payload),
+			Status:        string(StatusPending),
+			OccurredAt:    occurredAt(payload, now).Unix(),
+			NextAttemptAt: now.Unix(),
+			CreatedAt:     now.Unix(),
+		}
+	}
+
+	return tx.WithContext(ctx).Create(&models).Error
+}
+
+// occurredAt lê o campo occurred_at do evento serializado, presente em todos os
+// eventos de domínio; eventos sem ele usam o momento da gravação
+func occurredAt(payload []byte, fallback time.Time) time.Time {
+	var stamped struct {
+		OccurredAt time.Time `json:"occurred_at"`
+	}
+	if err := json.Unmarshal(payload, &stamped); err != nil || stamped.OccurredAt.IsZero() {
+		return fallback
+	}
+	return stamped.OccurredAt
+}
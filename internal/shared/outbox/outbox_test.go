@@ -0,0 +1,346 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/events"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type accountOpened struct {
+	AccountID string `json:"account_id"`
+}
+
+func (accountOpened) EventName() string {
+	return "account.opened"
+}
+
+type accountClosed struct {
+	AccountID  string    `json:"account_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (accountClosed) EventName() string {
+	return "account.closed"
+}
+
+type unknownEvent struct{}
+
+func (unknownEvent) EventName() string {
+	return "unknown.event"
+}
+
+type sinkFunc func(ctx context.Context, msg Message) error
+
+func (f sinkFunc) Deliver(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&MessageModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+func testConfig() Config {
+	return Config{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    10,
+		MaxAttempts:  3,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   time.Minute,
+		Lease:        time.Minute,
+	}
+}
+
+func newTestDispatcher(t *testing.T, db *gorm.DB, sink Sink) *Dispatcher {
+	t.Helper()
+	dispatcher, err := NewDispatcher(db, sink, testConfig())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return dispatcher
+}
+
+func findMessage(t *testing.T, db *gorm.DB) MessageModel {
+	t.Helper()
+	var model MessageModel
+	if err := db.First(&model).Error; err != nil {
+		t.Fatalf("Failed to load message: %v", err)
+	}
+	return model
+}
+
+func TestAppend(t *testing.T) {
+	t.Run("Rolled back transaction discards events", func(t *testing.T) {
+		db := newTestDB(t)
+		rollback := errors.New("rollback")
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := Append(context.Background(), tx, accountOpened{AccountID: "1"}); err != nil {
+				return err
+			}
+			return rollback
+		})
+		if !errors.Is(err, rollback) {
+			t.Fatalf("Expected rollback error, got %v", err)
+		}
+
+		var count int64
+		db.Model(&MessageModel{}).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected no messages, got %d", count)
+		}
+	})
+
+	t.Run("Committed transaction stores pending events", func(t *testing.T) {
+		db := newTestDB(t)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return Append(context.Background(), tx, accountOpened{AccountID: "1"})
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		model := findMessage(t, db)
+		if model.Status != string(StatusPending) || model.EventName != "account.opened" {
+			t.Errorf("Unexpected message: %+v", model)
+		}
+		if model.Payload != `{"account_id":"1"}` {
+			t.Errorf("Unexpected payload: %s", model.Payload)
+		}
+	})
+
+	t.Run("Keeps the time the event occurred", func(t *testing.T) {
+		db := newTestDB(t)
+		occurred := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return Append(context.Background(), tx, accountClosed{AccountID: "1", OccurredAt: occurred})
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if model := findMessage(t, db); model.OccurredAt != occurred.Unix() {
+			t.Errorf("Expected occurred_at %d, got %d", occurred.Unix(), model.OccurredAt)
+		}
+	})
+}
+
+func TestDispatchPending(t *testing.T) {
+	t.Run("Delivers to the event bus and marks delivered", func(t *testing.T) {
+		db := newTestDB(t)
+		bus := events.NewBus(events.Config{Workers: 1, QueueSize: 1})
+		defer bus.Close(context.Background())
+		bus.Types().Register(accountOpened{})
+
+		var received []accountOpened
+		events.Subscribe(bus, func(ctx context.Context, event accountOpened) error {
+			received = append(received, event)
+			return nil
+		})
+
+		_ = Append(context.Background(), db, accountOpened{AccountID: "1"}, accountOpened{AccountID: "2"})
+
+		dispatcher := newTestDispatcher(t, db, NewBusSink(bus))
+		processed, err := dispatcher.DispatchPending(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if processed != 2 {
+			t.Errorf("Expected 2 processed messages, got %d", processed)
+		}
+		if len(received) != 2 || received[0].AccountID != "1" || received[1].AccountID != "2" {
+			t.Errorf("Unexpected deliveries: %+v", received)
+		}
+
+		var pending int64
+		db.Model(&MessageModel{}).Where("status = ?", StatusPending).Count(&pending)
+		if pending != 0 {
+			t.Errorf("Expected no pending messages, got %d", pending)
+		}
+
+		processed, _ = dispatcher.DispatchPending(context.Background())
+		if processed != 0 {
+			t.Errorf("Expected delivered messages to be skipped, got %d", processed)
+		}
+	})
+
+	t.Run("Failed delivery is retried with backoff then dead-lettered", func(t *testing.T) {
+		db := newTestDB(t)
+		_ = Append(context.Background(), db, accountOpened{AccountID: "1"})
+
+		now := time.Now()
+		dispatcher := newTestDispatcher(t, db, sinkFunc(func(ctx context.Context, msg Message) error {
+			return errors.New("broker unavailable")
+		}))
+		dispatcher.now = func() time.Time { return now }
+
+		if _, err := dispatcher.DispatchPending(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		model := findMessage(t, db)
+		if model.Status != string(StatusPending) || model.Attempts != 1 {
+			t.Fatalf("Expected pending message with 1 attempt, got %+v", model)
+		}
+		if model.NextAttemptAt != now.Add(time.Second).Unix() {
+			t.Errorf("Expected next attempt in 1s, got %d", model.NextAttemptAt-now.Unix())
+		}
+
+		processed, _ := dispatcher.DispatchPending(context.Background())
+		if processed != 0 {
+			t.Errorf("Expected message to wait for backoff, got %d processed", processed)
+		}
+
+		for range 2 {
+			now = now.Add(time.Hour)
+			if _, err := dispatcher.DispatchPending(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		model = findMessage(t, db)
+		if model.Status != string(StatusDead) || model.Attempts != 3 {
+			t.Errorf("Expected dead message after 3 attempts, got %+v", model)
+		}
+		if model.LastError != "broker unavailable" {
+			t.Errorf("Expected last error to be recorded, got %q", model.LastError)
+		}
+	})
+
+	t.Run("Undecodable message goes straight to dead letter", func(t *testing.T) {
+		db := newTestDB(t)
+		bus := events.NewBus(events.Config{Workers: 1, QueueSize: 1})
+		defer bus.Close(context.Background())
+
+		_ = Append(context.Background(), db, unknownEvent{})
+
+		dispatcher := newTestDispatcher(t, db, NewBusSink(bus))
+		if _, err := dispatcher.DispatchPending(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		model := findMessage(t, db)
+		if model.Status != string(StatusDead) || model.Attempts != 1 {
+			t.Errorf("Expected dead message after 1 attempt, got %+v", model)
+		}
+	})
+}
+
+func TestDispatchLease(t *testing.T) {
+	t.Run("Claimed messages are delivered without holding the rows", func(t *testing.T) {
+		db := newTestDB(t)
+		_ = Append(context.Background(), db, accountOpened{AccountID: "1"})
+
+		var other *Dispatcher
+		var otherProcessed int
+		var otherErr error
+		dispatcher := newTestDispatcher(t, db, sinkFunc(func(ctx context.Context, msg Message) error {
+			// Com a transação da reserva ainda aberta, o SQLite recusaria a escrita da outra instância
+			otherProcessed, otherErr = other.DispatchPending(ctx)
+			return nil
+		}))
+		other = newTestDispatcher(t, db, sinkFunc(func(ctx context.Context, msg Message) error {
+			return errors.New("claimed message delivered twice")
+		}))
+
+		processed, err := dispatcher.DispatchPending(context.Background())
+		if err != nil || processed != 1 {
+			t.Fatalf("Expected 1 processed message, got %d (%v)", processed, err)
+		}
+		if otherErr != nil || otherProcessed != 0 {
+			t.Errorf("Expected the other dispatcher to skip the claimed message, got %d (%v)", otherProcessed, otherErr)
+		}
+		if model := findMessage(t, db); model.Status != string(StatusDelivered) || model.Attempts != 1 {
+			t.Errorf("Expected delivered message with 1 attempt, got %+v", model)
+		}
+	})
+
+	t.Run("Claim expires when the result is never recorded", func(t *testing.T) {
+		db := newTestDB(t)
+		_ = Append(context.Background(), db, accountOpened{AccountID: "1"})
+
+		now := time.Now()
+		var delivered []Message
+		dispatcher := newTestDispatcher(t, db, sinkFunc(func(ctx context.Context, msg Message) error {
+			delivered = append(delivered, msg)
+			return nil
+		}))
+		dispatcher.now = func() time.Time { return now }
+
+		// Reserva sem entrega, como uma instância que caiu no meio do lote
+		if _, err := dispatcher.claim(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if processed, _ := dispatcher.DispatchPending(context.Background()); processed != 0 {
+			t.Errorf("Expected the claimed message to wait for the lease, got %d processed", processed)
+		}
+
+		now = now.Add(testConfig().Lease + time.Second)
+		if processed, _ := dispatcher.DispatchPending(context.Background()); processed != 1 {
+			t.Fatalf("Expected the message to be redelivered after the lease, got %d processed", processed)
+		}
+		if delivered[0].Attempts != 1 {
+			t.Errorf("Expected the redelivery to report 1 previous attempt, got %d", delivered[0].Attempts)
+		}
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher := &Dispatcher{cfg: Config{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		if got := dispatcher.backoff(i + 1); got != expected {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, expected)
+		}
+	}
+}
+
+func TestNewDispatcherConfig(t *testing.T) {
+	tests := map[string]func(*Config){
+		"zero poll interval":    func(c *Config) { c.PollInterval = 0 },
+		"zero batch size":       func(c *Config) { c.BatchSize = 0 },
+		"zero lease":            func(c *Config) { c.Lease = 0 },
+		"negative max attempts": func(c *Config) { c.MaxAttempts = -1 },
+		"max below base backoff": func(c *Config) {
+			c.BaseBackoff = time.Minute
+			c.MaxBackoff = time.Second
+		},
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := testConfig()
+			mutate(&cfg)
+			if _, err := NewDispatcher(nil, nil, cfg); err == nil {
+				t.Error("Expected an invalid config error")
+			}
+		})
+	}
+
+	if _, err := NewDispatcher(nil, nil, testConfig()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/signedtoken"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+var ErrInvalidCursor = apperror.BadRequest("INVALID_CURSOR", "cursor is invalid or was tampered with")
+
+// CursorCodec serializa a posição de uma paginação por cursor (keyset) em um token
+// opaco assinado, impedindo que o cliente forje posições arbitrárias
+type CursorCodec struct {
+	tokens *signedtoken.Codec
+}
+
+// NewCursorCodec cria o codec. Sem segredo configurado é usada uma chave aleatória,
+// o que invalida os cursores emitidos a cada restart e entre instâncias; um segredo
+// curto demais é recusado.
+func NewCursorCodec(secret string) (*CursorCodec, error) {
+	tokens, err := signedtoken.NewCodec(secret)
+	switch {
+	case errors.Is(err, signedtoken.ErrMissingSecret):
+		logger.Warn("Cursor secret not configured, using a random key; cursors will not survive restarts")
+		tokens = signedtoken.NewEphemeralCodec()
+	case err != nil:
+		return nil, fmt.Errorf("PAGINATION_CURSOR_SECRET: %w", err)
+	}
+
+	return &CursorCodec{tokens: tokens}, nil
+}
+
+// Encode serializa position em um token opaco
+func (c *CursorCodec) Encode(position any) (string, error) {
+	return c.tokens.Seal(position)
+}
+
+// Decode valida a assinatura do token e preenche position. Tokens malformados ou
+// adulterados retornam ErrInvalidCursor.
+func (c *CursorCodec) Decode(token string, position any) error {
+	if err := c.tokens.Open(token, position); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/signedtoken"
+)
+
+type position struct {
+	CreatedAt int64  `json:"t"`
+	ID        string `json:"id"`
+}
+
+// newCodec cria o codec com um segredo de tamanho mínimo feito de fill
+func newCodec(t *testing.T, fill string) *CursorCodec {
+	t.Helper()
+
+	codec, err := NewCursorCodec(strings.Repeat(fill, signedtoken.MinSecretLength))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return codec
+}
+
+func TestNewCursorCodec(t *testing.T) {
+	if _, err := NewCursorCodec("short"); !errors.Is(err, signedtoken.ErrShortSecret) {
+		t.Errorf("Expected ErrShortSecret, got %v", err)
+	}
+	if codec, err := NewCursorCodec(""); err != nil || codec == nil {
+		t.Errorf("Expected a codec with a random key, got %v", err)
+	}
+}
+
+func TestCursorCodec(t *testing.T) {
+	codec := newCodec(t, "s")
+
+	t.Run("Round trip", func(t *testing.T) {
+		token, err := codec.Encode(position{CreatedAt: 1700000000, ID: "abc"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var got position
+		if err := codec.Decode(token, &got); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got.CreatedAt != 1700000000 || got.ID != "abc" {
+			t.Errorf("Unexpected position: %+v", got)
+		}
+	})
+
+	t.Run("Rejects tampered and foreign tokens", func(t *testing.T) {
+		token, _ := codec.Encode(position{CreatedAt: 1, ID: "a"})
+		forged, _ := newCodec(t, "o").Encode(position{CreatedAt: 1, ID: "a"})
+		tampered, _ := codec.Encode(position{CreatedAt: 2, ID: "a"})
+		tampered = tampered[:len(tampered)-43] + token[len(token)-43:]
+
+		for _, invalid := range []string{"", "garbage", "a.b", forged, tampered} {
+			var got position
+			if err := codec.Decode(invalid, &got); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Expected ErrInvalidCursor for %q, got %v", invalid, err)
+			}
+		}
+	})
+}
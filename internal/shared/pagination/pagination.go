@@ -0,0 +1,82 @@
+// Package pagination calcula metadados de paginação por página/limite e os links RFC 5988
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Page descreve a página retornada e o total de itens da consulta
+type Page struct {
+	Number int
+	Limit  int
+	Total  int64
+}
+
+func (p Page) TotalPages() int {
+	if p.Limit <= 0 || p.Total <= 0 {
+		return 0
+	}
+	return int((p.Total + int64(p.Limit) - 1) / int64(p.Limit))
+}
+
+func (p Page) HasNext() bool {
+	return int64(p.Number)*int64(p.Limit) < p.Total
+}
+
+func (p Page) HasPrev() bool {
+	return p.Number > 1
+}
+
+// Links monta o valor do header Link (first, prev, next, last) a partir da URL da requisição,
+// preservando os demais parâmetros de query
+func (p Page) Links(requestURL *url.URL) string {
+	last := max(p.TotalPages(), 1)
+
+	links := []string{link(requestURL, 1, p.Limit, "first")}
+	if p.HasPrev() {
+		links = append(links, link(requestURL, min(p.Number-1, last), p.Limit, "prev"))
+	}
+	if p.HasNext() {
+		links = append(links, link(requestURL, p.Number+1, p.Limit, "next"))
+	}
+	links = append(links, link(requestURL, last, p.Limit, "last"))
+
+	return strings.Join(links, ", ")
+}
+
+// SetLinkHeader escreve o header Link da página na resposta
+func SetLinkHeader(c *gin.Context, p Page) {
+	c.Header("Link", p.Links(c.Request.URL))
+}
+
+// CursorLinks monta o header Link da paginação por cursor (apenas next)
+func CursorLinks(requestURL *url.URL, nextCursor string) string {
+	query := requestURL.Query()
+	query.Del("page")
+	query.Set("cursor", nextCursor)
+
+	return format(requestURL.Path, query, "next")
+}
+
+// SetCursorLinkHeader escreve o header Link com o próximo cursor
+func SetCursorLinkHeader(c *gin.Context, nextCursor string) {
+	c.Header("Link", CursorLinks(c.Request.URL, nextCursor))
+}
+
+func link(requestURL *url.URL, page, limit int, rel string) string {
+	query := requestURL.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("limit", strconv.Itoa(limit))
+
+	return format(requestURL.Path, query, rel)
+}
+
+func format(path string, query url.Values, rel string) string {
+	target := url.URL{Path: path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+}
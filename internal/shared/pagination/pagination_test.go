@@ -0,0 +1,55 @@
+package pagination
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestPage(t *testing.T) {
+	tests := []struct {
+		name       string
+		page       Page
+		totalPages int
+		hasNext    bool
+	}{
+		{name: "Empty result", page: Page{Number: 1, Limit: 10, Total: 0}, totalPages: 0, hasNext: false},
+		{name: "Exact pages", page: Page{Number: 1, Limit: 5, Total: 15}, totalPages: 3, hasNext: true},
+		{name: "Partial last page", page: Page{Number: 3, Limit: 5, Total: 11}, totalPages: 3, hasNext: false},
+		{name: "Page beyond the end", page: Page{Number: 9, Limit: 5, Total: 11}, totalPages: 3, hasNext: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.page.TotalPages(); got != tt.totalPages {
+				t.Errorf("Expected %d total pages, got %d", tt.totalPages, got)
+			}
+			if got := tt.page.HasNext(); got != tt.hasNext {
+				t.Errorf("Expected hasNext %v, got %v", tt.hasNext, got)
+			}
+		})
+	}
+}
+
+func TestLinks(t *testing.T) {
+	requestURL, _ := url.Parse("/api/v1/users?page=2&limit=5&status=active")
+
+	t.Run("Middle page has all relations", func(t *testing.T) {
+		got := Page{Number: 2, Limit: 5, Total: 15}.Links(requestURL)
+		want := `</api/v1/users?limit=5&page=1&status=active>; rel="first", ` +
+			`</api/v1/users?limit=5&page=1&status=active>; rel="prev", ` +
+			`</api/v1/users?limit=5&page=3&status=active>; rel="next", ` +
+			`</api/v1/users?limit=5&page=3&status=active>; rel="last"`
+		if got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("Single page has only first and last", func(t *testing.T) {
+		got := Page{Number: 1, Limit: 5, Total: 3}.Links(requestURL)
+		want := `</api/v1/users?limit=5&page=1&status=active>; rel="first", ` +
+			`</api/v1/users?limit=5&page=1&status=active>; rel="last"`
+		if got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+// Package password gera e confere hashes de senha com argon2id ou bcrypt. Os hashes
+// carregam algoritmo e parâmetros, então trocar a configuração não invalida senhas
+// existentes: NeedsRehash indica quando um hash deve ser regerado no próximo login.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// Parâmetros padrão do argon2id (recomendação da OWASP: 64 MiB, 3 iterações)
+const (
+	DefaultArgon2Memory      = 64 * 1024
+	DefaultArgon2Iterations  = 3
+	DefaultArgon2Parallelism = 2
+	DefaultBcryptCost        = 12
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var errMalformedHash = errors.New("malformed password hash")
+
+type Config struct {
+	// Algorithm é usado nos novos hashes: argon2id (padrão) ou bcrypt
+	Algorithm string
+	// Memory em KiB
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	BcryptCost        int
+}
+
+// Hasher gera hashes no algoritmo configurado e confere hashes de qualquer algoritmo suportado
+type Hasher struct {
+	cfg Config
+}
+
+// NewHasher valida a configuração, preenchendo os valores ausentes com os padrões
+func NewHasher(cfg Config) (*Hasher, error) {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = AlgorithmArgon2id
+	}
+	if cfg.Argon2Memory == 0 {
+		cfg.Argon2Memory = DefaultArgon2Memory
+	}
+	if cfg.Argon2Iterations == 0 {
+		cfg.Argon2Iterations = DefaultArgon2Iterations
+	}
+	if cfg.Argon2Parallelism == 0 {
+		cfg.Argon2Parallelism = DefaultArgon2Parallelism
+	}
+	if cfg.BcryptCost == 0 {
+		cfg.BcryptCost = DefaultBcryptCost
+	}
+
+	switch {
+	case cfg.Algorithm != AlgorithmArgon2id && cfg.Algorithm != AlgorithmBcrypt:
+		return nil, fmt.Errorf("unsupported password algorithm %q", cfg.Algorithm)
+	case cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost:
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	return &Hasher{cfg: cfg}, nil
+}
+
+// Hash gera o hash de password no formato PHC ($argon2id$v=19$m=...,t=...,p=...$salt$hash)
+// ou no formato nativo do bcrypt
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.cfg.Algorithm == AlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+		return string(hash), err
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	params := argon2Params{memory: h.cfg.Argon2Memory, iterations: h.cfg.Argon2Iterations, parallelism: h.cfg.Argon2Parallelism}
+	key := params.derive(password, salt, argon2KeyLength)
+
+	encoding := base64.RawStdEncoding
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.memory, params.iterations, params.parallelism,
+		encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// Verify confere password com encoded em tempo constante. Hashes malformados retornam erro.
+func (h *Hasher) Verify(password, encoded string) (bool, error) {
+	if isBcrypt(encoded) {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	params, salt, key, err := parseArgon2(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	derived := params.derive(password, salt, uint32(len(key)))
+	return subtle.ConstantTimeCompare(derived, key) == 1, nil
+}
+
+// NeedsRehash informa se encoded foi gerado com outro algoritmo ou parâmetros diferentes dos atuais
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	if isBcrypt(encoded) {
+		if h.cfg.Algorithm != AlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost != h.cfg.BcryptCost
+	}
+
+	params, _, _, err := parseArgon2(encoded)
+	if err != nil || h.cfg.Algorithm != AlgorithmArgon2id {
+		return true
+	}
+	return params != argon2Params{memory: h.cfg.Argon2Memory, iterations: h.cfg.Argon2Iterations, parallelism: h.cfg.Argon2Parallelism}
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func (p argon2Params) derive(password string, salt []byte, keyLength uint32) []byte {
+	return argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, keyLength)
+}
+
+func parseArgon2(encoded string) (argon2Params, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != AlgorithmArgon2id {
+		return argon2Params{}, nil, nil, errMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return argon2Params{}, nil, nil, errMalformedHash
+	}
+
+	var params argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return argon2Params{}, nil, nil, errMalformedHash
+	}
+
+	salt, errSalt := base64.RawStdEncoding.DecodeString(parts[4])
+	key, errKey := base64.RawStdEncoding.DecodeString(parts[5])
+	if errSalt != nil || errKey != nil || len(key) == 0 {
+		return argon2Params{}, nil, nil, errMalformedHash
+	}
+
+	return params, salt, key, nil
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
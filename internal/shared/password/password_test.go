@@ -0,0 +1,79 @@
+package password
+
+import (
+	"strings"
+	"testing"
+)
+
+// Parâmetros baixos para os testes não gastarem 64 MiB por hash
+var fastArgon2 = Config{Algorithm: AlgorithmArgon2id, Argon2Memory: 1024, Argon2Iterations: 1, Argon2Parallelism: 1}
+
+func TestHasher(t *testing.T) {
+	argon, _ := NewHasher(fastArgon2)
+	bcryptHasher, _ := NewHasher(Config{Algorithm: AlgorithmBcrypt, BcryptCost: 4})
+
+	for name, hasher := range map[string]*Hasher{"argon2id": argon, "bcrypt": bcryptHasher} {
+		t.Run(name+" hashes and verifies", func(t *testing.T) {
+			hash, err := hasher.Hash("correct horse battery")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Contains(hash, "correct horse") {
+				t.Error("Expected password not to appear in the hash")
+			}
+
+			if ok, err := hasher.Verify("correct horse battery", hash); !ok || err != nil {
+				t.Errorf("Expected password to match, got %v, %v", ok, err)
+			}
+			if ok, _ := hasher.Verify("wrong horse battery", hash); ok {
+				t.Error("Expected wrong password not to match")
+			}
+			if hasher.NeedsRehash(hash) {
+				t.Error("Expected fresh hash not to need rehash")
+			}
+		})
+	}
+
+	t.Run("Salts differ between hashes", func(t *testing.T) {
+		first, _ := argon.Hash("same password")
+		second, _ := argon.Hash("same password")
+		if first == second {
+			t.Error("Expected different hashes for the same password")
+		}
+	})
+
+	t.Run("Changed parameters or algorithm need rehash", func(t *testing.T) {
+		hash, _ := argon.Hash("correct horse battery")
+
+		stronger := fastArgon2
+		stronger.Argon2Iterations = 2
+		strongerHasher, _ := NewHasher(stronger)
+
+		if !strongerHasher.NeedsRehash(hash) {
+			t.Error("Expected rehash after raising iterations")
+		}
+		if ok, _ := strongerHasher.Verify("correct horse battery", hash); !ok {
+			t.Error("Expected old hash to keep verifying with new parameters")
+		}
+
+		legacy, _ := bcryptHasher.Hash("correct horse battery")
+		if !argon.NeedsRehash(legacy) {
+			t.Error("Expected bcrypt hash to need rehash when argon2id is configured")
+		}
+		if ok, _ := argon.Verify("correct horse battery", legacy); !ok {
+			t.Error("Expected bcrypt hash to verify under argon2id configuration")
+		}
+	})
+
+	t.Run("Malformed hash", func(t *testing.T) {
+		if _, err := argon.Verify("x", "$argon2id$v=19$broken"); err == nil {
+			t.Error("Expected error for malformed hash")
+		}
+	})
+
+	t.Run("Invalid config", func(t *testing.T) {
+		if _, err := NewHasher(Config{Algorithm: "md5"}); err == nil {
+			t.Error("Expected error for unsupported algorithm")
+		}
+	})
+}
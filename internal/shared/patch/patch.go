@@ -0,0 +1,171 @@
+// Package patch interpreta corpos de PATCH em JSON Merge Patch (RFC 7396) e, para
+// membros de primeiro nível, JSON Patch (RFC 6902). O resultado é um Document que
+// distingue campo ausente, null e valor, para que cada módulo aplique as mudanças
+// pelos métodos do seu domínio.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+const (
+	ContentTypeMergePatch = "application/merge-patch+json"
+	ContentTypeJSONPatch  = "application/json-patch+json"
+
+	// HeaderAcceptPatch anuncia os formatos aceitos (RFC 5789)
+	HeaderAcceptPatch = "Accept-Patch"
+)
+
+// Códigos das violações de campo
+const (
+	FieldCodeImmutable = "immutable"
+	FieldCodeUnknown   = "unknown"
+	FieldCodeType      = "type"
+)
+
+var (
+	ErrUnsupportedMediaType = apperror.UnsupportedMediaType("UNSUPPORTED_PATCH_FORMAT", "PATCH requires application/merge-patch+json or application/json-patch+json")
+	ErrInvalidPatch         = apperror.BadRequest("INVALID_PATCH", "invalid patch document")
+	ErrPatchRejected        = apperror.Validation("PATCH_REJECTED", "patch changes fields that cannot be changed")
+)
+
+// Document são os membros de primeiro nível do patch; null fica como o literal "null"
+type Document map[string]json.RawMessage
+
+// Bind lê o corpo conforme o Content-Type da requisição
+func Bind(c *gin.Context) (Document, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != ContentTypeMergePatch && mediaType != ContentTypeJSONPatch {
+		return nil, ErrUnsupportedMediaType
+	}
+	if c.Request.Body == nil {
+		return nil, ErrInvalidPatch
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, ErrInvalidPatch.Wrap(err)
+	}
+
+	if mediaType == ContentTypeJSONPatch {
+		return fromJSONPatch(body)
+	}
+	return fromMergePatch(body)
+}
+
+// AcceptPatch é o valor do header Accept-Patch
+func AcceptPatch() string {
+	return ContentTypeMergePatch + ", " + ContentTypeJSONPatch
+}
+
+func fromMergePatch(body []byte) (Document, error) {
+	// Um merge patch que não é objeto substituiria o recurso inteiro; não faz sentido aqui
+	var doc Document
+	if err := json.Unmarshal(body, &doc); err != nil || doc == nil {
+		return nil, ErrInvalidPatch.WithMessage("merge patch must be a JSON object").Wrap(err)
+	}
+	return doc, nil
+}
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// fromJSONPatch converte add, replace e remove de membros de primeiro nível no
+// Document equivalente; as demais operações e caminhos aninhados são recusados
+func fromJSONPatch(body []byte) (Document, error) {
+	var operations []operation
+	if err := json.Unmarshal(body, &operations); err != nil {
+		return nil, ErrInvalidPatch.WithMessage("JSON patch must be an array of operations").Wrap(err)
+	}
+
+	doc := Document{}
+	for _, op := range operations {
+		name, ok := strings.CutPrefix(op.Path, "/")
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return nil, ErrInvalidPatch.WithMessage("JSON patch path must point to a top-level member: " + op.Path)
+		}
+		// Escapes da RFC 6901
+		name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return nil, ErrInvalidPatch.WithMessage("JSON patch operation " + op.Op + " requires a value")
+			}
+			doc[name] = op.Value
+		case "remove":
+			doc[name] = json.RawMessage("null")
+		default:
+			return nil, ErrInvalidPatch.WithMessage("unsupported JSON patch operation: " + op.Op)
+		}
+	}
+	return doc, nil
+}
+
+// Check recusa com ErrPatchRejected os campos imutáveis e os desconhecidos, todos de uma vez
+func (d Document) Check(mutable, immutable []string) error {
+	var violations []apperror.FieldError
+	for _, name := range d.names() {
+		switch {
+		case slices.Contains(mutable, name):
+		case slices.Contains(immutable, name):
+			violations = append(violations, apperror.FieldError{Field: name, Code: FieldCodeImmutable, Message: name + " cannot be changed"})
+		default:
+			violations = append(violations, apperror.FieldError{Field: name, Code: FieldCodeUnknown, Message: name + " is not a known field"})
+		}
+	}
+
+	if len(violations) > 0 {
+		return ErrPatchRejected.WithFields(violations...)
+	}
+	return nil
+}
+
+// names devolve os membros em ordem, para que as violações saiam sempre iguais
+func (d Document) names() []string {
+	names := make([]string, 0, len(d))
+	for name := range d {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Optional é um campo do patch: ausente (Present false), removido (Null) ou com Value
+type Optional[T any] struct {
+	Present bool
+	Null    bool
+	Value   T
+}
+
+// Field decodifica o campo name do documento
+func Field[T any](d Document, name string) (Optional[T], error) {
+	raw, ok := d[name]
+	if !ok {
+		return Optional[T]{}, nil
+	}
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return Optional[T]{Present: true, Null: true}, nil
+	}
+
+	var value T
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return Optional[T]{}, ErrPatchRejected.WithMessage("invalid patch value").WithFields(apperror.FieldError{
+			Field:   name,
+			Code:    FieldCodeType,
+			Message: name + " has an invalid type",
+		})
+	}
+	return Optional[T]{Present: true, Value: value}, nil
+}
@@ -0,0 +1,105 @@
+package patch
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+func bind(contentType, body string) (Document, error) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", contentType)
+	return Bind(c)
+}
+
+func TestBind(t *testing.T) {
+	t.Run("Merge patch keeps null apart from absent", func(t *testing.T) {
+		doc, err := bind(ContentTypeMergePatch+"; charset=utf-8", `{"name": "Ana", "nickname": null}`)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		name, _ := Field[string](doc, "name")
+		nickname, _ := Field[string](doc, "nickname")
+		email, _ := Field[string](doc, "email")
+		if !name.Present || name.Null || name.Value != "Ana" {
+			t.Errorf("Expected name Ana, got %+v", name)
+		}
+		if !nickname.Present || !nickname.Null {
+			t.Errorf("Expected nickname to be null, got %+v", nickname)
+		}
+		if email.Present {
+			t.Errorf("Expected email to be absent, got %+v", email)
+		}
+	})
+
+	t.Run("JSON patch is converted to the same document", func(t *testing.T) {
+		doc, err := bind(ContentTypeJSONPatch, `[{"op": "replace", "path": "/name", "value": "Ana"}, {"op": "remove", "path": "/nickname"}]`)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(doc["name"]) != `"Ana"` || string(doc["nickname"]) != "null" {
+			t.Errorf("Unexpected document: %v", doc)
+		}
+	})
+
+	t.Run("Rejects other media types", func(t *testing.T) {
+		if _, err := bind("application/json", `{"name": "Ana"}`); !errors.Is(err, ErrUnsupportedMediaType) {
+			t.Errorf("Expected ErrUnsupportedMediaType, got %v", err)
+		}
+	})
+
+	t.Run("Rejects invalid documents", func(t *testing.T) {
+		cases := []struct{ contentType, body string }{
+			{ContentTypeMergePatch, `["name"]`},
+			{ContentTypeMergePatch, `null`},
+			{ContentTypeJSONPatch, `{"op": "replace"}`},
+			{ContentTypeJSONPatch, `[{"op": "move", "from": "/a", "path": "/name"}]`},
+			{ContentTypeJSONPatch, `[{"op": "replace", "path": "/address/city", "value": "x"}]`},
+			{ContentTypeJSONPatch, `[{"op": "add", "path": "/name"}]`},
+		}
+		for _, tc := range cases {
+			if _, err := bind(tc.contentType, tc.body); !errors.Is(err, ErrInvalidPatch) {
+				t.Errorf("Expected ErrInvalidPatch for %s, got %v", tc.body, err)
+			}
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	doc := Document{"name": []byte(`"Ana"`), "email": []byte(`"a@b.com"`), "color": []byte(`"red"`)}
+
+	err := doc.Check([]string{"name"}, []string{"id", "email"})
+	if !errors.Is(err, ErrPatchRejected) {
+		t.Fatalf("Expected ErrPatchRejected, got %v", err)
+	}
+
+	appErr, _ := apperror.As(err)
+	if len(appErr.Fields) != 2 {
+		t.Fatalf("Expected 2 violations, got %+v", appErr.Fields)
+	}
+	if appErr.Fields[0].Field != "color" || appErr.Fields[0].Code != FieldCodeUnknown {
+		t.Errorf("Expected unknown color, got %+v", appErr.Fields[0])
+	}
+	if appErr.Fields[1].Field != "email" || appErr.Fields[1].Code != FieldCodeImmutable {
+		t.Errorf("Expected immutable email, got %+v", appErr.Fields[1])
+	}
+
+	if err := (Document{"name": []byte(`"Ana"`)}).Check([]string{"name"}, nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestField(t *testing.T) {
+	doc := Document{"name": []byte(`42`)}
+	if _, err := Field[string](doc, "name"); !errors.Is(err, ErrPatchRejected) {
+		t.Errorf("Expected ErrPatchRejected for a wrong type, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Package problem traduz erros da aplicação em respostas RFC 7807 (application/problem+json)
+package problem
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+const (
+	ContentType = "application/problem+json"
+
+	// TypePrefix compõe o campo "type" a partir do código do erro
+	TypePrefix = "urn:problem-type:"
+
+	CodeInternal = "INTERNAL_ERROR"
+)
+
+// Problem é o corpo de erro padronizado pela RFC 7807, estendido com o código
+// estável do erro e os detalhes de validação por campo
+type Problem struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	Code     string                `json:"code"`
+	Errors   []apperror.FieldError `json:"errors,omitempty"`
+}
+
+var statusByKind = map[apperror.Kind]int{
+	apperror.KindBadRequest:           http.StatusBadRequest,
+	apperror.KindValidation:           http.StatusUnprocessableEntity,
+	apperror.KindUnauthorized:         http.StatusUnauthorized,
+	apperror.KindForbidden:            http.StatusForbidden,
+	apperror.KindNotFound:             http.StatusNotFound,
+	apperror.KindConflict:             http.StatusConflict,
+	apperror.KindRateLimited:          http.StatusTooManyRequests,
+	apperror.KindPreconditionFailed:   http.StatusPreconditionFailed,
+	apperror.KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	apperror.KindPayloadTooLarge:      http.StatusRequestEntityTooLarge,
+}
+
+// StatusOf retorna o status HTTP correspondente à categoria do erro
+func StatusOf(err error) int {
+	if status, ok := statusByKind[apperror.KindOf(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// From monta o Problem de err. Erros não tipados viram 500 sem expor a mensagem original.
+func From(err error, instance string) Problem {
+	status := StatusOf(err)
+
+	appErr, ok := apperror.As(err)
+	if !ok || status == http.StatusInternalServerError {
+		return Problem{
+			Type:     TypePrefix + typeName(CodeInternal),
+			Title:    http.StatusText(http.StatusInternalServerError),
+			Status:   http.StatusInternalServerError,
+			Detail:   "An unexpected error occurred",
+			Instance: instance,
+			Code:     CodeInternal,
+		}
+	}
+
+	return Problem{
+		Type:     TypePrefix + typeName(appErr.Code),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: instance,
+		Code:     appErr.Code,
+		Errors:   appErr.Fields,
+	}
+}
+
+// Respond escreve err como problem+json. Erros internos são registrados no log
+// com a causa original, já que ela não é devolvida ao cliente.
+func Respond(c *gin.Context, err error) {
+	p := From(err, c.Request.URL.Path)
+
+	if p.Status == http.StatusInternalServerError {
+		logger.WithFields(logrus.Fields{
+			"method": c.Request.Method,
+			"path":   c.Request.URL.Path,
+		}).Errorf("Request failed: %v", err)
+	}
+
+	Write(c, p)
+}
+
+// Abort responde com err e interrompe a cadeia de handlers (uso em middlewares)
+func Abort(c *gin.Context, err error) {
+	Respond(c, err)
+	c.Abort()
+}
+
+// Write serializa p com o content type da RFC 7807
+func Write(c *gin.Context, p Problem) {
+	c.Header("Content-Type", ContentType)
+	c.JSON(p.Status, p)
+}
+
+// typeName converte o código (USER_NOT_FOUND) para o formato usado no type (user-not-found)
+func typeName(code string) string {
+	return strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
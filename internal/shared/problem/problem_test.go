@@ -0,0 +1,98 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+var errNotFound = apperror.NotFound("ORDER_NOT_FOUND", "order not found")
+
+func respond(err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+
+	Respond(c, err)
+	return recorder
+}
+
+func decode(t *testing.T, recorder *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	var p Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &p); err != nil {
+		t.Fatalf("Failed to decode body: %v", err)
+	}
+	return p
+}
+
+func TestRespond(t *testing.T) {
+	t.Run("Typed error is rendered as problem+json", func(t *testing.T) {
+		recorder := respond(fmt.Errorf("loading order: %w", errNotFound))
+
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", recorder.Code)
+		}
+		if got := recorder.Header().Get("Content-Type"); got != ContentType {
+			t.Errorf("Expected content type %s, got %s", ContentType, got)
+		}
+
+		p := decode(t, recorder)
+		if p.Code != "ORDER_NOT_FOUND" || p.Type != "urn:problem-type:order-not-found" {
+			t.Errorf("Unexpected code/type: %+v", p)
+		}
+		if p.Detail != "order not found" || p.Instance != "/orders/42" || p.Status != http.StatusNotFound {
+			t.Errorf("Unexpected problem: %+v", p)
+		}
+	})
+
+	t.Run("Validation error carries field details", func(t *testing.T) {
+		err := apperror.Validation("INVALID_ORDER", "invalid order",
+			apperror.FieldError{Field: "quantity", Code: "min", Message: "quantity must be positive"},
+		)
+
+		recorder := respond(err)
+		if recorder.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status 422, got %d", recorder.Code)
+		}
+
+		p := decode(t, recorder)
+		if len(p.Errors) != 1 || p.Errors[0].Field != "quantity" {
+			t.Errorf("Expected field error for quantity, got %+v", p.Errors)
+		}
+	})
+
+	t.Run("Unknown error becomes 500 without leaking details", func(t *testing.T) {
+		recorder := respond(errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+
+		if recorder.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", recorder.Code)
+		}
+
+		p := decode(t, recorder)
+		if p.Code != CodeInternal || p.Detail != "An unexpected error occurred" {
+			t.Errorf("Unexpected problem: %+v", p)
+		}
+	})
+}
+
+func TestErrorIdentity(t *testing.T) {
+	enriched := errNotFound.WithFields(apperror.FieldError{Field: "id"}).Wrap(errors.New("record not found"))
+
+	if !errors.Is(enriched, errNotFound) {
+		t.Error("Expected enriched error to match its sentinel")
+	}
+	if errors.Is(enriched, apperror.NotFound("OTHER", "other")) {
+		t.Error("Expected errors with different codes not to match")
+	}
+	if len(errNotFound.Fields) != 0 {
+		t.Error("Expected sentinel not to be mutated")
+	}
+}
@@ -0,0 +1,155 @@
+// Package ratelimit limita requisições por chave (API key, usuário, IP) com token
+// bucket. O estado fica em um Store: MemoryStore guarda os buckets na instância;
+// para limites compartilhados entre réplicas basta implementar Store sobre um
+// armazenamento comum (ex.: Redis).
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Policy permite Requests requisições por Window, com rajadas de até Burst
+// (padrão: Requests). Requests <= 0 desliga o limite.
+type Policy struct {
+	// Name separa os buckets de políticas diferentes que usam a mesma chave
+	Name     string
+	Requests int
+	Window   time.Duration
+	Burst    int
+}
+
+// Enabled informa se a política limita alguma coisa
+func (p Policy) Enabled() bool {
+	return p.Requests > 0 && p.Window > 0
+}
+
+// Capacity é o tamanho do bucket
+func (p Policy) Capacity() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Requests
+}
+
+// interval é o tempo para repor um token
+func (p Policy) interval() time.Duration {
+	return p.Window / time.Duration(p.Requests)
+}
+
+// Result descreve o bucket depois de uma tentativa
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter é o tempo até o bucket voltar a ficar cheio
+	ResetAfter time.Duration
+	// RetryAfter é o tempo até a próxima requisição ser aceita (zero quando Allowed)
+	RetryAfter time.Duration
+}
+
+// Store consome tokens dos buckets. Implementações precisam ser seguras para uso concorrente.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+// maxBuckets dispara a limpeza dos buckets já cheios, que equivalem a buckets inexistentes
+const maxBuckets = 10_000
+
+// MemoryStore guarda os buckets em memória; o limite vale por instância
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	capacity := policy.Capacity()
+	if !policy.Enabled() {
+		return Result{Allowed: true, Limit: capacity, Remaining: capacity}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	key = policy.Name + ":" + key
+	b, ok := s.buckets[key]
+	if !ok {
+		if len(s.buckets) >= maxBuckets {
+			s.prune(policy, now)
+		}
+		b = &bucket{tokens: float64(capacity), updatedAt: now}
+		s.buckets[key] = b
+	}
+	b.refill(policy, now)
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	result := Result{
+		Allowed:    allowed,
+		Limit:      capacity,
+		Remaining:  int(b.tokens),
+		ResetAfter: durationFor(float64(capacity)-b.tokens, policy),
+	}
+	if !allowed {
+		result.RetryAfter = durationFor(1-b.tokens, policy)
+	}
+	return result, nil
+}
+
+// refill repõe os tokens acumulados desde a última atualização, até a capacidade
+func (b *bucket) refill(policy Policy, now time.Time) {
+	elapsed := now.Sub(b.updatedAt)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = math.Min(float64(policy.Capacity()), b.tokens+float64(elapsed)/float64(policy.interval()))
+	b.updatedAt = now
+}
+
+// prune remove os buckets que já estariam cheios. Buckets de outras políticas usam
+// a janela de policy como aproximação.
+func (s *MemoryStore) prune(policy Policy, now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.updatedAt) >= policy.Window {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// durationFor é o tempo para repor tokens
+func durationFor(tokens float64, policy Policy) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(tokens * float64(policy.interval())))
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// Limits reúne o store e as políticas configuradas, entregues aos módulos para
+// montar os limites das suas rotas
+type Limits struct {
+	Store Store
+	// PerIP vale para toda a API, por IP
+	PerIP Policy
+	// PerKey vale nas rotas autenticadas, por credencial
+	PerKey Policy
+	// Auth vale nas rotas de login, por IP
+	Auth Policy
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	policy := Policy{Name: "test", Requests: 2, Window: time.Minute}
+
+	newStore := func() (*MemoryStore, *time.Time) {
+		now := time.Now()
+		store := NewMemoryStore()
+		store.now = func() time.Time { return now }
+		return store, &now
+	}
+
+	t.Run("Empties the bucket and refills over time", func(t *testing.T) {
+		store, now := newStore()
+
+		for i := range 2 {
+			result, _ := store.Take(ctx, "client", policy)
+			if !result.Allowed || result.Remaining != 1-i {
+				t.Fatalf("Expected request %d to be allowed, got %+v", i, result)
+			}
+		}
+
+		result, _ := store.Take(ctx, "client", policy)
+		if result.Allowed || result.RetryAfter != 30*time.Second {
+			t.Errorf("Expected denial with a 30s retry, got %+v", result)
+		}
+
+		*now = now.Add(30 * time.Second)
+		if result, _ := store.Take(ctx, "client", policy); !result.Allowed {
+			t.Errorf("Expected a token after 30s, got %+v", result)
+		}
+	})
+
+	t.Run("Keys and policies have separate buckets", func(t *testing.T) {
+		store, _ := newStore()
+		other := Policy{Name: "other", Requests: 1, Window: time.Minute}
+
+		_, _ = store.Take(ctx, "a", other)
+		if result, _ := store.Take(ctx, "b", other); !result.Allowed {
+			t.Error("Expected another key to have its own bucket")
+		}
+		if result, _ := store.Take(ctx, "a", policy); !result.Allowed {
+			t.Error("Expected another policy to have its own bucket")
+		}
+	})
+
+	t.Run("Burst sets the capacity", func(t *testing.T) {
+		store, _ := newStore()
+		burst := Policy{Name: "burst", Requests: 1, Window: time.Minute, Burst: 3}
+
+		for range 3 {
+			if result, _ := store.Take(ctx, "client", burst); !result.Allowed {
+				t.Fatal("Expected the burst to be allowed")
+			}
+		}
+		if result, _ := store.Take(ctx, "client", burst); result.Allowed || result.Limit != 3 {
+			t.Errorf("Expected denial after the burst, got %+v", result)
+		}
+	})
+
+	t.Run("Disabled policy", func(t *testing.T) {
+		store, _ := newStore()
+
+		for range 10 {
+			if result, _ := store.Take(ctx, "client", Policy{Name: "off"}); !result.Allowed {
+				t.Fatal("Expected every request to be allowed")
+			}
+		}
+	})
+}
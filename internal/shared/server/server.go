@@ -0,0 +1,58 @@
+// Package server encapsula o ciclo de vida do servidor HTTP da aplicação
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/config"
+	"github.com/vynazevedo/go-modular-monolith/pkg/logger"
+)
+
+// New cria um http.Server com os timeouts definidos na configuração
+func New(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// Run inicia o servidor e bloqueia até que ctx seja cancelado ou o servidor falhe.
+// No cancelamento, as requisições em andamento são drenadas dentro de shutdownTimeout.
+func Run(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration) error {
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case err, ok := <-serverErr:
+		if !ok {
+			return nil
+		}
+		return fmt.Errorf("http server: %w", err)
+	case <-ctx.Done():
+	}
+
+	logger.Infof("Shutting down HTTP server (grace period %s)", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("http server shutdown: %w", err)
+	}
+
+	logger.Info("HTTP server stopped")
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package signedtoken serializa payloads JSON em tokens opacos assinados com HMAC-SHA256.
+// Seal e Open são a base (ex.: cursores de paginação); Issue e Parse acrescentam propósito
+// e validade para links enviados fora da API (ex.: confirmação de e-mail), de modo que um
+// token emitido para um fluxo não valha em outro.
+package signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MinSecretLength é o tamanho mínimo do segredo, igual ao tamanho do hash do HMAC-SHA256
+const MinSecretLength = 32
+
+var (
+	// ErrInvalid indica token malformado, adulterado ou emitido para outro propósito
+	ErrInvalid = errors.New("signed token is invalid")
+	ErrExpired = errors.New("signed token has expired")
+
+	ErrMissingSecret = errors.New("signed token secret is required")
+	ErrShortSecret   = fmt.Errorf("signed token secret must have at least %d bytes", MinSecretLength)
+)
+
+type Codec struct {
+	secret []byte
+}
+
+// envelope é o conteúdo assinado dos tokens de Issue
+type envelope struct {
+	Purpose   string          `json:"p"`
+	ExpiresAt int64           `json:"exp"`
+	Data      json.RawMessage `json:"d"`
+}
+
+// NewCodec cria o codec com o segredo compartilhado entre as instâncias; segredos curtos
+// são recusados, já que os tokens assinam links de conta
+func NewCodec(secret string) (*Codec, error) {
+	if secret == "" {
+		return nil, ErrMissingSecret
+	}
+	if len(secret) < MinSecretLength {
+		return nil, ErrShortSecret
+	}
+	return &Codec{secret: []byte(secret)}, nil
+}
+
+// NewEphemeralCodec cria o codec com uma chave aleatória: os tokens deixam de valer a
+// cada restart e não são aceitos por outras instâncias. Só para desenvolvimento.
+func NewEphemeralCodec() *Codec {
+	key := make([]byte, MinSecretLength)
+	_, _ = rand.Read(key)
+	return &Codec{secret: key}
+}
+
+// Seal serializa payload em JSON e devolve payload.assinatura em base64 URL-safe
+func (c *Codec) Seal(payload any) (string, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(raw) + "." + encoding.EncodeToString(c.sign(raw)), nil
+}
+
+// Open valida a assinatura do token e preenche payload; qualquer problema retorna ErrInvalid
+func (c *Codec) Open(token string, payload any) error {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalid
+	}
+
+	encoding := base64.RawURLEncoding
+	raw, err := encoding.DecodeString(encodedPayload)
+	if err != nil {
+		return ErrInvalid
+	}
+	signature, err := encoding.DecodeString(encodedSignature)
+	if err != nil {
+		return ErrInvalid
+	}
+	if !hmac.Equal(signature, c.sign(raw)) {
+		return ErrInvalid
+	}
+	if err := json.Unmarshal(raw, payload); err != nil {
+		return ErrInvalid
+	}
+
+	return nil
+}
+
+// Issue serializa data em um token válido para purpose até expiresAt
+func (c *Codec) Issue(purpose string, data any, expiresAt time.Time) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return c.Seal(envelope{Purpose: purpose, ExpiresAt: expiresAt.Unix(), Data: raw})
+}
+
+// Parse valida assinatura, propósito e validade do token e preenche data.
+// Tokens vencidos retornam ErrExpired; os demais problemas, ErrInvalid.
+func (c *Codec) Parse(purpose, token string, data any, now time.Time) error {
+	var env envelope
+	if err := c.Open(token, &env); err != nil {
+		return err
+	}
+	if env.Purpose != purpose {
+		return ErrInvalid
+	}
+	if !now.Before(time.Unix(env.ExpiresAt, 0)) {
+		return ErrExpired
+	}
+	if err := json.Unmarshal(env.Data, data); err != nil {
+		return ErrInvalid
+	}
+
+	return nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,120 @@
+package signedtoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type claims struct {
+	UserID string `json:"uid"`
+	Email  string `json:"email"`
+}
+
+func TestNewCodec(t *testing.T) {
+	if _, err := NewCodec(""); !errors.Is(err, ErrMissingSecret) {
+		t.Errorf("Expected ErrMissingSecret, got %v", err)
+	}
+	if _, err := NewCodec("change-me"); !errors.Is(err, ErrShortSecret) {
+		t.Errorf("Expected ErrShortSecret, got %v", err)
+	}
+
+	t.Run("Ephemeral codecs do not accept each other's tokens", func(t *testing.T) {
+		token, _ := NewEphemeralCodec().Seal(claims{UserID: "u1"})
+
+		var got claims
+		if err := NewEphemeralCodec().Open(token, &got); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+	})
+}
+
+// mustCodec cria o codec com secret repetido até o tamanho mínimo
+func mustCodec(t *testing.T, secret string) *Codec {
+	t.Helper()
+
+	codec, err := NewCodec(strings.Repeat(secret, MinSecretLength/len(secret)+1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return codec
+}
+
+func TestSeal(t *testing.T) {
+	codec := mustCodec(t, "secret")
+
+	t.Run("Round trip", func(t *testing.T) {
+		token, err := codec.Seal(claims{UserID: "u1", Email: "ana@example.com"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var got claims
+		if err := codec.Open(token, &got); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got.UserID != "u1" || got.Email != "ana@example.com" {
+			t.Errorf("Unexpected claims: %+v", got)
+		}
+	})
+
+	t.Run("Rejects tampered and foreign tokens", func(t *testing.T) {
+		token, _ := codec.Seal(claims{UserID: "u1"})
+		forged, _ := mustCodec(t, "other-secret").Seal(claims{UserID: "u1"})
+		tampered, _ := codec.Seal(claims{UserID: "u2"})
+		tampered = tampered[:len(tampered)-43] + token[len(token)-43:]
+
+		for _, invalid := range []string{"", "garbage", "a.b", forged, tampered} {
+			var got claims
+			if err := codec.Open(invalid, &got); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Expected ErrInvalid for %q, got %v", invalid, err)
+			}
+		}
+	})
+}
+
+func TestCodec(t *testing.T) {
+	codec := mustCodec(t, "secret")
+	now := time.Unix(1700000000, 0)
+	expiresAt := now.Add(time.Hour)
+
+	t.Run("Round trip", func(t *testing.T) {
+		token, err := codec.Issue("verify", claims{UserID: "u1", Email: "ana@example.com"}, expiresAt)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var got claims
+		if err := codec.Parse("verify", token, &got, now); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got.UserID != "u1" || got.Email != "ana@example.com" {
+			t.Errorf("Unexpected claims: %+v", got)
+		}
+	})
+
+	t.Run("Rejects expired tokens", func(t *testing.T) {
+		token, _ := codec.Issue("verify", claims{UserID: "u1"}, expiresAt)
+
+		var got claims
+		if err := codec.Parse("verify", token, &got, expiresAt); !errors.Is(err, ErrExpired) {
+			t.Errorf("Expected ErrExpired, got %v", err)
+		}
+	})
+
+	t.Run("Rejects tampered, foreign and misused tokens", func(t *testing.T) {
+		token, _ := codec.Issue("verify", claims{UserID: "u1"}, expiresAt)
+		forged, _ := mustCodec(t, "other-secret").Issue("verify", claims{UserID: "u1"}, expiresAt)
+		tampered, _ := codec.Issue("verify", claims{UserID: "u2"}, expiresAt)
+		tampered = tampered[:len(tampered)-43] + token[len(token)-43:]
+		otherPurpose, _ := codec.Issue("change_email", claims{UserID: "u1"}, expiresAt)
+
+		for _, invalid := range []string{"", "garbage", "a.b", forged, tampered, otherPurpose} {
+			var got claims
+			if err := codec.Parse("verify", invalid, &got, now); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Expected ErrInvalid for %q, got %v", invalid, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,49 @@
+// Package transaction implementa Unit of Work sobre o GORM: a transação aberta
+// é guardada no context.Context e reutilizada por todos os repositórios da chamada.
+package transaction
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type ctxKey struct{}
+
+// Manager executa casos de uso dentro de uma transação
+type Manager interface {
+	// Do executa fn em uma transação. Se ctx já carrega uma transação, fn roda em um
+	// savepoint dela. Qualquer erro ou panic em fn desfaz as alterações.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type GormManager struct {
+	db *gorm.DB
+}
+
+func NewManager(db *gorm.DB) *GormManager {
+	return &GormManager{db: db}
+}
+
+func (m *GormManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return DB(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, ctxKey{}, tx))
+	})
+}
+
+// DB retorna a transação presente em ctx ou, na ausência dela, a conexão padrão.
+// Repositórios devem obter a conexão sempre por aqui para participar da Unit of Work.
+func DB(ctx context.Context, fallback *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(ctxKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return fallback.WithContext(ctx)
+}
+
+// InTransaction informa se ctx carrega uma transação aberta
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(ctxKey{}).(*gorm.DB)
+	return ok
+}
+
+var _ Manager = (*GormManager)(nil)
@@ -0,0 +1,169 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type recordModel struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tx.db")), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&recordModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+func insert(ctx context.Context, db *gorm.DB, name string) error {
+	return DB(ctx, db).Create(&recordModel{Name: name}).Error
+}
+
+func names(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var records []recordModel
+	if err := db.Order("id").Find(&records).Error; err != nil {
+		t.Fatalf("Failed to list records: %v", err)
+	}
+	result := make([]string, len(records))
+	for i, record := range records {
+		result[i] = record.Name
+	}
+	return result
+}
+
+func TestManagerDo(t *testing.T) {
+	t.Run("Commits all writes made through the context", func(t *testing.T) {
+		db := newTestDB(t)
+		manager := NewManager(db)
+
+		err := manager.Do(context.Background(), func(ctx context.Context) error {
+			if !InTransaction(ctx) {
+				t.Error("Expected context to carry the transaction")
+			}
+			if err := insert(ctx, db, "a"); err != nil {
+				return err
+			}
+			return insert(ctx, db, "b")
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := names(t, db); len(got) != 2 {
+			t.Errorf("Expected 2 records, got %v", got)
+		}
+	})
+
+	t.Run("Rolls back on error", func(t *testing.T) {
+		db := newTestDB(t)
+		manager := NewManager(db)
+		failure := errors.New("failure")
+
+		err := manager.Do(context.Background(), func(ctx context.Context) error {
+			if err := insert(ctx, db, "a"); err != nil {
+				return err
+			}
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			t.Fatalf("Expected failure, got %v", err)
+		}
+
+		if got := names(t, db); len(got) != 0 {
+			t.Errorf("Expected no records, got %v", got)
+		}
+	})
+
+	t.Run("Rolls back on panic", func(t *testing.T) {
+		db := newTestDB(t)
+		manager := NewManager(db)
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected panic to be propagated")
+				}
+			}()
+
+			_ = manager.Do(context.Background(), func(ctx context.Context) error {
+				_ = insert(ctx, db, "a")
+				panic("boom")
+			})
+		}()
+
+		if got := names(t, db); len(got) != 0 {
+			t.Errorf("Expected no records, got %v", got)
+		}
+	})
+
+	t.Run("Nested failure rolls back only its savepoint", func(t *testing.T) {
+		db := newTestDB(t)
+		manager := NewManager(db)
+
+		err := manager.Do(context.Background(), func(ctx context.Context) error {
+			if err := insert(ctx, db, "outer"); err != nil {
+				return err
+			}
+
+			nestedErr := manager.Do(ctx, func(ctx context.Context) error {
+				if err := insert(ctx, db, "inner"); err != nil {
+					return err
+				}
+				return errors.New("inner failure")
+			})
+			if nestedErr == nil {
+				t.Error("Expected nested error")
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		got := names(t, db)
+		if len(got) != 1 || got[0] != "outer" {
+			t.Errorf("Expected only the outer record, got %v", got)
+		}
+	})
+
+	t.Run("Nested writes follow the outer transaction", func(t *testing.T) {
+		db := newTestDB(t)
+		manager := NewManager(db)
+
+		err := manager.Do(context.Background(), func(ctx context.Context) error {
+			if err := manager.Do(ctx, func(ctx context.Context) error {
+				return insert(ctx, db, "inner")
+			}); err != nil {
+				return err
+			}
+			return errors.New("outer failure")
+		})
+		if err == nil {
+			t.Fatal("Expected outer failure")
+		}
+
+		if got := names(t, db); len(got) != 0 {
+			t.Errorf("Expected nested write to be rolled back with the outer transaction, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+// Package validation decodifica e valida corpos de requisição de forma declarativa,
+// usando as tags `binding` do validator do Gin, e reporta todas as violações por campo.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+var (
+	ErrInvalidBody  = apperror.BadRequest("INVALID_REQUEST_BODY", "invalid request body")
+	ErrInvalidQuery = apperror.BadRequest("INVALID_QUERY", "invalid query parameters")
+	ErrValidation   = apperror.Validation("VALIDATION_FAILED", "request validation failed")
+)
+
+var setupOnce sync.Once
+
+// Setup configura o validator do Gin (binding.Validator, global) para nomear as violações
+// pelo nome JSON ou de query do campo. Chamado uma vez na montagem do router; chamadas
+// repetidas não têm efeito.
+func Setup() {
+	setupOnce.Do(registerTagNames)
+}
+
+// BindJSON decodifica o corpo JSON em dst, remove espaços das pontas de todos os
+// campos string e valida as tags `binding`. Retorna ErrInvalidBody para JSON
+// malformado e ErrValidation, com uma FieldError por violação, para dados inválidos.
+func BindJSON(c *gin.Context, dst any) error {
+	if c.Request.Body == nil {
+		return ErrInvalidBody
+	}
+
+	if err := json.NewDecoder(c.Request.Body).Decode(dst); err != nil {
+		return ErrInvalidBody.Wrap(err)
+	}
+
+	TrimStrings(dst)
+
+	return Struct(dst)
+}
+
+// BindQuery preenche dst a partir da query string (tags `form`), remove espaços das
+// pontas e valida as tags `binding`. Valores que não podem ser convertidos (datas,
+// números) retornam ErrInvalidQuery com o motivo no detalhe.
+func BindQuery(c *gin.Context, dst any) error {
+	if err := binding.MapFormWithTag(dst, c.Request.URL.Query(), "form"); err != nil {
+		return ErrInvalidQuery.WithMessage("invalid query parameters: " + err.Error()).Wrap(err)
+	}
+
+	TrimStrings(dst)
+
+	return Struct(dst)
+}
+
+// Struct valida as tags `binding` de v e converte as violações em ErrValidation
+func Struct(v any) error {
+	err := binding.Validator.ValidateStruct(v)
+	if err == nil {
+		return nil
+	}
+
+	var violations validator.ValidationErrors
+	if !errors.As(err, &violations) {
+		return err
+	}
+
+	fields := make([]apperror.FieldError, len(violations))
+	for i, violation := range violations {
+		fields[i] = apperror.FieldError{
+			Field:   violation.Field(),
+			Code:    violation.Tag(),
+			Message: message(violation),
+		}
+	}
+
+	return ErrValidation.WithFields(fields...)
+}
+
+// TrimStrings remove espaços das pontas dos campos string (inclusive ponteiros) de uma struct.
+// Campos com a tag `trim:"-"` (ex.: senhas) são mantidos como vieram.
+func TrimStrings(v any) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	value = value.Elem()
+	for i := range value.NumField() {
+		field := value.Field(i)
+		if !field.CanSet() || value.Type().Field(i).Tag.Get("trim") == "-" {
+			continue
+		}
+
+		switch {
+		case field.Kind() == reflect.String:
+			field.SetString(strings.TrimSpace(field.String()))
+		case field.Kind() == reflect.Pointer && !field.IsNil() && field.Elem().Kind() == reflect.String:
+			field.Elem().SetString(strings.TrimSpace(field.Elem().String()))
+		}
+	}
+}
+
+// registerTagNames faz as violações usarem o nome JSON (ou de query) do campo, que é o que o cliente conhece
+func registerTagNames() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+func message(violation validator.FieldError) string {
+	field := violation.Field()
+
+	switch violation.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "max":
+		return fmt.Sprintf("%s must be at most %s characters", field, violation.Param())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s characters", field, violation.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, violation.Param())
+	default:
+		return fmt.Sprintf("%s is invalid (%s)", field, violation.Tag())
+	}
+}
@@ -0,0 +1,137 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vynazevedo/go-modular-monolith/internal/shared/apperror"
+)
+
+type signupRequest struct {
+	Email    string  `json:"email" binding:"required,email,max=255"`
+	Name     string  `json:"name" binding:"required,max=10"`
+	Nickname *string `json:"nickname,omitempty" binding:"omitempty,max=5"`
+}
+
+func bind(body string) (signupRequest, error) {
+	gin.SetMode(gin.TestMode)
+	Setup()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+	var req signupRequest
+	err := BindJSON(c, &req)
+	return req, err
+}
+
+type searchQuery struct {
+	Name  string     `form:"name" binding:"max=5"`
+	Since *time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+func bindQuery(rawQuery string) (searchQuery, error) {
+	gin.SetMode(gin.TestMode)
+	Setup()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+rawQuery, nil)
+
+	var query searchQuery
+	err := BindQuery(c, &query)
+	return query, err
+}
+
+func TestBindQuery(t *testing.T) {
+	t.Run("Maps and trims values", func(t *testing.T) {
+		query, err := bindQuery("name=+ana+&since=2024-01-02T03:04:05Z")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if query.Name != "ana" || query.Since == nil || query.Since.Year() != 2024 {
+			t.Errorf("Unexpected query: %+v", query)
+		}
+	})
+
+	t.Run("Unparseable value", func(t *testing.T) {
+		_, err := bindQuery("since=yesterday")
+		if !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery, got %v", err)
+		}
+	})
+
+	t.Run("Validation rules apply", func(t *testing.T) {
+		_, err := bindQuery("name=too-long")
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("Expected ErrValidation, got %v", err)
+		}
+
+		appErr, _ := apperror.As(err)
+		if appErr.Fields[0].Field != "name" {
+			t.Errorf("Expected violation on query parameter name, got %+v", appErr.Fields)
+		}
+	})
+}
+
+func TestBindJSON(t *testing.T) {
+	t.Run("Valid body is trimmed", func(t *testing.T) {
+		req, err := bind(`{"email": " ana@example.com ", "name": "  Ana ", "nickname": " an "}`)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if req.Email != "ana@example.com" || req.Name != "Ana" || *req.Nickname != "an" {
+			t.Errorf("Expected trimmed values, got %+v", req)
+		}
+	})
+
+	t.Run("Malformed JSON", func(t *testing.T) {
+		_, err := bind(`{"email":`)
+		if !errors.Is(err, ErrInvalidBody) {
+			t.Errorf("Expected ErrInvalidBody, got %v", err)
+		}
+	})
+
+	t.Run("All violations are reported by JSON field name", func(t *testing.T) {
+		_, err := bind(`{"email": "not-an-email", "name": "   ", "nickname": "toolong"}`)
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("Expected ErrValidation, got %v", err)
+		}
+
+		appErr, _ := apperror.As(err)
+		got := map[string]string{}
+		for _, field := range appErr.Fields {
+			got[field.Field] = field.Code
+		}
+
+		want := map[string]string{"email": "email", "name": "required", "nickname": "max"}
+		for field, code := range want {
+			if got[field] != code {
+				t.Errorf("Expected %s violation on %s, got %q", code, field, got[field])
+			}
+		}
+	})
+}
+
+func TestTrimStrings(t *testing.T) {
+	t.Run("Fields tagged trim:\"-\" are kept as sent", func(t *testing.T) {
+		secret := "  pass phrase  "
+		req := struct {
+			Email    string
+			Password string  `trim:"-"`
+			Previous *string `trim:"-"`
+		}{Email: " ana@example.com ", Password: secret, Previous: &secret}
+
+		TrimStrings(&req)
+
+		if req.Email != "ana@example.com" {
+			t.Errorf("Expected trimmed email, got %q", req.Email)
+		}
+		if req.Password != secret || *req.Previous != secret {
+			t.Errorf("Expected untouched passwords, got %q and %q", req.Password, *req.Previous)
+		}
+	})
+}
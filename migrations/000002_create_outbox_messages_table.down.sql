@@ -0,0 +1,2 @@
+-- Rollback: Dropa tabela de outbox
+DROP TABLE IF EXISTS outbox_messages;
@@ -0,0 +1,15 @@
+-- Criação da tabela de outbox para eventos de domínio
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY COMMENT 'Sequência de entrega',
+    event_name VARCHAR(255) NOT NULL COMMENT 'Nome do evento (ex.: user.created)',
+    payload JSON NOT NULL COMMENT 'Evento serializado',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'Status (pending/delivered/dead)',
+    attempts INT NOT NULL DEFAULT 0 COMMENT 'Tentativas de entrega realizadas',
+    last_error TEXT NULL COMMENT 'Último erro de entrega',
+    occurred_at BIGINT NOT NULL COMMENT 'Timestamp do evento em Unix time',
+    next_attempt_at BIGINT NOT NULL COMMENT 'Próxima tentativa em Unix time',
+    created_at BIGINT NOT NULL COMMENT 'Timestamp de criação em Unix time',
+    delivered_at BIGINT NULL COMMENT 'Timestamp da entrega em Unix time',
+
+    INDEX idx_outbox_dispatch (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Outbox transacional de eventos de domínio';
@@ -0,0 +1,2 @@
+-- Rollback: Dropa tabela de API keys
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,13 @@
+-- Criação da tabela de API keys
+CREATE TABLE IF NOT EXISTS api_keys (
+    id VARCHAR(36) NOT NULL PRIMARY KEY COMMENT 'UUID da chave',
+    name VARCHAR(255) NOT NULL COMMENT 'Descrição da chave (time, integração)',
+    prefix VARCHAR(16) NOT NULL COMMENT 'Trecho público do token, usado na busca',
+    secret_hash CHAR(64) NOT NULL COMMENT 'SHA-256 do segredo; o segredo em si não é armazenado',
+    created_at BIGINT NOT NULL COMMENT 'Timestamp de criação em Unix time',
+    expires_at BIGINT NULL COMMENT 'Expiração em Unix time (NULL = não expira)',
+    revoked_at BIGINT NULL COMMENT 'Revogação em Unix time',
+    last_used_at BIGINT NULL COMMENT 'Último uso em Unix time (resolução de 1 minuto)',
+
+    UNIQUE INDEX idx_api_keys_prefix (prefix)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='API keys para autenticação de integrações';
@@ -0,0 +1,2 @@
+-- Rollback: Remove os escopos das API keys
+ALTER TABLE api_keys DROP COLUMN scopes;
@@ -0,0 +1,6 @@
+-- Escopos das API keys
+ALTER TABLE api_keys
+    ADD COLUMN scopes VARCHAR(1024) NOT NULL DEFAULT '' COMMENT 'Escopos concedidos, separados por espaço (ex.: users:read users:write)' AFTER secret_hash;
+
+-- Chaves existentes tinham acesso a todas as rotas protegidas; mantém o comportamento
+UPDATE api_keys SET scopes = 'api_keys:admin users:admin';
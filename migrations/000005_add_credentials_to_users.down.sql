@@ -0,0 +1,6 @@
+-- Rollback: Remove as credenciais dos usuários
+ALTER TABLE users
+    DROP COLUMN last_login_at,
+    DROP COLUMN locked_until,
+    DROP COLUMN failed_logins,
+    DROP COLUMN password_hash;
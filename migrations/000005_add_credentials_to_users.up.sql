@@ -0,0 +1,6 @@
+-- Credenciais e controle de login dos usuários
+ALTER TABLE users
+    ADD COLUMN password_hash VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Hash da senha (argon2id PHC ou bcrypt); vazio = sem senha' AFTER created_at,
+    ADD COLUMN failed_logins INT NOT NULL DEFAULT 0 COMMENT 'Senhas erradas seguidas desde o último login' AFTER password_hash,
+    ADD COLUMN locked_until BIGINT NULL COMMENT 'Login bloqueado até este Unix time' AFTER failed_logins,
+    ADD COLUMN last_login_at BIGINT NULL COMMENT 'Último login em Unix time' AFTER locked_until;
@@ -0,0 +1,3 @@
+-- Rollback: Dropa as tabelas de sessões
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,24 @@
+-- Criação das tabelas de sessões de login e da família de refresh tokens
+CREATE TABLE IF NOT EXISTS sessions (
+    id VARCHAR(36) NOT NULL PRIMARY KEY COMMENT 'UUID da sessão',
+    user_id VARCHAR(36) NOT NULL COMMENT 'Usuário dono da sessão',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'User-Agent do último uso',
+    ip VARCHAR(45) NOT NULL DEFAULT '' COMMENT 'IP do último uso',
+    token_hash CHAR(64) NOT NULL COMMENT 'SHA-256 do refresh token vigente',
+    created_at BIGINT NOT NULL COMMENT 'Login em Unix time (base da expiração absoluta)',
+    last_used_at BIGINT NOT NULL COMMENT 'Último refresh em Unix time (base da expiração por inatividade)',
+    revoked_at BIGINT NULL COMMENT 'Revogação em Unix time',
+    revoke_reason VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'Motivo da revogação (logout, revoked, refresh_token_reuse, password_changed)',
+
+    INDEX idx_sessions_user_id (user_id, revoked_at),
+    CONSTRAINT fk_sessions_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Sessões de login dos usuários';
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    token_hash CHAR(64) NOT NULL PRIMARY KEY COMMENT 'SHA-256 de um refresh token emitido para a sessão',
+    session_id VARCHAR(36) NOT NULL COMMENT 'Sessão (família) à qual o token pertence',
+    created_at BIGINT NOT NULL COMMENT 'Emissão em Unix time',
+
+    INDEX idx_refresh_tokens_session_id (session_id),
+    CONSTRAINT fk_refresh_tokens_session FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Refresh tokens já emitidos, usados na detecção de reuso';
@@ -0,0 +1,3 @@
+-- Rollback: Dropa as tabelas de RBAC
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS roles;
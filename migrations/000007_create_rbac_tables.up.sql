@@ -0,0 +1,25 @@
+-- Criação das tabelas de papéis (RBAC) e das atribuições de papéis a usuários
+CREATE TABLE IF NOT EXISTS roles (
+    id VARCHAR(36) NOT NULL PRIMARY KEY COMMENT 'UUID do papel',
+    name VARCHAR(64) NOT NULL COMMENT 'Nome único do papel (ex.: admin, support)',
+    description VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Descrição do papel',
+    permissions TEXT NOT NULL COMMENT 'Permissões <recurso>:<ação> separadas por espaço',
+    created_at BIGINT NOT NULL COMMENT 'Criação em Unix time',
+
+    UNIQUE INDEX idx_roles_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Papéis e suas permissões';
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    role_id VARCHAR(36) NOT NULL COMMENT 'Papel atribuído',
+    user_id VARCHAR(36) NOT NULL COMMENT 'Usuário que recebeu o papel',
+    created_at BIGINT NOT NULL COMMENT 'Atribuição em Unix time',
+
+    PRIMARY KEY (role_id, user_id),
+    INDEX idx_user_roles_user_id (user_id),
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Papéis atribuídos a cada usuário';
+
+-- Papel inicial com administração de usuários e papéis
+INSERT INTO roles (id, name, description, permissions, created_at)
+VALUES (UUID(), 'admin', 'Administração de usuários e papéis', 'roles:admin users:admin', UNIX_TIMESTAMP());
@@ -0,0 +1,2 @@
+-- Rollback: Dropa a tabela de Idempotency-Key
+DROP TABLE IF EXISTS idempotency_keys;
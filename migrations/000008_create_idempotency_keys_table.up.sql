@@ -0,0 +1,12 @@
+-- Criação da tabela de respostas guardadas por Idempotency-Key
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id CHAR(64) NOT NULL PRIMARY KEY COMMENT 'SHA-256 da chave com o principal, o método e o caminho',
+    request_hash CHAR(64) NOT NULL COMMENT 'SHA-256 do corpo da requisição original',
+    status_code INT NOT NULL DEFAULT 0 COMMENT 'Status da resposta; 0 = requisição em andamento',
+    headers TEXT NULL COMMENT 'Headers da resposta repetidos no replay, em JSON',
+    body MEDIUMBLOB NULL COMMENT 'Corpo da resposta original',
+    created_at BIGINT NOT NULL COMMENT 'Primeira requisição em Unix time',
+    expires_at BIGINT NOT NULL COMMENT 'Expiração da chave em Unix time',
+
+    INDEX idx_idempotency_keys_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Respostas das requisições com Idempotency-Key';
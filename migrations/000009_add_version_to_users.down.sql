@@ -0,0 +1,3 @@
+-- Rollback: Remove a versão dos usuários
+ALTER TABLE users
+    DROP COLUMN version;
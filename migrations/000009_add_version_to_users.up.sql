@@ -0,0 +1,3 @@
+-- Versão dos usuários para controle de concorrência otimista (ETag/If-Match)
+ALTER TABLE users
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1 COMMENT 'Incrementada a cada gravação; a atualização só vale se a versão não mudou' AFTER last_login_at;
@@ -0,0 +1,6 @@
+-- Rollback: Apaga os usuários excluídos e remove a exclusão lógica
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+ALTER TABLE users
+    DROP INDEX idx_users_deleted_at,
+    DROP COLUMN deleted_at,
+    MODIFY COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT 'Status do usuário (active/inactive)';
@@ -0,0 +1,5 @@
+-- Exclusão lógica de usuários
+ALTER TABLE users
+    MODIFY COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT 'Status do usuário (active/inactive/deleted)',
+    ADD COLUMN deleted_at BIGINT NULL COMMENT 'Exclusão lógica em Unix time; NULL = não excluído' AFTER last_login_at,
+    ADD INDEX idx_users_deleted_at (deleted_at);
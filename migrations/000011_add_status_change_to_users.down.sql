@@ -0,0 +1,8 @@
+-- Rollback: Volta aos status active/inactive/deleted e remove o registro da última mudança
+UPDATE users SET status = 'inactive' WHERE status IN ('suspended', 'locked');
+UPDATE users SET status = 'active' WHERE status = 'pending_verification';
+ALTER TABLE users
+    DROP COLUMN status_changed_at,
+    DROP COLUMN status_changed_by,
+    DROP COLUMN status_reason,
+    MODIFY COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT 'Status do usuário (active/inactive/deleted)';
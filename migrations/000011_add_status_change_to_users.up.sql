@@ -0,0 +1,14 @@
+-- Máquina de estados do usuário: motivo, autor e momento da última mudança de status
+ALTER TABLE users
+    MODIFY COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT 'Status do usuário (pending_verification/active/suspended/locked/deleted)',
+    ADD COLUMN status_reason VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Motivo da última mudança de status' AFTER status,
+    ADD COLUMN status_changed_by VARCHAR(100) NOT NULL DEFAULT '' COMMENT 'Autor da última mudança de status (ex.: user:<id>, api_key:<id>, system)' AFTER status_reason,
+    ADD COLUMN status_changed_at BIGINT NULL COMMENT 'Última mudança de status em Unix time; NULL = nunca mudou' AFTER status_changed_by;
+
+-- Usuários desativados passam a suspensos
+UPDATE users
+SET status = 'suspended',
+    status_reason = 'migrated from inactive',
+    status_changed_by = 'system',
+    status_changed_at = UNIX_TIMESTAMP()
+WHERE status = 'inactive';
@@ -0,0 +1,5 @@
+-- Rollback: Remove a confirmação de e-mail; os usuários pendentes passam a ativos
+UPDATE users SET status = 'active' WHERE status = 'pending_verification';
+ALTER TABLE users
+    DROP COLUMN email_verified_at,
+    MODIFY COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT 'Status do usuário (pending_verification/active/suspended/locked/deleted)';
@@ -0,0 +1,4 @@
+-- Confirmação de e-mail: usuários novos ficam em pending_verification até confirmar
+ALTER TABLE users
+    MODIFY COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending_verification' COMMENT 'Status do usuário (pending_verification/active/suspended/locked/deleted)',
+    ADD COLUMN email_verified_at BIGINT NULL COMMENT 'Confirmação do e-mail atual em Unix time; NULL = não confirmado' AFTER last_login_at;
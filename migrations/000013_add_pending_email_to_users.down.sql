@@ -0,0 +1,4 @@
+-- Rollback: Remove a troca de e-mail pendente
+ALTER TABLE users
+    DROP COLUMN email_change_requested_at,
+    DROP COLUMN pending_email;
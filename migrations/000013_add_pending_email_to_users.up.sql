@@ -0,0 +1,4 @@
+-- Troca de e-mail: o novo endereço fica pendente até ser confirmado pelo link enviado a ele
+ALTER TABLE users
+    ADD COLUMN pending_email VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Novo e-mail aguardando confirmação; vazio = nenhuma troca em andamento' AFTER email,
+    ADD COLUMN email_change_requested_at BIGINT NULL COMMENT 'Pedido da troca de e-mail em Unix time; NULL = nenhuma troca em andamento' AFTER email_verified_at;
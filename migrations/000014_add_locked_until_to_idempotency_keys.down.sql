@@ -0,0 +1,3 @@
+-- Rollback: Remove o prazo da reserva das chaves de idempotência
+ALTER TABLE idempotency_keys
+    DROP COLUMN locked_until;
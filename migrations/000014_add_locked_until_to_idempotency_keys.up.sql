@@ -0,0 +1,3 @@
+-- Prazo da reserva em andamento: uma requisição que não terminou (ex.: instância caiu) libera a chave
+ALTER TABLE idempotency_keys
+    ADD COLUMN locked_until BIGINT NOT NULL DEFAULT 0 COMMENT 'Prazo da requisição em andamento em Unix time; depois dele uma retentativa assume a chave' AFTER expires_at;